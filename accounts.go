@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/pkg/api"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	userPath = "users/%s"
+	// accountsConcurrency is the number of concurrent account requests.
+	accountsConcurrency = 8
+	// accountMaxAge is how long cached accounts are used before they are
+	// fetched again.
+	accountMaxAge = 7 * 24 * time.Hour
+)
+
+// Account is the public profile of a stargazer.
+type Account struct {
+	Login       string    `json:"login"`
+	CreatedAt   time.Time `json:"created_at"`
+	PublicRepos int       `json:"public_repos"`
+	Followers   int       `json:"followers"`
+	Name        string    `json:"name,omitempty"`
+	Company     string    `json:"company,omitempty"`
+	Location    string    `json:"location,omitempty"`
+	Blog        string    `json:"blog,omitempty"`
+	Bio         string    `json:"bio,omitempty"`
+	Twitter     string    `json:"twitter_username,omitempty"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+// accountsPath returns the cache file of the accounts of all stargazers.
+func accountsPath() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "accounts.json"), nil
+}
+
+// loadAccounts reads the cached accounts keyed by lowercased login.
+func loadAccounts() (map[string]Account, error) {
+	accounts := make(map[string]Account)
+	path, err := accountsPath()
+	if err != nil {
+		return accounts, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return accounts, nil
+	}
+	if err != nil {
+		return accounts, err
+	}
+	if err := json.Unmarshal(data, &accounts); err != nil {
+		return make(map[string]Account), err
+	}
+	return accounts, nil
+}
+
+// saveAccounts writes the cached accounts.
+func saveAccounts(accounts map[string]Account) error {
+	path, err := accountsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(accounts)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// FetchAccounts returns the accounts of logins in the same order, using the
+// cached ones fetched less than accountMaxAge ago.
+func FetchAccounts(client api.RESTClient, logins []string) ([]Account, error) {
+	cached, err := loadAccounts()
+	if err != nil {
+		log.Printf("loading accounts cache: %v", err)
+	}
+	accounts := make([]Account, len(logins))
+	missing := make([]int, 0)
+	for i, login := range logins {
+		if a, ok := cached[strings.ToLower(login)]; ok && time.Since(a.FetchedAt) <= accountMaxAge {
+			accounts[i] = a
+		} else {
+			missing = append(missing, i)
+		}
+	}
+	var mu sync.Mutex
+	var errg errgroup.Group
+	errg.SetLimit(accountsConcurrency)
+	for _, i := range missing {
+		i, login := i, logins[i]
+		errg.Go(func() error {
+			var account Account
+			if err := client.Get(fmt.Sprintf(userPath, login), &account); err != nil {
+				return fmt.Errorf("Error fetching account of %s: %w", login, err)
+			}
+			account.FetchedAt = time.Now()
+			accounts[i] = account
+			mu.Lock()
+			cached[strings.ToLower(login)] = account
+			mu.Unlock()
+			return nil
+		})
+	}
+	err = errg.Wait()
+	if err := saveAccounts(cached); err != nil {
+		log.Printf("saving accounts cache: %v", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/aymanbagabas/gh-stars/pkg/starsui"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/spf13/pflag"
+)
+
+const starredPath = "users/%s/starred"
+
+// ActivityMsg holds the repositories starred by a user and when.
+type ActivityMsg struct {
+	// Daily are the repositories starred per day.
+	Daily map[string]int
+	// Repos are the starred repositories of each day.
+	Repos map[string][]string
+	Err   error
+}
+
+// FetchActivity fetches the repositories starred by a user, counted per day
+// in timeZone.
+func FetchActivity(client api.RESTClient, login string) ActivityMsg {
+	p := stars.NewPaginator(client, fmt.Sprintf(starredPath, login), stars.PerPage)
+	msg := ActivityMsg{Daily: make(map[string]int), Repos: make(map[string][]string)}
+	for page := 1; ; page++ {
+		// The star+json media type of the client wraps each repository.
+		var starred []struct {
+			StarredAt time.Time   `json:"starred_at"`
+			Repo      starredRepo `json:"repo"`
+		}
+		ok, err := p.Next(&starred)
+		if err != nil {
+			return ActivityMsg{Err: fmt.Errorf("Error fetching the starred repositories of %s, page %d: %w", login, page, err)}
+		}
+		if !ok {
+			break
+		}
+		for _, s := range starred {
+			day := dayOf(s.StarredAt)
+			msg.Daily[day]++
+			msg.Repos[day] = append(msg.Repos[day], s.Repo.FullName)
+		}
+	}
+	return msg
+}
+
+// Activity shows when a user starred repositories, with the graph and the
+// table of the repository view. Tab switches between them.
+type Activity struct {
+	login     string
+	opts      Options
+	client    api.RESTClient
+	keys      KeyMap
+	spinner   spinner.Model
+	graph     starsui.GraphModel
+	table     starsui.TableModel
+	showTable bool
+	loaded    bool
+	total     int
+	repos     map[string][]string
+	err       error
+	width     int
+	height    int
+}
+
+func NewActivity(login string, opts Options) (*Activity, error) {
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	s := spinner.New(spinner.WithSpinner(spinner.Dot))
+	if opts.ASCII {
+		s.Spinner = spinner.Line
+	}
+	s.Style = opts.Theme.AccentStyle()
+	a := &Activity{
+		login:   login,
+		opts:    opts,
+		client:  client,
+		keys:    opts.KeyMap,
+		spinner: s,
+		graph:   starsui.NewGraphModel(nil),
+		table:   starsui.NewTableModel(table.WithStyles(opts.Theme.TableStyles())),
+	}
+	a.graph.Renderer = opts.Renderer
+	a.graph.Colors = opts.Theme.Colors()
+	a.graph.LogScale = opts.LogScale
+	a.graph.Smoothing = opts.Smoothing
+	a.table.SetFormat(formatNumber, formatDay)
+	a.table.KeyMap = a.keys.Table
+	return a, nil
+}
+
+func (a *Activity) Init() tea.Cmd {
+	client, login := a.client, a.login
+	return tea.Batch(a.spinner.Tick, func() tea.Msg {
+		return FetchActivity(client, login)
+	})
+}
+
+func (a *Activity) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		a.width, a.height = msg.Width, msg.Height
+		a.resize()
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, a.keys.Quit):
+			return a, tea.Quit
+		case key.Matches(msg, a.keys.Section):
+			a.showTable = !a.showTable
+		case key.Matches(msg, a.keys.Columns):
+			a.table.NextColumns()
+		case a.showTable:
+			var cmd tea.Cmd
+			a.table, cmd = a.table.Update(msg)
+			return a, cmd
+		}
+	case ActivityMsg:
+		a.loaded = true
+		a.err = msg.Err
+		a.repos = msg.Repos
+		a.total = 0
+		for _, n := range msg.Daily {
+			a.total += n
+		}
+		a.graph.SetData(msg.Daily)
+		a.graph.Caption = a.caption()
+		a.table.SetData(msg.Daily)
+	case spinner.TickMsg:
+		if a.loaded {
+			return a, nil
+		}
+		var cmd tea.Cmd
+		a.spinner, cmd = a.spinner.Update(msg)
+		return a, cmd
+	}
+	return a, nil
+}
+
+// resize leaves room for the caption of the table and the repositories of
+// the selected day.
+func (a *Activity) resize() {
+	a.graph.SetSize(a.width, a.height-1)
+	a.table.SetWidth(a.width)
+	a.table.SetHeight(a.height - 4)
+}
+
+func (a *Activity) View() string {
+	if v, ok := tooSmall(a.width, a.height); ok {
+		return v
+	}
+	v := a.render()
+	if a.opts.ASCII {
+		v = asciiReplacer.Replace(v)
+	}
+	return v
+}
+
+// caption describes the stars of the user.
+func (a *Activity) caption() string {
+	return fmt.Sprintf("%s starred %s repositories over time (%s)", a.login, formatNumber(a.total), timeZone)
+}
+
+func (a *Activity) render() string {
+	switch {
+	case !a.loaded:
+		return fmt.Sprintf("\n %s loading the stars of %s...\n", a.spinner.View(), a.login)
+	case a.err != nil:
+		return fmt.Sprintf("\n Error: %s\n", a.err)
+	case a.total == 0:
+		return fmt.Sprintf("\n %s hasn't starred any public repository.\n", a.login)
+	}
+	if !a.showTable {
+		return a.graph.View()
+	}
+	day := a.table.SelectedDay()
+	repos := append([]string(nil), a.repos[day]...)
+	sort.Strings(repos)
+	starred := fmt.Sprintf(" Starred on %s: %s", formatDay(day), strings.Join(repos, ", "))
+	if len(starred) > a.width && a.width > 3 {
+		starred = starred[:a.width-3] + "..."
+	}
+	return a.opts.Theme.AccentStyle().Render(" "+a.caption()) + "\n" + a.table.View() + "\n" + starred
+}
+
+func runActivity(args []string) {
+	flags := pflag.NewFlagSet("activity", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gh stars activity <user> [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Show when a user starred repositories, their own starring habits rather than the stargazers of a repository.\n\n")
+		flags.PrintDefaults()
+	}
+	ui := addUIFlags(flags)
+	smooth := flags.String("smooth", "none", "smoothing of the graph: none, 7d, loess")
+	logScale := flags.Bool("log-scale", false, "plot the graph on a log scale")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(1)
+	}
+	cfg, err := LoadConfig()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	opts, err := ui.Options(cfg)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	opts.LogScale = *logScale
+	opts.Smoothing, err = starsui.ParseSmoothing(*smooth)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	m, err := NewActivity(flags.Arg(0), opts)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	ui.Run(m)
+}
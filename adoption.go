@@ -0,0 +1,282 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/go-gh/pkg/api"
+)
+
+const (
+	contentsPath = "repos/%s/contents/%s"
+	// The dependency graph API only lists the dependencies of a repository,
+	// its dependents are scraped from the page listing them.
+	dependentsURL   = "https://github.com/%s/network/dependents"
+	npmDownloadsURL = "https://api.npmjs.org/downloads/range/last-year/%s"
+	// pkg.go.dev has no download counts, only the packages importing a
+	// module.
+	goImportedByURL = "https://pkg.go.dev/%s?tab=importedby"
+	// maxScrapedPage caps the size of the scraped pages.
+	maxScrapedPage = 4 << 20
+	// adoptionMonths is the number of months of the downloads chart.
+	adoptionMonths = 12
+)
+
+var (
+	dependentsPattern = regexp.MustCompile(`([\d,]+)\s+Repositories`)
+	importedByPattern = regexp.MustCompile(`Imported by:?\s*(?:<[^>]*>\s*)*([\d,]+)`)
+)
+
+// AdoptionMsg holds the adoption of a repository beyond its stars. Counts
+// are -1 when unknown.
+type AdoptionMsg struct {
+	// Dependents is the number of repositories depending on the repository.
+	Dependents int
+	// NPM is the npm package of the repository, empty if there's none.
+	NPM string
+	// Downloads are the npm downloads per day of the last year.
+	Downloads map[string]int
+	// Module is the Go module of the repository, empty if there's none.
+	Module string
+	// Importers is the number of packages importing the Go module.
+	Importers int
+	Err       error
+}
+
+// adoption shows the dependents of the repository and the downloads of its
+// packages, for library authors to see adoption beyond stars.
+type adoption struct {
+	loading bool
+	loaded  bool
+	msg     AdoptionMsg
+}
+
+// Load fetches the adoption of the repository unless it was already
+// fetched.
+func (a *adoption) Load(r *Repo) tea.Cmd {
+	if a.loading || a.loaded {
+		return nil
+	}
+	if r.offline {
+		a.msg.Err = errOffline
+		return nil
+	}
+	a.loading = true
+	client, name := r.client, r.name
+	return func() tea.Msg {
+		return FetchAdoption(client, name, nil)
+	}
+}
+
+// Preload fetches the adoption while the stargazers are loading, reporting
+// each metric done to progress.
+func (a *adoption) Preload(r *Repo, progress *loadProgress) tea.Cmd {
+	a.loading = true
+	client, name := r.client, r.name
+	return func() tea.Msg {
+		return FetchAdoption(client, name, func(metric string) {
+			progress.Set(metric, 1, 1)
+		})
+	}
+}
+
+// SetAdoption stores the fetched adoption.
+func (a *adoption) SetAdoption(msg AdoptionMsg) {
+	a.loading = false
+	a.loaded = true
+	a.msg = msg
+}
+
+// FetchAdoption fetches the dependents of a repository, and the downloads
+// of its npm package or the importers of its Go module found at its root,
+// calling done when set after the dependents and after the packages.
+func FetchAdoption(client api.RESTClient, name string, done func(metric string)) AdoptionMsg {
+	msg := AdoptionMsg{Dependents: -1, Importers: -1}
+	var err error
+	if msg.Dependents, err = FetchDependents(name); err != nil {
+		return AdoptionMsg{Err: err}
+	}
+	if done != nil {
+		done(metricDependents)
+	}
+	pkg, err := fetchRootFile(client, name, "package.json")
+	if err != nil {
+		return AdoptionMsg{Err: err}
+	}
+	if pkg != "" {
+		var manifest struct {
+			Name    string `json:"name"`
+			Private bool   `json:"private"`
+		}
+		if json.Unmarshal([]byte(pkg), &manifest) == nil && manifest.Name != "" && !manifest.Private {
+			msg.NPM = manifest.Name
+			if msg.Downloads, err = FetchNPMDownloads(msg.NPM); err != nil {
+				return AdoptionMsg{Err: err}
+			}
+		}
+	}
+	mod, err := fetchRootFile(client, name, "go.mod")
+	if err != nil {
+		return AdoptionMsg{Err: err}
+	}
+	for _, line := range strings.Split(mod, "\n") {
+		if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "module" {
+			msg.Module = strings.Trim(fields[1], `"`)
+			break
+		}
+	}
+	if msg.Module != "" {
+		if msg.Importers, err = FetchGoImporters(msg.Module); err != nil {
+			return AdoptionMsg{Err: err}
+		}
+	}
+	if done != nil {
+		done(metricDownloads)
+	}
+	return msg
+}
+
+// fetchRootFile returns the content of a file at the root of a repository,
+// or an empty string if there's none.
+func fetchRootFile(client api.RESTClient, name, path string) (string, error) {
+	var file struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	if err := client.Get(fmt.Sprintf(contentsPath, name, path), &file); err != nil {
+		var httpErr api.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+			return "", nil
+		}
+		return "", fmt.Errorf("Error fetching %s: %w", path, err)
+	}
+	if file.Encoding != "base64" {
+		return file.Content, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(file.Content, "\n", ""))
+	if err != nil {
+		return "", fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return string(data), nil
+}
+
+// fetchPage returns the HTML of a page, or an empty string if it's not
+// found.
+func fetchPage(u string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "gh-stars")
+	resp, err := hintsClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxScrapedPage))
+	return string(data), err
+}
+
+// scrapeCount returns the first count matched by pattern in a page, or -1
+// if the page or the count isn't found.
+func scrapeCount(u string, pattern *regexp.Regexp) (int, error) {
+	page, err := fetchPage(u)
+	if err != nil {
+		return -1, err
+	}
+	m := pattern.FindStringSubmatch(page)
+	if m == nil {
+		return -1, nil
+	}
+	return strconv.Atoi(strings.ReplaceAll(m[1], ",", ""))
+}
+
+// FetchDependents returns the number of repositories depending on a
+// repository according to its dependency graph, or -1 if it isn't shown.
+func FetchDependents(name string) (int, error) {
+	n, err := scrapeCount(fmt.Sprintf(dependentsURL, name), dependentsPattern)
+	if err != nil {
+		return -1, fmt.Errorf("fetching the dependents: %w", err)
+	}
+	return n, nil
+}
+
+// FetchGoImporters returns the number of packages importing a Go module
+// according to pkg.go.dev, or -1 if it isn't listed.
+func FetchGoImporters(module string) (int, error) {
+	n, err := scrapeCount(fmt.Sprintf(goImportedByURL, module), importedByPattern)
+	if err != nil {
+		return -1, fmt.Errorf("fetching the importers of %s: %w", module, err)
+	}
+	return n, nil
+}
+
+// FetchNPMDownloads returns the downloads per day of an npm package during
+// the last year.
+func FetchNPMDownloads(pkg string) (map[string]int, error) {
+	var result struct {
+		Downloads []struct {
+			Day       string `json:"day"`
+			Downloads int    `json:"downloads"`
+		} `json:"downloads"`
+	}
+	if err := getJSON(fmt.Sprintf(npmDownloadsURL, pkg), &result); err != nil {
+		return nil, fmt.Errorf("fetching the npm downloads of %s: %w", pkg, err)
+	}
+	daily := make(map[string]int, len(result.Downloads))
+	for _, d := range result.Downloads {
+		daily[d.Day] = d.Downloads
+	}
+	return daily, nil
+}
+
+func (a *adoption) View(r *Repo) string {
+	switch {
+	case a.loading:
+		return fmt.Sprintf("\n %s loading dependents and packages...\n", r.spinner.View())
+	case a.msg.Err != nil:
+		return fmt.Sprintf("\n Error: %s", a.msg.Err)
+	}
+	lines := []string{"", r.theme.AccentStyle().Render(fmt.Sprintf(" Adoption of %s beyond its %s stars", r.name, formatNumber(r.stars))), ""}
+	if a.msg.Dependents >= 0 {
+		lines = append(lines, fmt.Sprintf(" Used by %d repositories according to the dependency graph", a.msg.Dependents))
+	} else {
+		lines = append(lines, " No dependents shown, the dependency graph may be disabled")
+	}
+	if a.msg.Module != "" {
+		if a.msg.Importers >= 0 {
+			lines = append(lines, fmt.Sprintf(" Go module %s, imported by %d packages on pkg.go.dev", a.msg.Module, a.msg.Importers))
+		} else {
+			lines = append(lines, fmt.Sprintf(" Go module %s, not listed on pkg.go.dev", a.msg.Module))
+		}
+	}
+	if a.msg.NPM == "" {
+		if a.msg.Module == "" {
+			lines = append(lines, " No npm package or Go module found at the root of the repository")
+		}
+		return strings.Join(lines, "\n")
+	}
+	months, counts := monthlyCounts(a.msg.Downloads, time.Now().In(timeZone), adoptionMonths)
+	var total int
+	for _, n := range counts {
+		total += n
+	}
+	lines = append(lines, fmt.Sprintf(" npm package %s, %d downloads in the last %d months", a.msg.NPM, total, adoptionMonths), "")
+	lines = append(lines, barChart(r.theme, months, counts, r.width)...)
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// agesSampleSize is the number of most recent stargazers whose account ages
+// are plotted. It's the notable sample size so their accounts are cached.
+const agesSampleSize = notableSampleSize
+
+// ageBucket is a range of account ages in the histogram.
+type ageBucket struct {
+	label string
+	// below is the exclusive upper bound of the bucket, zero for the last
+	// one.
+	below time.Duration
+}
+
+var ageBuckets = []ageBucket{
+	{"< 1 day", 24 * time.Hour},
+	{"< 1 week", 7 * 24 * time.Hour},
+	{"< 1 month", 30 * 24 * time.Hour},
+	{"< 6 months", 182 * 24 * time.Hour},
+	{"< 1 year", 365 * 24 * time.Hour},
+	{"1-2 years", 2 * 365 * 24 * time.Hour},
+	{"2-5 years", 5 * 365 * 24 * time.Hour},
+	{"5+ years", 0},
+}
+
+// AgesMsg holds the accounts of the sampled stargazers with their starred
+// dates.
+type AgesMsg struct {
+	Accounts  []Account
+	StarredAt []time.Time
+	Err       error
+}
+
+// ages plots a histogram of how old the accounts of the stargazers were
+// when they starred the repository. Organic growth spreads over years, bot
+// rings pile up in the first buckets.
+type ages struct {
+	loading bool
+	loaded  bool
+	sampled int
+	counts  []int
+	err     error
+}
+
+// Load fetches the accounts of the most recent stargazers unless they were
+// already fetched.
+func (a *ages) Load(r *Repo) tea.Cmd {
+	if a.loading || a.loaded || len(r.logins) == 0 {
+		return nil
+	}
+	if r.offline {
+		a.err = errOffline
+		return nil
+	}
+	// Histories cached before starred dates were stored don't have them.
+	if len(r.starredAt) != len(r.logins) {
+		a.err = fmt.Errorf("the starred dates of the stargazers aren't cached, fetch the repository again")
+		return nil
+	}
+	a.loading = true
+	logins, starredAt := r.logins, r.starredAt
+	if len(logins) > agesSampleSize {
+		logins = logins[len(logins)-agesSampleSize:]
+		starredAt = starredAt[len(starredAt)-agesSampleSize:]
+	}
+	client := r.client
+	return func() tea.Msg {
+		accounts, err := FetchAccounts(client, logins)
+		return AgesMsg{Accounts: accounts, StarredAt: starredAt, Err: err}
+	}
+}
+
+// SetAccounts counts the sampled accounts per age bucket.
+func (a *ages) SetAccounts(msg AgesMsg) {
+	a.loading = false
+	a.loaded = true
+	a.err = msg.Err
+	a.sampled = len(msg.Accounts)
+	a.counts = ageHistogram(msg.Accounts, msg.StarredAt)
+}
+
+// ageHistogram returns the number of accounts per age bucket when they
+// starred.
+func ageHistogram(accounts []Account, starredAt []time.Time) []int {
+	counts := make([]int, len(ageBuckets))
+	for i, account := range accounts {
+		age := starredAt[i].Sub(account.CreatedAt)
+		b := len(ageBuckets) - 1
+		for j, bucket := range ageBuckets[:b] {
+			if age < bucket.below {
+				b = j
+				break
+			}
+		}
+		counts[b]++
+	}
+	return counts
+}
+
+func (a *ages) View(r *Repo) string {
+	switch {
+	case a.loading:
+		return fmt.Sprintf("\n %s loading stargazer accounts...\n", r.spinner.View())
+	case a.err != nil:
+		return fmt.Sprintf("\n Error: %s", a.err)
+	case len(r.logins) == 0 || a.sampled == 0:
+		return "\n No stargazers found.\n"
+	}
+	labels := make([]string, len(ageBuckets))
+	for i, bucket := range ageBuckets {
+		labels[i] = bucket.label
+	}
+	lines := append([]string{""}, barChart(r.theme, labels, a.counts, r.width)...)
+	lines = append(lines, "", r.theme.AccentStyle().Render(fmt.Sprintf(" Account age when starring of the %d most recent stargazers", a.sampled)))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/go-gh/pkg/term"
+)
+
+// Alerts emitted when the stargazers are loaded.
+const (
+	alertNone = "none"
+	alertBell = "bell"
+	// alertOSC9 is the desktop notification escape sequence of iTerm2,
+	// also supported by kitty, WezTerm and Windows Terminal.
+	alertOSC9 = "osc9"
+)
+
+// ParseAlert parses the alert emitted when the stargazers are loaded.
+func ParseAlert(s string) (string, error) {
+	switch s = strings.ToLower(s); s {
+	case "", alertNone:
+		return alertNone, nil
+	case alertBell, alertOSC9:
+		return s, nil
+	}
+	return "", fmt.Errorf("unknown alert %q, expected none, bell, or osc9", s)
+}
+
+// alertSequence returns the escape sequence of an alert with message.
+func alertSequence(alert, message string) string {
+	switch alert {
+	case alertBell:
+		return "\a"
+	case alertOSC9:
+		seq := "\x1b]9;" + message + "\a"
+		// tmux only passes escape sequences through to the terminal
+		// wrapped, with their escapes doubled.
+		if os.Getenv("TMUX") != "" {
+			seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+		}
+		return seq
+	}
+	return ""
+}
+
+// alertCmd returns a command emitting an alert with message on the
+// terminal, nil if there's none. It's written to stderr so it doesn't
+// interleave with the rendering of the view on stdout.
+func alertCmd(alert, message string) tea.Cmd {
+	seq := alertSequence(alert, message)
+	if seq == "" || !term.IsTerminal(os.Stderr) {
+		return nil
+	}
+	return func() tea.Msg {
+		fmt.Fprint(os.Stderr, seq)
+		return nil
+	}
+}
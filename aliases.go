@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/cli/go-gh/pkg/api"
+)
+
+// aliasesOf returns the alias repositories of a repository declared in the
+// config file.
+func aliasesOf(aliases map[string][]string, name string) []string {
+	for canonical, list := range aliases {
+		if strings.EqualFold(canonical, name) {
+			return list
+		}
+	}
+	return nil
+}
+
+// mergeAliases returns the history of a repository with the stargazers of
+// its aliases summed per day, and the stars of each source. Aliases are
+// cached like any other repository.
+func mergeAliases(client api.RESTClient, entry *stars.History, aliases []string, offline bool) (*stars.History, map[string]int, error) {
+	sources := map[string]int{entry.Name: entry.Stars}
+	if len(aliases) == 0 {
+		return entry, sources, nil
+	}
+	entries := []*stars.History{entry}
+	for _, alias := range aliases {
+		e, err := LoadHistory(client, alias, time.Hour, offline)
+		if err != nil {
+			return nil, nil, fmt.Errorf("loading alias %s: %w", alias, err)
+		}
+		sources[alias] = e.Stars
+		entries = append(entries, e)
+	}
+	merged := SumHistories(entry.Name, entries)
+	merged.Logins = entry.Logins
+	merged.StarredAt = entry.StarredAt
+	merged.Unstars = entry.Unstars
+	return merged, sources, nil
+}
+
+// sourcesCaption attributes the stars of a merged repository to its sources.
+func sourcesCaption(sources map[string]int) string {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return sources[names[i]] > sources[names[j]]
+	})
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s %d", name, sources[name])
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cli/go-gh/pkg/api"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	userStarredPath = "users/%s/starred?per_page=100"
+	// alsoSampleSize is the number of most recent stargazers whose starred
+	// repositories are fetched.
+	alsoSampleSize = 50
+	// alsoTop is the number of repositories, languages, and topics listed.
+	alsoTop = 10
+)
+
+// starredRepo is a repository starred by a stargazer.
+type starredRepo struct {
+	FullName string   `json:"full_name"`
+	Language string   `json:"language"`
+	Topics   []string `json:"topics"`
+}
+
+// AlsoMsg holds how many of the sampled stargazers starred each other
+// repository, language, and topic.
+type AlsoMsg struct {
+	Sampled   int
+	Repos     map[string]int
+	Languages map[string]int
+	Topics    map[string]int
+	Err       error
+}
+
+// also summarizes what the stargazers of the repository also star: people
+// who starred this also star X.
+type also struct {
+	loading bool
+	loaded  bool
+	msg     AlsoMsg
+}
+
+// Load fetches the starred repositories of the most recent stargazers unless
+// they were already fetched.
+func (a *also) Load(r *Repo) tea.Cmd {
+	if a.loading || a.loaded || len(r.logins) == 0 {
+		return nil
+	}
+	if r.offline {
+		a.msg.Err = errOffline
+		return nil
+	}
+	a.loading = true
+	logins := r.logins
+	if len(logins) > alsoSampleSize {
+		logins = logins[len(logins)-alsoSampleSize:]
+	}
+	client, name := r.client, r.name
+	return func() tea.Msg {
+		return FetchAlsoStarred(client, name, logins)
+	}
+}
+
+// FetchAlsoStarred counts the stargazers among logins starring each other
+// repository than name, and each language and topic of those repositories,
+// from their 100 most recent stars.
+func FetchAlsoStarred(client api.RESTClient, name string, logins []string) AlsoMsg {
+	msg := AlsoMsg{
+		Sampled:   len(logins),
+		Repos:     make(map[string]int),
+		Languages: make(map[string]int),
+		Topics:    make(map[string]int),
+	}
+	var mu sync.Mutex
+	var errg errgroup.Group
+	errg.SetLimit(accountsConcurrency)
+	for _, login := range logins {
+		login := login
+		errg.Go(func() error {
+			// The star+json media type of the client wraps each repository.
+			var starred []struct {
+				Repo starredRepo `json:"repo"`
+			}
+			if err := client.Get(fmt.Sprintf(userStarredPath, login), &starred); err != nil {
+				return fmt.Errorf("Error fetching the starred repositories of %s: %w", login, err)
+			}
+			// Count each language and topic once per stargazer.
+			languages, topics := make(map[string]bool), make(map[string]bool)
+			mu.Lock()
+			defer mu.Unlock()
+			for _, s := range starred {
+				if s.Repo.FullName == "" || strings.EqualFold(s.Repo.FullName, name) {
+					continue
+				}
+				msg.Repos[s.Repo.FullName]++
+				if s.Repo.Language != "" {
+					languages[s.Repo.Language] = true
+				}
+				for _, topic := range s.Repo.Topics {
+					topics[topic] = true
+				}
+			}
+			for language := range languages {
+				msg.Languages[language]++
+			}
+			for topic := range topics {
+				msg.Topics[topic]++
+			}
+			return nil
+		})
+	}
+	msg.Err = errg.Wait()
+	return msg
+}
+
+// SetStarred stores the summary of the starred repositories.
+func (a *also) SetStarred(msg AlsoMsg) {
+	a.loading = false
+	a.loaded = true
+	a.msg = msg
+}
+
+// topCounts returns the n keys of counts with the highest counts, formatted
+// with their share of the sampled stargazers.
+func topCounts(counts map[string]int, n, sampled int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+	lines := make([]string, len(keys))
+	for i, k := range keys {
+		lines[i] = fmt.Sprintf("%3.0f%% %s", float64(counts[k])/float64(sampled)*100, k)
+	}
+	return lines
+}
+
+func (a *also) View(r *Repo) string {
+	switch {
+	case a.loading:
+		return fmt.Sprintf("\n %s loading starred repositories...\n", r.spinner.View())
+	case a.msg.Err != nil:
+		return fmt.Sprintf("\n Error: %s", a.msg.Err)
+	case len(r.logins) == 0 || a.msg.Sampled == 0:
+		return "\n No stargazers found.\n"
+	}
+	column := func(title string, counts map[string]int) string {
+		lines := append([]string{r.theme.AccentStyle().Bold(true).Render(title)}, topCounts(counts, alsoTop, a.msg.Sampled)...)
+		return lipgloss.NewStyle().PaddingRight(4).Render(strings.Join(lines, "\n"))
+	}
+	columns := lipgloss.JoinHorizontal(lipgloss.Top,
+		column("Repositories", a.msg.Repos),
+		column("Languages", a.msg.Languages),
+		column("Topics", a.msg.Topics),
+	)
+	note := fmt.Sprintf(" Also starred by the %d most recent stargazers, from their last 100 stars", a.msg.Sampled)
+	return "\n" + lipgloss.NewStyle().PaddingLeft(1).Render(columns) + "\n\n" + r.theme.AccentStyle().Render(note)
+}
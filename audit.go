@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// auditMaxSize is the size of the audit log above which it's rotated. The
+// previous log is kept with a .1 suffix.
+const auditMaxSize = 1 << 20
+
+// AuditRecord is an API request recorded in the audit log.
+type AuditRecord struct {
+	Time     time.Time `json:"time"`
+	Method   string    `json:"method"`
+	Endpoint string    `json:"endpoint"`
+	// Status is 0 when the request failed without a response.
+	Status int `json:"status"`
+	// Cost is the number of requests counted against the rate limit of
+	// Resource, REST requests cost one.
+	Cost      int    `json:"cost"`
+	Resource  string `json:"resource,omitempty"`
+	Remaining int    `json:"remaining"`
+}
+
+var auditMu sync.Mutex
+
+func auditPath() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "audit.log"), nil
+}
+
+// audit appends the request and its response to the audit log.
+func audit(req *http.Request, resp *http.Response) {
+	record := AuditRecord{
+		Time:     time.Now(),
+		Method:   req.Method,
+		Endpoint: req.URL.RequestURI(),
+		Cost:     1,
+	}
+	if resp != nil {
+		record.Status = resp.StatusCode
+		record.Resource = resp.Header.Get("X-RateLimit-Resource")
+		record.Remaining, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	}
+	if err := appendAudit(record); err != nil {
+		log.Printf("writing audit log: %v", err)
+	}
+}
+
+func appendAudit(record AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	path, err := auditPath()
+	if err != nil {
+		return err
+	}
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() >= auditMaxSize {
+		if err := os.Rename(path, path+".1"); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadAudit returns the records of the audit log and of the rotated one,
+// oldest first.
+func ReadAudit() ([]AuditRecord, error) {
+	path, err := auditPath()
+	if err != nil {
+		return nil, err
+	}
+	records := make([]AuditRecord, 0)
+	for _, p := range []string{path + ".1", path} {
+		f, err := os.Open(p)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var record AuditRecord
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				continue
+			}
+			records = append(records, record)
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
+
+func runAudit(args []string) {
+	flags := pflag.NewFlagSet("audit", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gh stars audit [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "List the API requests made by gh-stars.\n\n")
+		flags.PrintDefaults()
+	}
+	since := flags.Duration("since", 0, "only list the requests of this last period, e.g. 24h")
+	failed := flags.Bool("errors", false, "only list the failed requests")
+	flags.Parse(args)
+	records, err := ReadAudit()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var requests, cost, errs int
+	for _, r := range records {
+		if *since > 0 && time.Since(r.Time) > *since {
+			continue
+		}
+		if *failed && r.Status > 0 && r.Status < 400 {
+			continue
+		}
+		status := "-"
+		if r.Status > 0 {
+			status = strconv.Itoa(r.Status)
+		}
+		if r.Status == 0 || r.Status >= 400 {
+			errs++
+		}
+		requests++
+		cost += r.Cost
+		fmt.Printf("%s  %-6s %3s  %-8s %5d  %s\n", r.Time.Local().Format("2006-01-02 15:04:05"), r.Method, status, r.Resource, r.Remaining, r.Endpoint)
+	}
+	fmt.Printf("\n%d requests, %d counted against the rate limit, %d failed\n", requests, cost, errs)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/spf13/pflag"
+)
+
+func cacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh-stars"), nil
+}
+
+// historyCache returns the cache of the stargazers histories.
+func historyCache() (stars.Cache, error) {
+	dir, err := cacheDir()
+	return stars.Cache{Dir: dir}, err
+}
+
+// LoadCache reads the cached history of a repository, or of the repository
+// it was renamed to, counted per day in timeZone. It returns nil if the
+// repository isn't cached.
+func LoadCache(name string) (*stars.History, error) {
+	cache, err := historyCache()
+	if err != nil {
+		return nil, err
+	}
+	entry, err := cache.Lookup(name)
+	if err != nil || entry == nil {
+		return entry, err
+	}
+	entry.Rebucket(dailyBucketer())
+	return entry, nil
+}
+
+// SaveCache writes the cached history of a repository.
+func SaveCache(entry *stars.History) error {
+	cache, err := historyCache()
+	if err != nil {
+		return err
+	}
+	return cache.Save(entry)
+}
+
+// MigrateCache moves the cached history of a renamed repository to its
+// canonical name. If both names are cached, the histories are merged.
+func MigrateCache(from, to string) error {
+	cache, err := historyCache()
+	if err != nil {
+		return err
+	}
+	return cache.Migrate(from, to)
+}
+
+// MergeCache merges an imported history into the cached history of its
+// repository.
+func MergeCache(imported *stars.History) (*stars.History, error) {
+	cache, err := historyCache()
+	if err != nil {
+		return nil, err
+	}
+	return cache.Merge(imported)
+}
+
+// ClearCache removes the cached histories of repositories, or the whole
+// cache if no repository is given. The audit log is kept, it records the
+// requests made rather than caching their responses.
+func ClearCache(names ...string) error {
+	cache, err := historyCache()
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		audit, err := auditPath()
+		if err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(cache.Dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			path := filepath.Join(cache.Dir, e.Name())
+			if path == audit || path == audit+".1" {
+				continue
+			}
+			if err := os.RemoveAll(path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, name := range names {
+		if err := cache.Remove(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runCache(args []string) {
+	flags := pflag.NewFlagSet("cache", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gh stars cache clear [repository]...\n\n")
+		fmt.Fprintf(os.Stderr, "Remove the cached data of repositories, or all of it but the audit log.\n")
+	}
+	flags.Parse(args)
+	if flags.NArg() == 0 || flags.Arg(0) != "clear" {
+		flags.Usage()
+		os.Exit(1)
+	}
+	if err := ClearCache(flags.Args()[1:]...); err != nil {
+		log.Fatalln(err)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+)
+
+// Campaign is a named date range of promotion of a repository, like a
+// conference talk or a blog post, that stars are attributed to.
+type Campaign struct {
+	Name string `yaml:"name"`
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+}
+
+// Validate returns an error if the campaign has no name or its dates aren't
+// a range.
+func (c Campaign) Validate() error {
+	if c.Name == "" {
+		return fmt.Errorf("campaign from %s has no name", c.From)
+	}
+	for _, date := range []string{c.From, c.To} {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return fmt.Errorf("campaign %q: invalid date %q, expected YYYY-MM-DD", c.Name, date)
+		}
+	}
+	if c.From > c.To {
+		return fmt.Errorf("campaign %q ends before it starts", c.Name)
+	}
+	return nil
+}
+
+// campaignsOf returns the campaigns of a repository sorted by start date.
+func campaignsOf(campaigns map[string][]Campaign, name string) []Campaign {
+	for repo, list := range campaigns {
+		if strings.EqualFold(repo, name) {
+			sorted := append([]Campaign(nil), list...)
+			sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].From < sorted[j].From })
+			return sorted
+		}
+	}
+	return nil
+}
+
+// Attribution is the stars attributed to a campaign.
+type Attribution struct {
+	Campaign
+	Stars int
+	// Before are the stars of the same number of days before the campaign,
+	// its baseline.
+	Before int
+}
+
+// Days returns the number of days of the campaign.
+func (a Attribution) Days() int {
+	return len(dayRange(a.From, a.To))
+}
+
+// AttributeStars attributes the stars of each day to the campaign covering
+// it that started last, like last-touch attribution, and returns the stars
+// of each campaign and the stars outside of every campaign. campaigns must
+// be sorted by start date.
+func AttributeStars(daily map[string]int, campaigns []Campaign) ([]Attribution, int) {
+	attributed := make([]Attribution, len(campaigns))
+	owner := make(map[string]int)
+	for i, c := range campaigns {
+		attributed[i].Campaign = c
+		days := dayRange(c.From, c.To)
+		for _, day := range days {
+			owner[day] = i
+		}
+		start, _ := time.Parse("2006-01-02", c.From)
+		for _, day := range dayRange(dayOf(start.AddDate(0, 0, -len(days))), dayOf(start.AddDate(0, 0, -1))) {
+			attributed[i].Before += daily[day]
+		}
+	}
+	var untagged int
+	for day, n := range daily {
+		if i, ok := owner[day]; ok {
+			attributed[i].Stars += n
+		} else {
+			untagged += n
+		}
+	}
+	return attributed, untagged
+}
+
+// campaigns attributes the stars to the campaigns of the repository in the
+// config file.
+type campaigns struct {
+	list     []Campaign
+	tagged   int
+	untagged int
+	table    table.Model
+}
+
+func newCampaigns(theme Theme, list []Campaign) campaigns {
+	return campaigns{
+		list: list,
+		table: table.New(
+			table.WithColumns(
+				[]table.Column{
+					{Title: "Campaign", Width: 24},
+					{Title: "Dates", Width: 23},
+					{Title: "Stars", Width: 8},
+					{Title: "Per day", Width: 8},
+					{Title: "Lift", Width: 8},
+					{Title: "Share", Width: 8},
+				},
+			),
+			table.WithFocused(true),
+			table.WithStyles(theme.TableStyles()),
+		),
+	}
+}
+
+// SetSize sets the size of the campaigns table.
+func (c *campaigns) SetSize(width, height int) {
+	c.table.SetWidth(width)
+	// Leave room for the note.
+	c.table.SetHeight(height - 3)
+}
+
+// SetData attributes the daily stargazers to the campaigns.
+func (c *campaigns) SetData(daily map[string]int) {
+	attributed, untagged := AttributeStars(daily, c.list)
+	c.tagged, c.untagged = 0, untagged
+	for _, a := range attributed {
+		c.tagged += a.Stars
+	}
+	total := c.tagged + c.untagged
+	rows := make([]table.Row, len(attributed))
+	for i, a := range attributed {
+		days := a.Days()
+		lift := "-"
+		if a.Before > 0 {
+			lift = fmt.Sprintf("%.1fx", float64(a.Stars)/float64(a.Before))
+		}
+		share := "-"
+		if total > 0 {
+			share = fmt.Sprintf("%.1f%%", float64(a.Stars)*100/float64(total))
+		}
+		rows[i] = table.Row{
+			a.Name,
+			fmt.Sprintf("%s..%s", a.From, a.To),
+			fmt.Sprintf("%d", a.Stars),
+			fmt.Sprintf("%.1f", float64(a.Stars)/float64(days)),
+			lift,
+			share,
+		}
+	}
+	c.table.SetRows(rows)
+}
+
+func (c *campaigns) View(r *Repo) string {
+	if len(c.list) == 0 {
+		return fmt.Sprintf("\n No campaigns for %s, tag date ranges with names in the campaigns of the config file.\n", r.name)
+	}
+	note := fmt.Sprintf(" %d stars during campaigns, %d outside of them. Days of overlapping campaigns count for the latest one, lift compares with the same number of days before.", c.tagged, c.untagged)
+	return "\n" + c.table.View() + "\n" + r.theme.AccentStyle().Render(note)
+}
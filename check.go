@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/spf13/pflag"
+)
+
+// UnstarWaves returns the messages describing the unstar waves of the recent
+// days of a repository, oldest first.
+func UnstarWaves(entry *stars.History, now time.Time) []string {
+	days := unstarAlerts(entry.Unstars, now)
+	messages := make([]string, len(days))
+	for i, day := range days {
+		messages[i] = fmt.Sprintf("%s lost %d stargazers on %s", entry.Name, entry.Unstars[day], day)
+	}
+	return messages
+}
+
+func runCheck(args []string) {
+	flags := pflag.NewFlagSet("check", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gh stars check <repository>... [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Fetch the stars of repositories and exit with status 1 if more than %d\n", unstarAlertThreshold)
+		fmt.Fprintf(os.Stderr, "stargazers were lost in a day over the last %d days, e.g. in CI.\n\n", unstarAlertDays)
+		flags.PrintDefaults()
+	}
+	offline := flags.Bool("offline", false, "check the cached histories without making API calls")
+	addTokenFlag(flags)
+	addMaxRequestsFlag(flags)
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(1)
+	}
+	var client api.RESTClient
+	if !*offline {
+		var err error
+		client, err = NewClient()
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
+	var waves int
+	for _, name := range flags.Args() {
+		var entry *stars.History
+		var err error
+		if *offline {
+			entry, err = LoadCache(name)
+			if err == nil && entry == nil {
+				err = fmt.Errorf("%s isn't cached", name)
+			}
+		} else {
+			entry, err = FetchHistory(client, name)
+		}
+		if err != nil {
+			log.Fatalln(classifyError(name, err))
+		}
+		for _, msg := range UnstarWaves(entry, time.Now()) {
+			fmt.Println(msg)
+			waves++
+		}
+	}
+	if waves > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,436 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/aymanbagabas/gh-stars/pkg/starsui"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/spf13/pflag"
+)
+
+// comparisonMsg is the history of a compared repository.
+type comparisonMsg struct {
+	index int
+	entry *stars.History
+	err   error
+}
+
+// Comparison overlays the cumulative stargazers of several repositories.
+type Comparison struct {
+	names   []string
+	entries []*stars.History
+	errs    []error
+	pending int
+	width   int
+	height  int
+	opts    Options
+	client  api.RESTClient
+	spinner spinner.Model
+	ranking bool
+	// hidden tells which repositories aren't plotted, toggled with the
+	// number keys.
+	hidden []bool
+	// window limits the plotted days when set.
+	window *Window
+	// normalize is how the stargazers are scaled, one of normalizations.
+	normalize string
+}
+
+const (
+	normalizeNone    = "none"
+	normalizeGained  = "gained"
+	normalizePercent = "percent"
+)
+
+// normalizations are the ways compared stargazers can be scaled: cumulative
+// stars, stars gained since the first plotted day, or percent of the stars on
+// the last plotted day.
+var normalizations = []string{normalizeNone, normalizeGained, normalizePercent}
+
+func NewComparison(names []string, opts Options) (*Comparison, error) {
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	s := spinner.New(spinner.WithSpinner(spinner.Dot))
+	if opts.ASCII {
+		s.Spinner = spinner.Line
+	}
+	s.Style = opts.Theme.AccentStyle()
+	names = dedupeRepos(names)
+	return &Comparison{
+		names:   names,
+		entries: make([]*stars.History, len(names)),
+		errs:    make([]error, len(names)),
+		hidden:  make([]bool, len(names)),
+		pending: len(names),
+		opts:    opts,
+		client:  client,
+		spinner: s,
+	}, nil
+}
+
+// Init fetches the repositories, using the cache when it's recent enough.
+func (c *Comparison) Init() tea.Cmd {
+	cmds := []tea.Cmd{c.spinner.Tick}
+	for i, name := range c.names {
+		i, name := i, name
+		cmds = append(cmds, func() tea.Msg {
+			entry, err := LoadHistory(c.client, name, time.Hour, c.opts.Offline)
+			return comparisonMsg{index: i, entry: entry, err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+func (c *Comparison) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		c.width = msg.Width
+		c.height = msg.Height
+	case tea.KeyMsg:
+		switch k := msg.String(); k {
+		case "q", "ctrl+c":
+			return c, tea.Quit
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+			if i := int(k[0] - '1'); i < len(c.hidden) {
+				c.hidden[i] = !c.hidden[i]
+			}
+		case "m":
+			c.ranking = !c.ranking
+		case "o":
+			names := c.names
+			return c, func() tea.Msg {
+				if err := OpenWeb(io.Discard, names...); err != nil {
+					log.Printf("opening star-history.com: %v", err)
+				}
+				return nil
+			}
+		}
+	case comparisonMsg:
+		c.entries[msg.index] = msg.entry
+		c.errs[msg.index] = msg.err
+		c.pending--
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		c.spinner, cmd = c.spinner.Update(msg)
+		return c, cmd
+	}
+	return c, nil
+}
+
+func (c *Comparison) View() string {
+	if v, ok := tooSmall(c.width, c.height); ok {
+		return v
+	}
+	v := c.render()
+	if c.opts.ASCII {
+		v = asciiReplacer.Replace(v)
+	}
+	return v
+}
+
+// status lists the repositories still loading and the ones that failed to,
+// a line each.
+func (c *Comparison) status() string {
+	loading := make([]string, 0)
+	for i, entry := range c.entries {
+		if entry == nil && c.errs[i] == nil {
+			loading = append(loading, c.names[i])
+		}
+	}
+	var b strings.Builder
+	if len(loading) > 0 {
+		fmt.Fprintf(&b, "\n %s loading %s...", c.spinner.View(), strings.Join(loading, ", "))
+	}
+	for i, err := range c.errs {
+		if err != nil {
+			fmt.Fprintf(&b, "\n Error loading %s: %s", c.names[i], err)
+		}
+	}
+	return b.String()
+}
+
+func (c *Comparison) render() string {
+	// Repositories are plotted as they load, so a slow one doesn't hold up
+	// the others.
+	loaded := make([]*stars.History, 0, len(c.entries))
+	skipped := make([]bool, len(c.entries))
+	for i, entry := range c.entries {
+		skipped[i] = entry == nil || c.hidden[i]
+		if entry != nil {
+			loaded = append(loaded, entry)
+		}
+	}
+	status := c.status()
+	if len(loaded) == 0 {
+		return status + "\n"
+	}
+	first := ""
+	for _, entry := range loaded {
+		if k := earliestKey(entry.Stargazers); k != "" && (first == "" || k < first) {
+			first = k
+		}
+	}
+	if first == "" {
+		return "\n No stargazers found.\n" + status
+	}
+	if c.ranking {
+		cfg := c.opts.Momentum.withDefaults()
+		return "\n" + momentumTable(RankMomentum(loaded, time.Now(), cfg), cfg.Window) + status
+	}
+	all := dayRange(first, time.Now().Format("2006-01-02"))
+	from, to := 0, len(all)
+	if c.window != nil {
+		for from < len(all) && all[from] < c.window.From.Format("2006-01-02") {
+			from++
+		}
+		for to > from && all[to-1] > c.window.To.Format("2006-01-02") {
+			to--
+		}
+	}
+	if from == to {
+		return "\n No stargazers in the window.\n"
+	}
+	days := all[from:to]
+	series := make([]starsui.Series, 0, len(c.entries))
+	labels := make([]string, len(c.entries))
+	for i, entry := range c.entries {
+		if entry == nil {
+			labels[i] = c.names[i]
+			continue
+		}
+		labels[i] = fmt.Sprintf("%s %d", entry.Name, entry.Stars)
+		if skipped[i] {
+			continue
+		}
+		values := make([]float64, len(all))
+		var total float64
+		for d, day := range all {
+			total += float64(entry.Stargazers[day])
+			values[d] = total
+		}
+		var before float64
+		if from > 0 {
+			before = values[from-1]
+		}
+		series = append(series, starsui.Series{Name: entry.Name, Labels: days, Values: normalize(values[from:to], before, c.normalize)})
+	}
+	if len(series) == 0 {
+		return "\n All repositories are hidden, press 1-9 to show them.\n\n " + c.opts.Theme.ToggleLegend(labels, skipped) + status
+	}
+	caption := fmt.Sprintf("stargazers over time since %s", formatDay(days[0]))
+	switch c.normalize {
+	case normalizeGained:
+		caption = fmt.Sprintf("stargazers gained since %s", formatDay(days[0]))
+	case normalizePercent:
+		caption = fmt.Sprintf("%% of the stargazers of %s over time since %s", formatDay(days[len(days)-1]), formatDay(days[0]))
+	}
+	renderer := c.opts.Renderer
+	if renderer == nil {
+		renderer = starsui.AsciigraphRenderer{}
+	}
+	graph := renderer.Render(series, starsui.RenderOptions{
+		Width:   c.width,
+		Height:  c.height - 2 - strings.Count(status, "\n"),
+		Caption: caption,
+		Colors:  c.opts.Theme.VisibleColors(skipped),
+	})
+	return graph + "\n " + c.opts.Theme.ToggleLegend(labels, skipped) + status
+}
+
+// normalize scales cumulative stargazers the way mode tells. before is the
+// number of stargazers before the first value.
+func normalize(values []float64, before float64, mode string) []float64 {
+	scaled := make([]float64, len(values))
+	if len(values) == 0 {
+		return scaled
+	}
+	for i, v := range values {
+		switch mode {
+		case normalizeGained:
+			scaled[i] = v - before
+		case normalizePercent:
+			if last := values[len(values)-1]; last > 0 {
+				scaled[i] = 100 * v / last
+			}
+		default:
+			scaled[i] = v
+		}
+	}
+	return scaled
+}
+
+// dayRange returns every day between from and to inclusive, formatted as
+// 2006-01-02.
+func dayRange(from, to string) []string {
+	start, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return nil
+	}
+	end, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return nil
+	}
+	days := make([]string, 0)
+	for t := start; !t.After(end); t = t.AddDate(0, 0, 1) {
+		days = append(days, t.Format("2006-01-02"))
+	}
+	return days
+}
+
+func runCompare(args []string) {
+	flags := pflag.NewFlagSet("compare", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gh stars compare <repository>... [flags]\n")
+		fmt.Fprintf(os.Stderr, "       gh stars compare --forks <n> [repository] [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Overlay the stargazers over time of several repositories.\n\n")
+		flags.PrintDefaults()
+	}
+	ui := addUIFlags(flags)
+	offline := flags.Bool("offline", false, "show the cached data without making API calls")
+	openWeb := flags.Bool("open-web", false, "open the star-history.com chart of the repositories in the browser")
+	window := flags.String("window", "", "only plot a quarter like 2024Q1 or a range like 2024-01-01..2024-03-31")
+	norm := flags.String("normalize", normalizeNone, "stars to plot: "+strings.Join(normalizations, ", "))
+	output := flags.StringP("output", "o", "", "write the histories to a star-history.com compatible CSV file instead of showing them")
+	interactive := flags.BoolP("interactive", "i", false, "pick the repositories and the options step by step")
+	forks := flags.Int("forks", 0, "compare the repository, the current one by default, with its n most starred forks")
+	flags.Parse(args)
+	repos := dedupeRepos(flags.Args())
+	if *forks > 0 {
+		var err error
+		if repos, err = withTopForks(repos, *forks, *offline); err != nil {
+			log.Fatalln(err)
+		}
+	}
+	if len(repos) < 2 && !*interactive {
+		flags.Usage()
+		os.Exit(1)
+	}
+	cfg, err := LoadConfig()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	opts, err := ui.Options(cfg)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	opts.Offline = *offline
+	if *interactive {
+		client, err := NewClient()
+		if err != nil {
+			log.Fatalln(err)
+		}
+		w := NewCompareWizard(client, knownRepos(cfg), opts)
+		w.Repos = repos
+		ui.Run(w)
+		if !w.Done {
+			return
+		}
+		repos, *window, *norm = w.Repos, w.Window, w.Normalize
+		*openWeb = w.Output == outputWeb
+		*output = w.File
+	}
+	if *openWeb {
+		if err := OpenWeb(os.Stdout, repos...); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+	if *output != "" {
+		if err := writeComparisonCSV(*output, repos, opts.Offline); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+	m, err := NewComparison(repos, opts)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if *window != "" {
+		w, err := ParseWindow(*window)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		m.window = &w
+	}
+	switch *norm {
+	case normalizeNone, normalizeGained, normalizePercent:
+		m.normalize = *norm
+	default:
+		log.Fatalf("unknown normalization %q, expected %s", *norm, strings.Join(normalizations, ", "))
+	}
+	ui.Run(m)
+}
+
+const forksPath = "repos/%s/forks?sort=stargazers&per_page=%d"
+
+// withTopForks returns the repository of repos, the current one if empty,
+// followed by its n most starred forks with stars, to see whether a hard fork
+// is overtaking its upstream.
+func withTopForks(repos []string, n int, offline bool) ([]string, error) {
+	if len(repos) > 1 {
+		return nil, errors.New("--forks compares a single repository with its forks")
+	}
+	if len(repos) == 0 {
+		if repo := currentRepo(); repo != "" {
+			repos = []string{repo}
+		}
+	}
+	if len(repos) == 0 {
+		return nil, errors.New("no repository specified")
+	}
+	if offline {
+		return nil, fmt.Errorf("listing the forks of %s: %w", repos[0], errOffline)
+	}
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	forks := make([]RepoMsg, 0)
+	if err := client.Get(fmt.Sprintf(forksPath, repos[0], min(n, stars.PerPage)), &forks); err != nil {
+		return nil, fmt.Errorf("listing the forks of %s: %w", repos[0], classifyError(repos[0], err))
+	}
+	for _, fork := range forks {
+		if fork.StargazersCount > 0 {
+			repos = append(repos, fork.FullName)
+		}
+	}
+	if len(repos) == 1 {
+		return nil, fmt.Errorf("%s has no starred forks", repos[0])
+	}
+	return repos, nil
+}
+
+// writeComparisonCSV writes the histories of repos to a star-history.com
+// compatible CSV file.
+func writeComparisonCSV(path string, repos []string, offline bool) error {
+	client, err := NewClient()
+	if err != nil {
+		return err
+	}
+	entries := make([]*stars.History, len(repos))
+	for i, name := range repos {
+		if entries[i], err = LoadHistory(client, name, time.Hour, offline); err != nil {
+			return err
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := ExportStarHistoryCSV(f, entries...); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aymanbagabas/gh-stars/pkg/starsui"
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the user configuration read from the config file.
+type Config struct {
+	Theme  string      `yaml:"theme"`
+	Colors ThemeColors `yaml:"colors"`
+	// Dashboard are the repositories shown by --dashboard.
+	Dashboard []string `yaml:"dashboard"`
+	// Groups are named sets of repositories charted as one.
+	Groups map[string][]string `yaml:"groups"`
+	// Momentum configures the momentum ranking of compared repositories.
+	Momentum MomentumConfig `yaml:"momentum"`
+	// Aliases are former names and mirrors of repositories whose
+	// stargazers are merged into them.
+	Aliases map[string][]string `yaml:"aliases"`
+	// Columns are the column sets of the table cycled through with C.
+	Columns [][]string `yaml:"columns"`
+	// Keys remap the key bindings of the repository view by name.
+	Keys map[string][]string `yaml:"keys"`
+	// Campaigns are named date ranges of promotion that stars are
+	// attributed to, keyed by repository.
+	Campaigns map[string][]Campaign `yaml:"campaigns"`
+}
+
+func configPath() (string, error) {
+	if path := os.Getenv("GH_STARS_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gh-stars", "config.yml"), nil
+}
+
+// LoadConfig reads the config file. A missing config file is not an error.
+func LoadConfig() (*Config, error) {
+	var cfg Config
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+var yamlLineRe = regexp.MustCompile(`^line (\d+): (.*)`)
+
+// ConfigIssue is a problem found in the config file.
+type ConfigIssue struct {
+	Line    int
+	Message string
+}
+
+func (i ConfigIssue) String() string {
+	if i.Line == 0 {
+		return i.Message
+	}
+	return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+}
+
+// ValidateConfig checks the config file and returns it with defaults
+// applied. Repositories are resolved with client unless it's nil.
+func ValidateConfig(client *api.RESTClient) (*Config, []ConfigIssue, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, nil, err
+	}
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg.effective(), nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	issues := make([]ConfigIssue, 0)
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			// Syntax errors already mention their line.
+			return nil, append(issues, ConfigIssue{Message: err.Error()}), nil
+		}
+		for _, e := range typeErr.Errors {
+			issue := ConfigIssue{Message: e}
+			if m := yamlLineRe.FindStringSubmatch(e); m != nil {
+				issue.Line, _ = strconv.Atoi(m[1])
+				issue.Message = m[2]
+			}
+			issues = append(issues, issue)
+		}
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, issues, nil
+	}
+	if _, err := NewTheme(cfg.Theme, ThemeColors{}); err != nil {
+		issues = append(issues, ConfigIssue{Line: configLine(&root, "theme"), Message: err.Error()})
+	}
+	for _, key := range []string{"series", "secondary", "axis", "label", "caption"} {
+		n := configNode(&root, "colors", key)
+		if n == nil {
+			continue
+		}
+		if _, err := parseAnsiColor(n.Value); err != nil {
+			issues = append(issues, ConfigIssue{Line: n.Line, Message: err.Error()})
+		}
+	}
+	if n := configNode(&root, "columns"); n != nil {
+		for i, set := range cfg.Columns {
+			if err := starsui.ValidateColumns(set); err != nil && i < len(n.Content) {
+				issues = append(issues, ConfigIssue{Line: n.Content[i].Line, Message: err.Error()})
+			}
+		}
+	}
+	if len(cfg.Keys) > 0 {
+		keys := DefaultKeyMap()
+		if err := keys.Remap(cfg.Keys); err != nil {
+			issues = append(issues, ConfigIssue{Line: configLine(&root, "keys"), Message: err.Error()})
+		}
+	}
+	if cfg.Momentum.Window < 0 {
+		issues = append(issues, ConfigIssue{Line: configLine(&root, "momentum", "window"), Message: "momentum window must be positive"})
+	}
+	if n := configNode(&root, "campaigns"); n != nil {
+		for i := 1; i < len(n.Content); i += 2 {
+			for j, c := range cfg.Campaigns[n.Content[i-1].Value] {
+				if err := c.Validate(); err != nil && j < len(n.Content[i].Content) {
+					issues = append(issues, ConfigIssue{Line: n.Content[i].Content[j].Line, Message: err.Error()})
+				}
+			}
+		}
+	}
+	repos := make([]*yaml.Node, 0)
+	if n := configNode(&root, "dashboard"); n != nil {
+		repos = append(repos, n.Content...)
+	}
+	if n := configNode(&root, "groups"); n != nil {
+		for i := 1; i < len(n.Content); i += 2 {
+			if len(n.Content[i].Content) == 0 {
+				issues = append(issues, ConfigIssue{Line: n.Content[i-1].Line, Message: fmt.Sprintf("group %s has no repositories", n.Content[i-1].Value)})
+			}
+			repos = append(repos, n.Content[i].Content...)
+		}
+	}
+	if n := configNode(&root, "aliases"); n != nil {
+		for i := 1; i < len(n.Content); i += 2 {
+			repos = append(repos, n.Content[i-1])
+			repos = append(repos, n.Content[i].Content...)
+		}
+	}
+	if n := configNode(&root, "campaigns"); n != nil {
+		for i := 0; i < len(n.Content); i += 2 {
+			repos = append(repos, n.Content[i])
+		}
+	}
+	for _, n := range repos {
+		if owner, repo, ok := strings.Cut(n.Value, "/"); !ok || owner == "" || repo == "" || strings.Contains(repo, "/") {
+			issues = append(issues, ConfigIssue{Line: n.Line, Message: fmt.Sprintf("invalid repository %q, expected owner/repo", n.Value)})
+			continue
+		}
+		if client == nil {
+			continue
+		}
+		if _, err := FetchRepo(*client, n.Value); err != nil {
+			issues = append(issues, ConfigIssue{Line: n.Line, Message: classifyError(n.Value, err).Error()})
+		}
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+	return cfg.effective(), issues, nil
+}
+
+// effective returns the config with defaults applied.
+func (c Config) effective() *Config {
+	if c.Theme == "" {
+		c.Theme = "auto"
+	}
+	c.Momentum = c.Momentum.withDefaults()
+	return &c
+}
+
+// configNode returns the node at the path of mapping keys of a document,
+// or nil if there is none.
+func configNode(doc *yaml.Node, path ...string) *yaml.Node {
+	n := doc
+	if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		n = n.Content[0]
+	}
+	for _, key := range path {
+		if n.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if n.Content[i].Value == key {
+				next = n.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		n = next
+	}
+	return n
+}
+
+// configLine returns the line of the node at path, or 0 if there is none.
+func configLine(doc *yaml.Node, path ...string) int {
+	if n := configNode(doc, path...); n != nil {
+		return n.Line
+	}
+	return 0
+}
+
+func runConfig(args []string) {
+	flags := pflag.NewFlagSet("config", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gh stars config validate [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Check the config file, resolve its repositories, and print the effective\nconfiguration.\n\n")
+		flags.PrintDefaults()
+	}
+	offline := flags.Bool("offline", false, "don't resolve the repositories")
+	addTokenFlag(flags)
+	flags.Parse(args)
+	if flags.NArg() != 1 || flags.Arg(0) != "validate" {
+		flags.Usage()
+		os.Exit(1)
+	}
+	path, err := configPath()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var client *api.RESTClient
+	issues := make([]ConfigIssue, 0)
+	if !*offline {
+		c, err := NewClient()
+		if err != nil {
+			issues = append(issues, ConfigIssue{Message: err.Error()})
+		} else {
+			client = &c
+		}
+	}
+	cfg, configIssues, err := ValidateConfig(client)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	issues = append(issues, configIssues...)
+	for _, issue := range issues {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", path, issue)
+	}
+	if cfg != nil {
+		out, err := yaml.Marshal(cfg)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Printf("# Effective configuration of %s\n%s", path, out)
+	}
+	if len(issues) > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/aymanbagabas/gh-stars/pkg/starsui"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	contributorStatsPath = "repos/%s/stats/contributors"
+	// contributorStatsRetries is the number of times to poll the contributor
+	// statistics while GitHub computes them.
+	contributorStatsRetries = 5
+	contributorStatsDelay   = 2 * time.Second
+)
+
+// ContributorStats is the weekly commit activity of a contributor.
+type ContributorStats struct {
+	Author stars.User `json:"author"`
+	Weeks  []struct {
+		Week    int64 `json:"w"`
+		Commits int   `json:"c"`
+	} `json:"weeks"`
+}
+
+// ContributorsMsg holds the number of first-time contributors per month.
+type ContributorsMsg struct {
+	Monthly map[string]int
+	Err     error
+}
+
+// contributors compares the monthly star gains with the number of
+// first-time contributors.
+type contributors struct {
+	loading bool
+	monthly map[string]int
+	err     error
+}
+
+// Load fetches the contributor statistics unless they were already fetched.
+// GitHub only reports the top 100 contributors.
+func (c *contributors) Load(r *Repo) tea.Cmd {
+	if c.loading || c.monthly != nil {
+		return nil
+	}
+	if r.offline {
+		c.err = errOffline
+		return nil
+	}
+	c.loading = true
+	client, name := r.client, r.name
+	return func() tea.Msg {
+		path := fmt.Sprintf(contributorStatsPath, name)
+		var stats []ContributorStats
+		for i := 0; ; i++ {
+			resp, err := client.Request(http.MethodGet, path, nil)
+			if err != nil {
+				return ContributorsMsg{Err: err}
+			}
+			// GitHub returns 202 while the statistics are being computed.
+			if resp.StatusCode == http.StatusAccepted {
+				resp.Body.Close()
+				if i == contributorStatsRetries {
+					return ContributorsMsg{Err: fmt.Errorf("GitHub is still computing the contributor statistics, try again later")}
+				}
+				time.Sleep(contributorStatsDelay)
+				continue
+			}
+			err = json.NewDecoder(resp.Body).Decode(&stats)
+			resp.Body.Close()
+			if err != nil {
+				return ContributorsMsg{Err: err}
+			}
+			break
+		}
+		monthly := make(map[string]int)
+		for _, s := range stats {
+			for _, w := range s.Weeks {
+				if w.Commits > 0 {
+					monthly[time.Unix(w.Week, 0).UTC().Format("2006-01")]++
+					break
+				}
+			}
+		}
+		return ContributorsMsg{Monthly: monthly}
+	}
+}
+
+// SetMonthly sets the number of first-time contributors per month.
+func (c *contributors) SetMonthly(msg ContributorsMsg) {
+	c.loading = false
+	c.monthly = msg.Monthly
+	c.err = msg.Err
+	if c.monthly == nil && c.err == nil {
+		c.monthly = make(map[string]int)
+	}
+}
+
+func (c *contributors) View(r *Repo) string {
+	switch {
+	case c.loading:
+		return fmt.Sprintf("\n %s loading contributors...\n", r.spinner.View())
+	case c.err != nil:
+		return fmt.Sprintf("\n Error: %s", c.err)
+	case len(r.stargazers) == 0:
+		return "\n No stargazers found.\n"
+	}
+	stars := monthlyStars(r.stargazers)
+	first := earliestKey(stars)
+	if k := earliestKey(c.monthly); k != "" && k < first {
+		first = k
+	}
+	months := monthRange(first, time.Now().Format("2006-01"))
+	starSeries := make([]float64, len(months))
+	contribSeries := make([]float64, len(months))
+	var maxStars, maxContrib float64
+	for i, m := range months {
+		starSeries[i] = float64(stars[m])
+		contribSeries[i] = float64(c.monthly[m])
+		maxStars = math.Max(maxStars, starSeries[i])
+		maxContrib = math.Max(maxContrib, contribSeries[i])
+	}
+	// Scale the contributors to the stars so both series use the whole
+	// height of the graph.
+	scale := 1.0
+	if maxContrib > 0 && maxStars > 0 {
+		scale = maxStars / maxContrib
+	}
+	for i := range contribSeries {
+		contribSeries[i] *= scale
+	}
+	caption := fmt.Sprintf("%s monthly stars vs. new contributors (x%.1f), correlation %.2f",
+		r.name, scale, correlation(starSeries, contribSeries))
+	return starsui.AsciigraphRenderer{}.Render(
+		[]starsui.Series{
+			{Name: "stars", Labels: months, Values: starSeries},
+			{Name: "contributors", Labels: months, Values: contribSeries},
+		},
+		starsui.RenderOptions{
+			Width:   r.width,
+			Height:  r.height - 1,
+			Caption: caption,
+			Colors:  r.theme.Colors(),
+		},
+	)
+}
+
+// monthlyStars sums the daily stargazers per month.
+func monthlyStars(daily map[string]int) map[string]int {
+	monthly := make(map[string]int)
+	for day, count := range daily {
+		monthly[day[:7]] += count
+	}
+	return monthly
+}
+
+// earliestKey returns the smallest key of m, or an empty string if m is
+// empty.
+func earliestKey(m map[string]int) string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+	return keys[0]
+}
+
+// monthRange returns every month between from and to inclusive, formatted as
+// 2006-01.
+func monthRange(from, to string) []string {
+	start, err := time.Parse("2006-01", from)
+	if err != nil {
+		return nil
+	}
+	end, err := time.Parse("2006-01", to)
+	if err != nil {
+		return nil
+	}
+	months := make([]string, 0)
+	for t := start; !t.After(end); t = t.AddDate(0, 1, 0) {
+		months = append(months, t.Format("2006-01"))
+	}
+	return months
+}
+
+// correlation returns the Pearson correlation coefficient of two series of
+// the same length.
+func correlation(x, y []float64) float64 {
+	n := float64(len(x))
+	if n == 0 {
+		return 0
+	}
+	var sx, sy, sxx, syy, sxy float64
+	for i := range x {
+		sx += x[i]
+		sy += y[i]
+		sxx += x[i] * x[i]
+		syy += y[i] * y[i]
+		sxy += x[i] * y[i]
+	}
+	d := math.Sqrt(n*sxx-sx*sx) * math.Sqrt(n*syy-sy*sy)
+	if d == 0 {
+		return 0
+	}
+	return (n*sxy - sx*sy) / d
+}
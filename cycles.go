@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// CyclesMsg holds the releases of the repository for the release cycles
+// view.
+type CyclesMsg ReleasesMsg
+
+// cycles charts the stars gained from each release until the next one, to
+// tell which release drove the most growth.
+type cycles struct {
+	loading bool
+	loaded  bool
+	list    []Release
+	err     error
+}
+
+// Load fetches the releases unless they were already fetched.
+func (c *cycles) Load(r *Repo) tea.Cmd {
+	if c.loading || c.loaded {
+		return nil
+	}
+	if r.releases.loaded && r.releases.err == nil {
+		c.loaded = true
+		c.list = r.releases.list
+		return nil
+	}
+	if r.offline {
+		c.err = errOffline
+		return nil
+	}
+	c.loading = true
+	client, name := r.client, r.name
+	return func() tea.Msg {
+		list, err := FetchReleases(client, name)
+		return CyclesMsg{Releases: list, Err: err}
+	}
+}
+
+// SetReleases stores the fetched releases.
+func (c *cycles) SetReleases(msg CyclesMsg) {
+	c.loading = false
+	c.loaded = true
+	c.list = msg.Releases
+	c.err = msg.Err
+}
+
+// releaseCycles returns the tag of each release and the stars gained from
+// its day until the day of the next release, or until now for the last one.
+// releases must be sorted by date.
+func releaseCycles(daily map[string]int, releases []Release) ([]string, []int) {
+	tags := make([]string, len(releases))
+	counts := make([]int, len(releases))
+	for i, rel := range releases {
+		tags[i] = rel.TagName
+		from, to := rel.Date(), ""
+		if i+1 < len(releases) {
+			to = releases[i+1].Date()
+		}
+		for day, n := range daily {
+			if day >= from && (to == "" || day < to) {
+				counts[i] += n
+			}
+		}
+	}
+	return tags, counts
+}
+
+func (c *cycles) View(r *Repo) string {
+	switch {
+	case c.loading:
+		return fmt.Sprintf("\n %s loading releases...\n", r.spinner.View())
+	case c.err != nil:
+		return fmt.Sprintf("\n Error: %s", c.err)
+	case len(c.list) == 0:
+		return "\n No releases found.\n"
+	}
+	tags, counts := releaseCycles(r.stargazers, c.list)
+	best := 0
+	for i, n := range counts {
+		if n > counts[best] {
+			best = i
+		}
+	}
+	note := fmt.Sprintf(" Stars gained from each release until the next one, %s drove the most with %d", tags[best], counts[best])
+	// Show the most recent releases that fit.
+	if n := r.height - 4; n > 0 && len(tags) > n {
+		tags, counts = tags[len(tags)-n:], counts[len(counts)-n:]
+	}
+	lines := append([]string{""}, barChart(r.theme, tags, counts, r.width)...)
+	lines = append(lines, "", r.theme.AccentStyle().Render(note))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/cli/go-gh/pkg/api"
+)
+
+// sparklineDays is the number of days shown in the sparklines.
+const sparklineDays = 30
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// dashboardRowMsg is the history of a dashboard repository.
+type dashboardRowMsg struct {
+	index int
+	entry *stars.History
+	err   error
+}
+
+type dashboardRow struct {
+	name  string
+	entry *stars.History
+	err   error
+	// group rows sum the stargazers of the other rows.
+	group bool
+}
+
+// Dashboard lists several repositories with their recent stars. Selecting a
+// repository opens its full view.
+type Dashboard struct {
+	rows    []dashboardRow
+	cursor  int
+	width   int
+	height  int
+	opts    Options
+	client  api.RESTClient
+	spinner spinner.Model
+	child   *Repo
+	error   error
+	// totals adds a row summing the stars of the loaded rows.
+	totals bool
+}
+
+func NewDashboard(repos []string, opts Options) (*Dashboard, error) {
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	repos = dedupeRepos(repos)
+	rows := make([]dashboardRow, len(repos))
+	for i, name := range repos {
+		rows[i] = dashboardRow{name: name}
+	}
+	s := spinner.New(spinner.WithSpinner(spinner.Dot))
+	if opts.ASCII {
+		s.Spinner = spinner.Line
+	}
+	s.Style = opts.Theme.AccentStyle()
+	return &Dashboard{
+		rows:    rows,
+		opts:    opts,
+		client:  client,
+		spinner: s,
+	}, nil
+}
+
+// NewGroup returns a dashboard of the members of a group of repositories,
+// led by a row summing their stargazers.
+func NewGroup(name string, members []string, opts Options) (*Dashboard, error) {
+	d, err := NewDashboard(members, opts)
+	if err != nil {
+		return nil, err
+	}
+	d.rows = append([]dashboardRow{{name: name, group: true}}, d.rows...)
+	return d, nil
+}
+
+// Init fetches the repositories, using the cache when it's recent enough.
+// Their requests share the request budget.
+func (d *Dashboard) Init() tea.Cmd {
+	cmds := []tea.Cmd{d.spinner.Tick}
+	for i, row := range d.rows {
+		if row.group {
+			continue
+		}
+		i, name := i, row.name
+		cmds = append(cmds, func() tea.Msg {
+			entry, err := LoadHistory(d.client, name, time.Hour, d.opts.Offline)
+			return dashboardRowMsg{index: i, entry: entry, err: err}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+func (d *Dashboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case dashboardRowMsg:
+		d.rows[msg.index].entry = msg.entry
+		d.rows[msg.index].err = msg.err
+		d.sumGroup()
+		return d, nil
+	case tea.WindowSizeMsg:
+		d.width = msg.Width
+		d.height = msg.Height
+	case tea.KeyMsg:
+		if d.child != nil {
+			if msg.String() == "backspace" {
+				d.child = nil
+				return d, nil
+			}
+			break
+		}
+		switch msg.String() {
+		case "q", "ctrl+c":
+			return d, tea.Quit
+		case "up", "k":
+			if d.cursor > 0 {
+				d.cursor--
+			}
+		case "down", "j":
+			if d.cursor < len(d.rows)-1 {
+				d.cursor++
+			}
+		case "enter":
+			if len(d.rows) == 0 {
+				return d, nil
+			}
+			row := d.rows[d.cursor]
+			opts := d.opts
+			if row.group {
+				if row.entry == nil {
+					return d, nil
+				}
+				opts.Data = row.entry
+			}
+			child, err := NewRepo(row.name, opts)
+			if err != nil {
+				d.error = err
+				return d, nil
+			}
+			d.child = child
+			child.Update(tea.WindowSizeMsg{Width: d.width, Height: d.height})
+			return d, child.Init()
+		}
+		return d, nil
+	case spinner.TickMsg:
+		if d.child == nil {
+			var cmd tea.Cmd
+			d.spinner, cmd = d.spinner.Update(msg)
+			return d, cmd
+		}
+	}
+	if d.child != nil {
+		_, cmd := d.child.Update(msg)
+		return d, cmd
+	}
+	return d, nil
+}
+
+func (d *Dashboard) View() string {
+	if d.child != nil {
+		return d.child.View()
+	}
+	if v, ok := tooSmall(d.width, d.height); ok {
+		return v
+	}
+	v := d.render()
+	if d.opts.ASCII {
+		v = asciiReplacer.Replace(v)
+	}
+	return v
+}
+
+func (d *Dashboard) render() string {
+	if d.error != nil {
+		return fmt.Sprintf("\n Error: %s", d.error)
+	}
+	if len(d.rows) == 0 {
+		return "\n No repositories configured, add them to the dashboard list of the config file.\n"
+	}
+	nameWidth := 0
+	for _, row := range d.rows {
+		if len(row.name) > nameWidth {
+			nameWidth = len(row.name)
+		}
+	}
+	lines := []string{
+		"",
+		fmt.Sprintf("   %-*s %8s %8s  %s", nameWidth, "Repository", "Stars", "7 days", "Last 30 days"),
+	}
+	// Scroll so the cursor stays visible.
+	visible := d.height - len(lines) - 2
+	if d.totals {
+		visible--
+	}
+	start := 0
+	if visible > 0 && d.cursor >= visible {
+		start = d.cursor - visible + 1
+	}
+	for i := start; i < len(d.rows) && (visible <= 0 || i < start+visible); i++ {
+		row := d.rows[i]
+		var line string
+		switch {
+		case row.err != nil:
+			line = fmt.Sprintf("%-*s Error: %s", nameWidth, row.name, row.err)
+		case row.entry == nil:
+			line = fmt.Sprintf("%-*s %s", nameWidth, row.name, d.spinner.View())
+		default:
+			line = entryLine(nameWidth, row.name, row.entry)
+		}
+		if i == d.cursor {
+			line = d.opts.Theme.AccentStyle().Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, " "+line)
+	}
+	if d.totals {
+		entries := make([]*stars.History, 0, len(d.rows))
+		for _, row := range d.rows {
+			if row.entry != nil {
+				entries = append(entries, row.entry)
+			}
+		}
+		lines = append(lines, "   "+entryLine(nameWidth, "Total", SumHistories("Total", entries)))
+	}
+	lines = append(lines, "", lipgloss.NewStyle().Faint(true).Render(" ↑/↓ select • enter open • backspace back • q quit"))
+	return strings.Join(lines, "\n")
+}
+
+// entryLine renders the stars, the stars of the last 7 days, and the
+// sparkline of a history.
+func entryLine(nameWidth int, name string, entry *stars.History) string {
+	recent := recentDaily(entry.Stargazers, time.Now(), sparklineDays)
+	var week int
+	for _, v := range recent[len(recent)-7:] {
+		week += int(v)
+	}
+	return fmt.Sprintf("%-*s %8d %+8d  %s", nameWidth, name, entry.Stars, week, sparkline(recent))
+}
+
+// sumGroup sums the stargazers of the members into the group row once they
+// are all loaded.
+func (d *Dashboard) sumGroup() {
+	if len(d.rows) == 0 || !d.rows[0].group {
+		return
+	}
+	entries := make([]*stars.History, 0, len(d.rows)-1)
+	for _, row := range d.rows[1:] {
+		if row.err != nil {
+			d.rows[0].err = fmt.Errorf("loading %s: %w", row.name, row.err)
+			return
+		}
+		if row.entry == nil {
+			return
+		}
+		entries = append(entries, row.entry)
+	}
+	d.rows[0].entry = SumHistories(d.rows[0].name, entries)
+}
+
+// SumHistories returns a history with the stargazers of all entries summed
+// per day.
+func SumHistories(name string, entries []*stars.History) *stars.History {
+	sum := &stars.History{Name: name, Stargazers: make(map[string]int)}
+	for i, entry := range entries {
+		sum.Stars += entry.Stars
+		for day, count := range entry.Stargazers {
+			sum.Stargazers[day] += count
+		}
+		if i == 0 || entry.UpdatedAt.Before(sum.UpdatedAt) {
+			sum.UpdatedAt = entry.UpdatedAt
+		}
+	}
+	return sum
+}
+
+// recentDaily returns the number of stargazers of each of the last days
+// before now, oldest first.
+func recentDaily(daily map[string]int, now time.Time, days int) []float64 {
+	values := make([]float64, days)
+	for i := 0; i < days; i++ {
+		day := now.AddDate(0, 0, i-days+1).Format("2006-01-02")
+		values[i] = float64(daily[day])
+	}
+	return values
+}
+
+// sparkline renders values as a line of block characters.
+func sparkline(values []float64) string {
+	max := 0.0
+	for _, v := range values {
+		max = math.Max(max, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if max > 0 {
+			i = int(math.Round(v / max * float64(len(sparkBlocks)-1)))
+		}
+		b.WriteRune(sparkBlocks[i])
+	}
+	return b.String()
+}
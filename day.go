@@ -0,0 +1,188 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/go-gh/pkg/browser"
+)
+
+const profileURL = "https://github.com/%s"
+
+// DayMsg holds the accounts of the stargazers of a day.
+type DayMsg struct {
+	Day      string
+	Accounts []Account
+	Err      error
+}
+
+// dayStargazers lists who starred the repository on the selected day of the
+// table.
+type dayStargazers struct {
+	active    bool
+	loading   bool
+	day       string
+	logins    []string
+	starredAt []time.Time
+	err       error
+	// accounts holds the accounts of the days already opened.
+	accounts map[string][]Account
+	table    table.Model
+}
+
+func newDayStargazers(theme Theme) dayStargazers {
+	return dayStargazers{
+		accounts: make(map[string][]Account),
+		table: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "Stargazer", Width: 20},
+				{Title: "Starred", Width: 7},
+				{Title: "Account age", Width: 11},
+				{Title: "Profile", Width: 40},
+			}),
+			table.WithFocused(true),
+			table.WithStyles(theme.TableStyles()),
+		),
+	}
+}
+
+// Active returns whether the stargazers of a day are listed.
+func (d *dayStargazers) Active() bool {
+	return d.active
+}
+
+// Stop hides the stargazers of the day.
+func (d *dayStargazers) Stop() {
+	d.active = false
+}
+
+// SetSize sets the size of the stargazers table.
+func (d *dayStargazers) SetSize(width, height int) {
+	d.table.SetWidth(width)
+	// Leave room for the title.
+	d.table.SetHeight(height - 2)
+}
+
+// Toggle lists the stargazers of the selected day of the table, fetching
+// their accounts unless they were already fetched, or hides them.
+func (d *dayStargazers) Toggle(r *Repo) tea.Cmd {
+	if d.active {
+		d.active = false
+		return nil
+	}
+	day := r.table.SelectedDay()
+	if day == "" {
+		return nil
+	}
+	d.active = true
+	d.day = day
+	d.err = nil
+	d.logins, d.starredAt = nil, nil
+	// Histories cached before starred dates were stored don't have them.
+	if len(r.starredAt) != len(r.logins) {
+		d.err = errors.New("the starred dates of the stargazers aren't cached, fetch the repository again")
+		d.setRows()
+		return nil
+	}
+	for i, login := range r.logins {
+		if dayOf(r.starredAt[i]) == day {
+			d.logins = append(d.logins, login)
+			d.starredAt = append(d.starredAt, r.starredAt[i])
+		}
+	}
+	d.setRows()
+	if _, ok := d.accounts[day]; ok || len(d.logins) == 0 || r.offline {
+		return nil
+	}
+	d.loading = true
+	client, logins := r.client, d.logins
+	return func() tea.Msg {
+		accounts, err := FetchAccounts(client, logins)
+		return DayMsg{Day: day, Accounts: accounts, Err: err}
+	}
+}
+
+// SetAccounts fills in the account ages of the stargazers of a day.
+func (d *dayStargazers) SetAccounts(msg DayMsg) {
+	d.loading = false
+	if msg.Err != nil {
+		d.err = msg.Err
+		return
+	}
+	d.accounts[msg.Day] = msg.Accounts
+	if msg.Day == d.day {
+		d.setRows()
+	}
+}
+
+func (d *dayStargazers) setRows() {
+	accounts := d.accounts[d.day]
+	rows := make([]table.Row, len(d.logins))
+	for i, login := range d.logins {
+		age := "-"
+		if len(accounts) == len(d.logins) {
+			age = accountAge(accounts[i].CreatedAt, d.starredAt[i])
+		}
+		rows[i] = table.Row{
+			login,
+			d.starredAt[i].In(timeZone).Format("15:04"),
+			age,
+			fmt.Sprintf(profileURL, login),
+		}
+	}
+	d.table.SetRows(rows)
+	d.table.GotoTop()
+}
+
+// accountAge returns how old an account was at a time.
+func accountAge(created, at time.Time) string {
+	days := int(at.Sub(created).Hours() / 24)
+	switch {
+	case days < 0:
+		return "-"
+	case days < 31:
+		return fmt.Sprintf("%d days", days)
+	case days < 2*365:
+		return fmt.Sprintf("%d months", days/30)
+	default:
+		return fmt.Sprintf("%d years", days/365)
+	}
+}
+
+// OpenProfile opens the profile of the selected stargazer in the browser.
+func (d *dayStargazers) OpenProfile() tea.Cmd {
+	row := d.table.SelectedRow()
+	if row == nil {
+		return nil
+	}
+	login := row[0]
+	return func() tea.Msg {
+		b := browser.New("", io.Discard, os.Stderr)
+		if err := b.Browse(fmt.Sprintf(profileURL, login)); err != nil {
+			log.Printf("opening the profile of %s: %v", login, err)
+		}
+		return nil
+	}
+}
+
+func (d *dayStargazers) View(r *Repo) string {
+	title := fmt.Sprintf(" %s stargazers on %s", formatNumber(len(d.logins)), formatDay(d.day))
+	switch {
+	case d.err != nil:
+		title += fmt.Sprintf(", error: %s", d.err)
+	case d.loading:
+		title += fmt.Sprintf(", %s loading accounts...", r.spinner.View())
+	case r.offline && len(d.accounts[d.day]) == 0:
+		title += ", account ages aren't available offline"
+	}
+	if len(d.logins) == 0 {
+		return r.theme.AccentStyle().Render(title) + "\n\n No stargazers listed for this day."
+	}
+	return r.theme.AccentStyle().Render(title) + "\n" + d.table.View()
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/spf13/pflag"
+)
+
+// DayGain is the number of stargazers gained on a day.
+type DayGain struct {
+	Date  string `json:"date"`
+	Stars int    `json:"stars"`
+}
+
+// Diff is the change of the stargazers of a repository between two dates.
+type Diff struct {
+	Name string `json:"name"`
+	// From is the first day of the period and To the day after its last.
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Gained int    `json:"gained"`
+	// Velocity is the number of stars gained per day, and
+	// PreviousVelocity the one of the period of the same length before.
+	Velocity         float64   `json:"velocity"`
+	PreviousVelocity float64   `json:"previous_velocity"`
+	TopDays          []DayGain `json:"top_days"`
+	// Logins are the stargazers gained in the period, only known when the
+	// starred date of every cached stargazer is.
+	Logins []string `json:"logins,omitempty"`
+}
+
+// NewDiff returns the change of the stargazers of entry from the day from
+// until the day before to, with its top top days.
+func NewDiff(entry *stars.History, from, to time.Time, top int) Diff {
+	days := int(to.Sub(from).Hours() / 24)
+	start, end := from.Format("2006-01-02"), to.Format("2006-01-02")
+	before := from.AddDate(0, 0, -days).Format("2006-01-02")
+	d := Diff{Name: entry.Name, From: start, To: end, TopDays: make([]DayGain, 0)}
+	var previous int
+	for day, count := range entry.Stargazers {
+		switch {
+		case day >= start && day < end:
+			d.Gained += count
+			d.TopDays = append(d.TopDays, DayGain{Date: day, Stars: count})
+		case day >= before && day < start:
+			previous += count
+		}
+	}
+	if days > 0 {
+		d.Velocity = float64(d.Gained) / float64(days)
+		d.PreviousVelocity = float64(previous) / float64(days)
+	}
+	sort.Slice(d.TopDays, func(i, j int) bool {
+		if d.TopDays[i].Stars != d.TopDays[j].Stars {
+			return d.TopDays[i].Stars > d.TopDays[j].Stars
+		}
+		return d.TopDays[i].Date < d.TopDays[j].Date
+	})
+	if len(d.TopDays) > top {
+		d.TopDays = d.TopDays[:top]
+	}
+	if len(entry.StarredAt) == len(entry.Logins) {
+		for i, login := range entry.Logins {
+			if day := dayOf(entry.StarredAt[i]); day >= start && day < end {
+				d.Logins = append(d.Logins, login)
+			}
+		}
+	}
+	return d
+}
+
+// WriteDiff writes a diff as a plain text table.
+func WriteDiff(w io.Writer, d Diff) error {
+	change := "n/a"
+	if d.PreviousVelocity > 0 {
+		change = fmt.Sprintf("%+.1f%%", 100*(d.Velocity-d.PreviousVelocity)/d.PreviousVelocity)
+	}
+	fmt.Fprintf(w, "%s from %s to %s\n\n", d.Name, d.From, d.To)
+	fmt.Fprintf(w, "%-10s %+d\n", "Gained", d.Gained)
+	fmt.Fprintf(w, "%-10s %.1f stars per day, %s from %.1f the period before\n", "Velocity", d.Velocity, change, d.PreviousVelocity)
+	if len(d.TopDays) > 0 {
+		fmt.Fprintf(w, "\nTop days\n")
+		for _, day := range d.TopDays {
+			fmt.Fprintf(w, "  %s %6d\n", day.Date, day.Stars)
+		}
+	}
+	if len(d.Logins) > 0 {
+		fmt.Fprintf(w, "\nStargazers gained (%d)\n", len(d.Logins))
+		for _, login := range d.Logins {
+			fmt.Fprintf(w, "  %s\n", login)
+		}
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+func runDiff(args []string) {
+	flags := pflag.NewFlagSet("diff", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gh stars diff <repository> --from <date> [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Print the stargazers gained by a repository between two dates.\n\n")
+		flags.PrintDefaults()
+	}
+	fromFlag := flags.String("from", "", "first day of the period, as 2006-01-02")
+	toFlag := flags.String("to", "", "day after the last day of the period, as 2006-01-02, tomorrow by default")
+	format := flags.String("format", "table", "table or json")
+	top := flags.Int("top", 5, "number of top gain days to list")
+	offline := flags.Bool("offline", false, "use the cached data without making API calls")
+	tz := flags.String("tz", "UTC", "time zone of the days stargazers are counted in: UTC, local, or an IANA name")
+	addTokenFlag(flags)
+	addMaxRequestsFlag(flags)
+	flags.Parse(args)
+	if flags.NArg() != 1 || *fromFlag == "" {
+		flags.Usage()
+		os.Exit(1)
+	}
+	var err error
+	timeZone, err = ParseTimeZone(*tz)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	from, err := time.Parse("2006-01-02", *fromFlag)
+	if err != nil {
+		log.Fatalf("invalid --from date: %v", err)
+	}
+	to := time.Now().In(timeZone).AddDate(0, 0, 1)
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	if *toFlag != "" {
+		if to, err = time.Parse("2006-01-02", *toFlag); err != nil {
+			log.Fatalf("invalid --to date: %v", err)
+		}
+	}
+	if !to.After(from) {
+		log.Fatalln("--to must be after --from")
+	}
+	client, err := NewClient()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	entry, err := LoadHistory(client, flags.Arg(0), time.Hour, *offline)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	d := NewDiff(entry, from, to, *top)
+	switch *format {
+	case "table":
+		err = WriteDiff(os.Stdout, d)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(d)
+	default:
+		log.Fatalf("unknown format %q, expected table or json", *format)
+	}
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// watchInterval is how often the repository is polled while waiting for its
+// first star.
+const watchInterval = time.Minute
+
+type watchTickMsg struct{}
+
+// watchMsg is the repository metadata polled while waiting for its first
+// star.
+type watchMsg RepoMsg
+
+// firstStar waits for the first star of a repository with no stargazers.
+type firstStar struct {
+	watching bool
+	checked  time.Time
+}
+
+func watchTick() tea.Cmd {
+	return tea.Tick(watchInterval, func(time.Time) tea.Msg {
+		return watchTickMsg{}
+	})
+}
+
+// Toggle starts or stops watching the repository.
+func (f *firstStar) Toggle() tea.Cmd {
+	f.watching = !f.watching
+	if !f.watching {
+		return nil
+	}
+	return watchTick()
+}
+
+// Poll fetches the repository metadata if it's still watched.
+func (f *firstStar) Poll(r *Repo) tea.Cmd {
+	if !f.watching {
+		return nil
+	}
+	client, name := r.client, r.name
+	return func() tea.Msg {
+		repo, err := FetchRepo(client, name)
+		if err != nil {
+			// Keep watching through transient errors.
+			return watchMsg(RepoMsg{FullName: name})
+		}
+		return watchMsg(repo)
+	}
+}
+
+// Update handles the polled metadata. It reloads the repository once it has
+// stargazers and rings the terminal bell.
+func (f *firstStar) Update(r *Repo, msg watchMsg) tea.Cmd {
+	f.checked = time.Now()
+	if msg.StargazersCount == 0 {
+		return watchTick()
+	}
+	f.watching = false
+	fmt.Fprint(os.Stdout, "\a")
+	repo := RepoMsg(msg)
+	return func() tea.Msg {
+		return repo
+	}
+}
+
+// View renders the empty state of a repository without stargazers.
+func (f *firstStar) View(r *Repo) string {
+	if r.offline {
+		return "\n No stargazers found.\n"
+	}
+	lines := []string{
+		"",
+		r.theme.AccentStyle().Bold(true).Render(r.name),
+	}
+	if r.repo.Description != "" {
+		lines = append(lines, r.repo.Description)
+	}
+	meta := make([]string, 0)
+	if r.repo.Language != "" {
+		meta = append(meta, r.repo.Language)
+	}
+	if !r.repo.CreatedAt.IsZero() {
+		meta = append(meta, "created "+r.repo.CreatedAt.Format("2006-01-02"))
+	}
+	meta = append(meta, fmt.Sprintf("%d forks", r.repo.ForksCount), fmt.Sprintf("%d watchers", r.repo.SubscribersCount))
+	lines = append(lines, strings.Join(meta, " • "), "", "No stars yet.")
+	if f.watching {
+		status := fmt.Sprintf("%s watching for the first star", r.spinner.View())
+		if !f.checked.IsZero() {
+			status += fmt.Sprintf(", last checked at %s", f.checked.Format("15:04"))
+		}
+		lines = append(lines, status, "Press w to stop watching.")
+	} else {
+		lines = append(lines, "Press w to watch for the first star.")
+	}
+	return lipgloss.Place(r.width, r.height, lipgloss.Center, lipgloss.Center,
+		lipgloss.JoinVertical(lipgloss.Center, lines...))
+}
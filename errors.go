@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cli/go-gh/pkg/api"
+)
+
+// ErrorKind is the kind of an API error.
+type ErrorKind int
+
+const (
+	ErrOther ErrorKind = iota
+	ErrNotFound
+	ErrNoAuth
+	ErrRateLimited
+)
+
+// RepoError is an API error that happened while loading a repository, with
+// a message telling how to fix it.
+type RepoError struct {
+	Kind ErrorKind
+	Name string
+	// Reset is when the rate limit resets, if known.
+	Reset time.Time
+	Err   error
+}
+
+func (e *RepoError) Error() string {
+	switch e.Kind {
+	case ErrNotFound:
+		return fmt.Sprintf("repository %s not found. Check its name, or run `gh auth refresh -s repo` if it's private.", e.Name)
+	case ErrNoAuth:
+		return fmt.Sprintf("not authorized to access %s. Run `gh auth login` to authenticate.", e.Name)
+	case ErrRateLimited:
+		if e.Reset.IsZero() {
+			return "API rate limit exceeded, try again later."
+		}
+		return fmt.Sprintf("API rate limit exceeded, it resets at %s.", e.Reset.Local().Format("15:04"))
+	default:
+		return e.Err.Error()
+	}
+}
+
+func (e *RepoError) Unwrap() error {
+	return e.Err
+}
+
+// classifyError wraps API errors into a RepoError.
+func classifyError(name string, err error) error {
+	var httpErr api.HTTPError
+	if !errors.As(err, &httpErr) {
+		return err
+	}
+	e := &RepoError{Name: name, Err: err}
+	switch httpErr.StatusCode {
+	case http.StatusNotFound:
+		e.Kind = ErrNotFound
+	case http.StatusUnauthorized:
+		e.Kind = ErrNoAuth
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		e.Kind = ErrNoAuth
+		if httpErr.Headers.Get("X-RateLimit-Remaining") == "0" || httpErr.Headers.Get("Retry-After") != "" ||
+			httpErr.StatusCode == http.StatusTooManyRequests {
+			e.Kind = ErrRateLimited
+			e.Reset = rateLimitReset(httpErr.Headers)
+		}
+	default:
+		return err
+	}
+	return e
+}
+
+// rateLimitReset returns when the rate limit resets according to the
+// response headers, or the zero time if they don't say.
+func rateLimitReset(h http.Header) time.Time {
+	if s, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		return time.Unix(s, 0)
+	}
+	if s, err := strconv.Atoi(h.Get("Retry-After")); err == nil {
+		return time.Now().Add(time.Duration(s) * time.Second)
+	}
+	return time.Time{}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/cli/go-gh/pkg/api"
+)
+
+const (
+	// pageLatency is the typical time taken by a stargazers page.
+	pageLatency = 500 * time.Millisecond
+	// largeFetchPages is the number of stargazers pages above which
+	// interactive views ask before fetching.
+	largeFetchPages = 100
+)
+
+// Estimate is the cost of fetching the stargazers of a repository.
+type Estimate struct {
+	Name  string
+	Stars int
+	// Pages is the number of stargazers pages fetched.
+	Pages int
+	// TooMany is set when the stargazers can't all be listed, and the
+	// history needs --sample.
+	TooMany bool
+	// Core is the core rate limit the requests count against.
+	Core RateLimit
+}
+
+// EstimateFetch fetches the metadata of a repository and the rate limits to
+// estimate the cost of fetching its stargazers, from every nth page when
+// sample is positive.
+func EstimateFetch(client api.RESTClient, name string, sample int) (*Estimate, error) {
+	repo, err := FetchRepo(client, name)
+	if err != nil {
+		return nil, classifyError(name, err)
+	}
+	e := &Estimate{Name: repo.FullName, Stars: repo.StargazersCount, Pages: stars.StargazerPages(repo.StargazersCount)}
+	if e.Name == "" {
+		e.Name = name
+	}
+	switch {
+	case sample > 0:
+		if e.Pages > stars.MaxStargazerPages {
+			e.Pages = stars.MaxStargazerPages
+		}
+		// Every nth page and the last one.
+		e.Pages = (e.Pages+sample-1)/sample + 1
+	case e.Pages > stars.MaxStargazerPages:
+		e.TooMany = true
+	}
+	var result struct {
+		Resources map[string]RateLimit `json:"resources"`
+	}
+	if err := client.Get(rateLimitPath, &result); err != nil {
+		return nil, fmt.Errorf("Error fetching the rate limits: %w", err)
+	}
+	e.Core = result.Resources["core"]
+	return e, nil
+}
+
+// Requests returns the number of API requests of the fetch, the repository
+// metadata included.
+func (e *Estimate) Requests() int {
+	return e.Pages + 1
+}
+
+// Duration returns about how long the fetch takes. The first page is
+// fetched alone, the others concurrently within the request budget. A fetch
+// exceeding the remaining rate limit waits for its reset.
+func (e *Estimate) Duration(now time.Time) time.Duration {
+	batches := 1 + (e.Pages-1+maxRequests-1)/maxRequests
+	d := time.Duration(batches) * pageLatency
+	if e.Requests() > e.Core.Remaining && e.Core.Reset > 0 {
+		if wait := time.Unix(e.Core.Reset, 0).Sub(now); wait > 0 {
+			d += wait
+		}
+	}
+	return d
+}
+
+// Large returns whether the fetch is worth a confirmation.
+func (e *Estimate) Large() bool {
+	return e.Pages > largeFetchPages || e.Requests() > e.Core.Remaining
+}
+
+// WriteEstimate writes the cost of a fetch.
+func WriteEstimate(w io.Writer, e *Estimate) error {
+	now := time.Now()
+	fmt.Fprintf(w, "%s has %d stars.\n", e.Name, e.Stars)
+	if e.TooMany {
+		fmt.Fprintf(w, "Only the first %d stargazers can be listed, run with --sample 10 to approximate the history.\n", stars.MaxStargazerPages*stars.PerPage)
+	} else {
+		fmt.Fprintf(w, "Fetching its stargazers takes %d API requests, about %s.\n", e.Requests(), e.Duration(now).Round(time.Second))
+	}
+	reset := time.Unix(e.Core.Reset, 0).In(timeZone).Format("15:04")
+	_, err := fmt.Fprintf(w, "%d of %d core requests remain, the limit resets at %s.\n", e.Core.Remaining, e.Core.Limit, reset)
+	if err == nil && !e.TooMany && e.Requests() > e.Core.Remaining {
+		_, err = fmt.Fprintf(w, "The fetch exceeds the remaining requests and would wait for the reset.\n")
+	}
+	return err
+}
+
+// confirmFetch writes the cost of a large fetch to w and asks to go on,
+// reading the answer from r.
+func confirmFetch(r io.Reader, w io.Writer, e *Estimate) (bool, error) {
+	if err := WriteEstimate(w, e); err != nil {
+		return false, err
+	}
+	fmt.Fprintf(w, "Fetch it? [y/N] ")
+	answer, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
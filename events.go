@@ -0,0 +1,198 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/go-gh/pkg/api"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	readmePath = "repos/%s/readme"
+	// maxReadmeCommitPages caps the number of README commit pages searched
+	// for badges.
+	maxReadmeCommitPages = 5
+)
+
+// Event is a dated event of the history of a repository, like a release or
+// a post announcing it.
+type Event struct {
+	Date  string `yaml:"date"`
+	Label string `yaml:"label"`
+}
+
+// EventsMsg holds the events found in the timeline of a repository.
+type EventsMsg struct {
+	Events []Event
+	Err    error
+}
+
+// LoadAnnotations reads the custom events of a YAML file, a list of dates and
+// labels, sorted by date.
+func LoadAnnotations(path string) ([]Event, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var list []Event
+	if err := yaml.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("reading annotations %s: %w", path, err)
+	}
+	for i, e := range list {
+		if _, err := time.Parse("2006-01-02", e.Date); err != nil {
+			return nil, fmt.Errorf("annotation %d of %s: invalid date %q, expected YYYY-MM-DD", i+1, path, e.Date)
+		}
+		if e.Label == "" {
+			return nil, fmt.Errorf("annotation %d of %s: missing label", i+1, path)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Date < list[j].Date })
+	return list, nil
+}
+
+// FetchEvents fetches the significant events of the timeline of a
+// repository: its first release and the commits adding badges to its README,
+// sorted by date.
+func FetchEvents(client api.RESTClient, name string) ([]Event, error) {
+	list := make([]Event, 0)
+	releases, err := FetchReleases(client, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(releases) > 0 {
+		list = append(list, Event{Date: releases[0].Date(), Label: "First release " + releases[0].TagName})
+	}
+	badges, err := fetchBadgeCommits(client, name)
+	if err != nil {
+		return nil, err
+	}
+	list = append(list, badges...)
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Date < list[j].Date })
+	return list, nil
+}
+
+// fetchBadgeCommits returns the commits touching the README of a repository
+// that mention badges in their message.
+func fetchBadgeCommits(client api.RESTClient, name string) ([]Event, error) {
+	var readme struct {
+		Path string `json:"path"`
+	}
+	if err := client.Get(fmt.Sprintf(readmePath, name), &readme); err != nil {
+		var httpErr api.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("Error fetching the README: %w", err)
+	}
+	path := fmt.Sprintf(commitsPath+"?path=%s", name, url.QueryEscape(readme.Path))
+	p := stars.NewPaginator(client, path, stars.PerPage)
+	list := make([]Event, 0)
+	for page := 1; page <= maxReadmeCommitPages; page++ {
+		var commits []struct {
+			Commit struct {
+				Message string `json:"message"`
+				Author  struct {
+					Date time.Time `json:"date"`
+				} `json:"author"`
+			} `json:"commit"`
+		}
+		ok, err := p.Next(&commits)
+		if err != nil {
+			return nil, fmt.Errorf("Error fetching README commits page %d: %w", page, err)
+		}
+		if !ok {
+			break
+		}
+		for _, c := range commits {
+			subject, _, _ := strings.Cut(c.Commit.Message, "\n")
+			if strings.Contains(strings.ToLower(subject), "badge") {
+				list = append(list, Event{Date: dayOf(c.Commit.Author.Date), Label: "README: " + subject})
+			}
+		}
+	}
+	return list, nil
+}
+
+// events overlays the events of the timeline of the repository and the
+// custom annotations on the graph.
+type events struct {
+	active  bool
+	loading bool
+	loaded  bool
+	// custom are the events of the --annotations file.
+	custom []Event
+	list   []Event
+	err    error
+}
+
+// Active returns whether events are shown.
+func (e *events) Active() bool {
+	return e.active
+}
+
+// Toggle shows or hides the events, fetching them the first time.
+func (e *events) Toggle(r *Repo) tea.Cmd {
+	e.active = !e.active
+	return e.Load(r)
+}
+
+// Load fetches the events while they're shown unless they were already
+// fetched. Only the custom events are shown offline.
+func (e *events) Load(r *Repo) tea.Cmd {
+	if !e.active || e.loading || e.loaded || len(r.stargazers) == 0 {
+		return nil
+	}
+	if r.offline {
+		e.loaded = true
+		return nil
+	}
+	e.loading = true
+	client, name := r.client, r.name
+	return func() tea.Msg {
+		list, err := FetchEvents(client, name)
+		return EventsMsg{Events: list, Err: err}
+	}
+}
+
+// SetEvents stores the fetched events.
+func (e *events) SetEvents(msg EventsMsg) {
+	e.loading = false
+	e.loaded = true
+	e.list = msg.Events
+	e.err = msg.Err
+}
+
+// View renders a marker under each event and a legend of the most recent
+// ones.
+func (e *events) View(r *Repo, keys []string) string {
+	switch {
+	case e.loading:
+		return fmt.Sprintf("\n %s loading events...", r.spinner.View())
+	case e.err != nil:
+		return fmt.Sprintf("\n Error: %s", e.err)
+	}
+	all := append(append(make([]Event, 0, len(e.custom)+len(e.list)), e.custom...), e.list...)
+	if len(all) == 0 {
+		return "\n No events found."
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Date < all[j].Date })
+	dates := make([]string, len(all))
+	for i, ev := range all {
+		dates[i] = ev.Date
+	}
+	legend := make([]string, 0)
+	for i := len(all) - 1; i >= 0 && len(legend) < 5; i-- {
+		legend = append(legend, fmt.Sprintf("%s (%s)", all[i].Label, all[i].Date))
+	}
+	return markerLine(r, keys, dates, "▼") + "\n" +
+		fmt.Sprintf(" ▼ %d events, latest: %s", len(all), strings.Join(legend, ", "))
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// explanation details how the number of the selected row was computed.
+type explanation struct {
+	active bool
+	title  string
+	lines  []string
+}
+
+// Active returns whether the explanation is shown.
+func (e *explanation) Active() bool {
+	return e.active
+}
+
+// Stop hides the explanation.
+func (e *explanation) Stop() {
+	e.active = false
+}
+
+// Toggle explains the selected row of the current view, or hides the
+// explanation.
+func (e *explanation) Toggle(r *Repo) {
+	if e.active {
+		e.active = false
+		return
+	}
+	switch {
+	case r.view == viewGraph && r.pinned.Active():
+		e.title, e.lines = r.explainPinned()
+	case r.view == viewTable || r.view == viewGraph && r.split():
+		e.title, e.lines = r.explainDay()
+	case r.view == viewOrgs:
+		e.title, e.lines = r.explainOrg()
+	case r.view == viewQuality:
+		e.title, e.lines = r.explainQuality()
+	case r.view == viewNotable:
+		e.title, e.lines = r.explainNotable()
+	default:
+		return
+	}
+	e.active = e.title != ""
+}
+
+func (e *explanation) View(r *Repo) string {
+	body := r.theme.AccentStyle().Bold(true).Render(e.title) + "\n\n" + strings.Join(e.lines, "\n") +
+		"\n\n" + lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf("%s/%s close", r.keys.Explain.Help().Key, r.keys.Stop.Help().Key))
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(r.theme.Accent).
+		Padding(0, 1).
+		Width(min(r.width-4, 72)).
+		Render(body)
+	return lipgloss.Place(r.width, r.height, lipgloss.Center, lipgloss.Center, box)
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// explainSource describes where the daily counts come from.
+func (r *Repo) explainSource() []string {
+	lines := make([]string, 0)
+	switch {
+	case !r.stale.IsZero():
+		lines = append(lines, fmt.Sprintf("Counts come from the cache, last fetched on %s.", r.stale.Local().Format("2006-01-02 15:04")))
+	case r.offline:
+		lines = append(lines, "Counts come from an imported file.")
+	default:
+		lines = append(lines, "Counts come from the starred date of each stargazer listed by the GitHub API.")
+	}
+	lines = append(lines, fmt.Sprintf("Days are counted in the %s time zone.", timeZone))
+	if len(r.sources) > 1 {
+		lines = append(lines, "Stargazers of the aliases are added: "+sourcesCaption(r.sources)+".")
+	}
+	return lines
+}
+
+// explainDay explains the selected day of the table.
+func (r *Repo) explainDay() (string, []string) {
+	day := r.table.SelectedDay()
+	if day == "" {
+		return "", nil
+	}
+	count := r.stargazers[day]
+	keys := r.graph.Keys()
+	i := sort.SearchStrings(keys, day)
+	var total int
+	for _, k := range keys[:i+1] {
+		total += r.stargazers[k]
+	}
+	lines := []string{
+		fmt.Sprintf("%d stargazers starred the repository on %s, %d in total by the end of the day.", count, day, total),
+	}
+	if i > 0 {
+		prev, _ := time.Parse("2006-01-02", keys[i-1])
+		cur, _ := time.Parse("2006-01-02", day)
+		if gap := int(cur.Sub(prev).Hours()/24) - 1; gap > 0 {
+			lines = append(lines, fmt.Sprintf("No stars in the %d days before, since %s.", gap, keys[i-1]))
+		}
+	}
+	if lost := r.unstars[day]; lost > 0 {
+		lines = append(lines, fmt.Sprintf("%d stargazers were found missing on this day. Unstars aren't subtracted, GitHub only lists current stargazers so their stars are gone from their day.", lost))
+	} else {
+		lines = append(lines, "Stargazers who unstarred since aren't counted, GitHub only lists current stargazers.")
+	}
+	lines = append(lines, r.explainSource()...)
+	lines = append(lines, "The table shows raw counts, the graph smoothing and bucketing don't apply.")
+	return day, lines
+}
+
+// explainPinned explains the selected day of the pinned window.
+func (r *Repo) explainPinned() (string, []string) {
+	row := r.pinned.table.SelectedRow()
+	if row == nil {
+		return "", nil
+	}
+	n, _ := strconv.Atoi(row[0])
+	prev := r.pinned.end.AddDate(0, 0, n-2*pinDays).Format("2006-01-02")
+	lines := []string{
+		fmt.Sprintf("Day %s of the %d days ending %s gained %s stars.", row[0], pinDays, r.pinned.end.Format("2006-01-02"), row[2]),
+		fmt.Sprintf("The same day of the previous %d days, %s, gained %s stars.", pinDays, prev, row[3]),
+		fmt.Sprintf("The delta is %s stars.", row[4]),
+	}
+	lines = append(lines, r.explainSource()...)
+	return row[1], lines
+}
+
+// explainOrg explains the selected organization.
+func (r *Repo) explainOrg() (string, []string) {
+	row := r.orgs.table.SelectedRow()
+	if row == nil {
+		return "", nil
+	}
+	return row[0], []string{
+		fmt.Sprintf("%s of the %d most recent stargazers list %s among their public organizations.", row[1], r.orgs.sampled, row[0]),
+		"Private memberships aren't visible, so the count is a lower bound.",
+	}
+}
+
+// explainQuality explains why the selected account was flagged.
+func (r *Repo) explainQuality() (string, []string) {
+	row := r.quality.table.SelectedRow()
+	if row == nil {
+		return "", nil
+	}
+	return row[0], []string{
+		fmt.Sprintf("%s was created on %s and has %s public repositories and %s followers.", row[0], row[1], row[2], row[3]),
+		"Accounts without repositories or followers are flagged.",
+		fmt.Sprintf("Accounts created the same week as at least %d others of the %d most recent stargazers are flagged.", qualityClusterSize-1, r.quality.sampled),
+		"Signals: " + row[4] + ".",
+		"Accounts are cached for a week.",
+	}
+}
+
+// explainNotable explains the follower count of the selected stargazer.
+func (r *Repo) explainNotable() (string, []string) {
+	row := r.notable.table.SelectedRow()
+	if row == nil {
+		return "", nil
+	}
+	return row[0], []string{
+		fmt.Sprintf("%s has %s followers and starred the repository on %s.", row[0], row[1], row[2]),
+		fmt.Sprintf("Only the %d most recent stargazers are ranked.", r.notable.sampled),
+		"Follower counts come from the public profile, cached for a week.",
+	}
+}
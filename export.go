@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/cli/go-gh/pkg/browser"
+	"github.com/spf13/pflag"
+)
+
+const starHistoryURL = "https://star-history.com/#%s&Date"
+
+// StarHistoryURL returns the star-history.com chart comparing repos.
+func StarHistoryURL(repos ...string) string {
+	escaped := make([]string, len(repos))
+	for i, repo := range repos {
+		escaped[i] = url.PathEscape(repo)
+		// Keep the owner/repo separator readable.
+		escaped[i] = strings.ReplaceAll(escaped[i], "%2F", "/")
+	}
+	return strings.Replace(starHistoryURL, "%s", strings.Join(escaped, "&"), 1)
+}
+
+// OpenWeb opens the star-history.com chart comparing repos in the browser.
+func OpenWeb(stdout io.Writer, repos ...string) error {
+	b := browser.New("", stdout, os.Stderr)
+	return b.Browse(StarHistoryURL(repos...))
+}
+
+// ExportStarHistoryCSV writes the cumulative stargazers of each day with new
+// stargazers in the CSV format of star-history.com exports.
+func ExportStarHistoryCSV(w io.Writer, entries ...*stars.History) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Repository", "Date", "Stars"}); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		days := make([]string, 0, len(entry.Stargazers))
+		for day := range entry.Stargazers {
+			days = append(days, day)
+		}
+		sort.Strings(days)
+		var total int
+		for _, day := range days {
+			total += entry.Stargazers[day]
+			t, err := time.Parse("2006-01-02", day)
+			if err != nil {
+				return err
+			}
+			if err := cw.Write([]string{entry.Name, t.Format("Mon Jan 02 2006"), strconv.Itoa(total)}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func runExport(args []string) {
+	flags := pflag.NewFlagSet("export", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gh stars export [repository] [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Write the stargazers history of a repository, the current one by default.\n\n")
+		flags.PrintDefaults()
+	}
+	output := flags.StringP("output", "o", "-", "file to write, - for stdout")
+	format := flags.String("format", "csv", "csv for star-history.com compatible files, json, openmetrics for Prometheus, or ipynb for a Jupyter notebook, the default for .ipynb outputs")
+	offline := flags.Bool("offline", false, "export the cached data without making API calls")
+	importFile := flags.String("import", "", "convert a previously exported .csv or .json file")
+	tz := flags.String("tz", "UTC", "time zone of the days stargazers are counted in: UTC, local, or an IANA name")
+	stream := flags.Bool("stream", false, "print each stargazer as a JSON line to stdout as pages are fetched")
+	addTokenFlag(flags)
+	addMaxRequestsFlag(flags)
+	addProfileFlag(flags)
+	flags.Parse(args)
+	defer writeProfile()
+	var err error
+	timeZone, err = ParseTimeZone(*tz)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	repo := flags.Arg(0)
+	if repo == "" && *importFile == "" {
+		repo = currentRepo()
+	}
+	if repo == "" && *importFile == "" {
+		fmt.Fprintf(os.Stderr, "Error: no repository specified\n\n")
+		flags.Usage()
+		os.Exit(1)
+	}
+	if *stream {
+		if *offline || *importFile != "" {
+			log.Fatalln("--stream fetches the stargazers, it can't be used with --offline or --import")
+		}
+		client, err := NewClient()
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if err := StreamStargazers(client, repo, os.Stdout); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+	if isNotebook(*output) && !flags.Changed("format") {
+		*format = "ipynb"
+	}
+	var write func(io.Writer, *stars.History) error
+	switch *format {
+	case "csv":
+		write = func(w io.Writer, entry *stars.History) error {
+			return ExportStarHistoryCSV(w, entry)
+		}
+	case "json":
+		write = func(w io.Writer, entry *stars.History) error {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			return enc.Encode(entry)
+		}
+	case "openmetrics":
+		write = func(w io.Writer, entry *stars.History) error {
+			return ExportOpenMetrics(w, entry)
+		}
+	case "ipynb":
+		write = ExportNotebook
+	default:
+		log.Fatalf("unknown format %q, expected csv, json, openmetrics, or ipynb", *format)
+	}
+	entry, err := exportEntry(repo, *importFile, *offline)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if *output == "-" {
+		if err := write(os.Stdout, entry); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if err := write(f, entry); err != nil {
+		f.Close()
+		log.Fatalln(err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// exportEntry returns the history to export, read from file if set.
+func exportEntry(repo, file string, offline bool) (*stars.History, error) {
+	if file != "" {
+		entry, err := ImportFile(file)
+		if err == nil && repo != "" {
+			entry.Name = repo
+		}
+		return entry, err
+	}
+	if offline {
+		return loadStale(repo)
+	}
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return LoadHistory(client, repo, 0, false)
+}
+
+// streamedStargazer is a stargazer printed by StreamStargazers.
+type streamedStargazer struct {
+	Login     string    `json:"login"`
+	StarredAt time.Time `json:"starred_at"`
+}
+
+// StreamStargazers writes each stargazer of a repository to w as a JSON
+// line, oldest first, as the pages are fetched.
+func StreamStargazers(client api.RESTClient, name string, w io.Writer) error {
+	enc := json.NewEncoder(w)
+	f := stars.NewFetcher(client)
+	f.Warn = logAnomalies(name)
+	err := f.Stream(name, func(s stars.Stargazer) error {
+		return enc.Encode(streamedStargazer{Login: s.User.Login, StarredAt: s.StarredAt})
+	})
+	return classifyError(name, err)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+)
+
+func TestStarHistoryURL(t *testing.T) {
+	tests := []struct {
+		repos []string
+		want  string
+	}{
+		{[]string{"charmbracelet/bubbletea"}, "https://star-history.com/#charmbracelet/bubbletea&Date"},
+		{[]string{"charmbracelet/bubbletea", "charmbracelet/lipgloss"}, "https://star-history.com/#charmbracelet/bubbletea&charmbracelet/lipgloss&Date"},
+		{[]string{"owner/repo name"}, "https://star-history.com/#owner/repo%20name&Date"},
+	}
+	for _, tt := range tests {
+		if got := StarHistoryURL(tt.repos...); got != tt.want {
+			t.Errorf("StarHistoryURL(%q) = %q, want %q", tt.repos, got, tt.want)
+		}
+	}
+}
+
+func TestExportStarHistoryCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []*stars.History
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "no repositories",
+			want: "Repository,Date,Stars\n",
+		},
+		{
+			name: "cumulative stars in chronological order",
+			entries: []*stars.History{
+				{Name: "owner/repo", Stargazers: stars.Timeline{"2024-01-03": 2, "2024-01-01": 1}},
+			},
+			want: "Repository,Date,Stars\n" +
+				"owner/repo,Mon Jan 01 2024,1\n" +
+				"owner/repo,Wed Jan 03 2024,3\n",
+		},
+		{
+			name: "several repositories",
+			entries: []*stars.History{
+				{Name: "owner/a", Stargazers: stars.Timeline{"2024-01-01": 1}},
+				{Name: "owner/b", Stargazers: stars.Timeline{"2024-01-02": 5}},
+			},
+			want: "Repository,Date,Stars\n" +
+				"owner/a,Mon Jan 01 2024,1\n" +
+				"owner/b,Tue Jan 02 2024,5\n",
+		},
+		{
+			name:    "invalid day",
+			entries: []*stars.History{{Name: "owner/repo", Stargazers: stars.Timeline{"01/02/2024": 1}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			err := ExportStarHistoryCSV(&b, tt.entries...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExportStarHistoryCSV() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && b.String() != tt.want {
+				t.Errorf("ExportStarHistoryCSV() =\n%s\nwant\n%s", b.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/spf13/pflag"
+)
+
+// ExportedUser is a stargazer written by gh stars export-users, with the
+// profile fields of its account when enriched.
+type ExportedUser struct {
+	Login     string    `json:"login"`
+	ID        int64     `json:"id"`
+	StarredAt time.Time `json:"starred_at"`
+	Profile   *Account  `json:"profile,omitempty"`
+}
+
+// ExportUsers returns the stargazers of a repository oldest first, with
+// their accounts if enrich is set. Interrupted fetches resume from the pages
+// and the accounts kept in the cache.
+func ExportUsers(client api.RESTClient, name string, enrich bool) ([]ExportedUser, error) {
+	repo, err := FetchRepo(client, name)
+	if err != nil {
+		return nil, classifyError(name, err)
+	}
+	name = canonicalName(name, repo)
+	stargazers, err := FetchStargazers(client, name, repo.StargazersCount, nil, logAnomalies(name))
+	if err != nil {
+		return nil, classifyError(name, err)
+	}
+	users := make([]ExportedUser, len(stargazers))
+	logins := make([]string, len(stargazers))
+	for i, s := range stargazers {
+		users[i] = ExportedUser{Login: s.User.Login, ID: s.User.ID, StarredAt: s.StarredAt}
+		logins[i] = s.User.Login
+	}
+	if !enrich {
+		return users, nil
+	}
+	accounts, err := FetchAccounts(client, logins)
+	if err != nil {
+		return nil, classifyError(name, err)
+	}
+	for i := range accounts {
+		users[i].Profile = &accounts[i]
+	}
+	return users, nil
+}
+
+// writeUsers writes users as an indented JSON array.
+func writeUsers(w io.Writer, users []ExportedUser) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(users)
+}
+
+func runExportUsers(args []string) {
+	flags := pflag.NewFlagSet("export-users", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gh stars export-users [repository] [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Write the stargazers of a repository, the current one by default, as JSON.\n\n")
+		flags.PrintDefaults()
+	}
+	output := flags.StringP("output", "o", "-", "file to write, - for stdout")
+	enrich := flags.Bool("enrich", false, "add the profile of each stargazer, one request per stargazer")
+	addTokenFlag(flags)
+	addMaxRequestsFlag(flags)
+	flags.Parse(args)
+	repo := flags.Arg(0)
+	if repo == "" {
+		repo = currentRepo()
+	}
+	if repo == "" {
+		fmt.Fprintf(os.Stderr, "Error: no repository specified\n\n")
+		flags.Usage()
+		os.Exit(1)
+	}
+	client, err := NewClient()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	users, err := ExportUsers(client, repo, *enrich)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if *output == "-" {
+		if err := writeUsers(os.Stdout, users); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if err := writeUsers(f, users); err != nil {
+		f.Close()
+		log.Fatalln(err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatalln(err)
+	}
+}
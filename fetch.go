@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/cli/go-gh"
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/spf13/pflag"
+	"golang.org/x/sync/singleflight"
+)
+
+// authToken is the token given with --token.
+var authToken string
+
+// addTokenFlag adds the --token flag to the flags of a command making API
+// calls.
+func addTokenFlag(flags *pflag.FlagSet) {
+	flags.StringVar(&authToken, "token", "", "GitHub token to authenticate with instead of gh, defaults to $GH_TOKEN or $GITHUB_TOKEN")
+}
+
+// addMaxRequestsFlag adds the --max-requests flag to the flags of a command
+// fetching stargazers, whose pages are fetched concurrently.
+func addMaxRequestsFlag(flags *pflag.FlagSet) {
+	flags.IntVar(&maxRequests, "max-requests", defaultMaxRequests, "number of API requests in flight at once, shared by every repository fetched")
+}
+
+// clientOptions returns the options of the API clients. A token given with
+// --token, GH_TOKEN, or GITHUB_TOKEN is used for GH_HOST, github.com by
+// default, without reading the gh config so gh-stars runs in CI jobs and
+// containers where gh isn't installed.
+func clientOptions() *api.ClientOptions {
+	opts := &api.ClientOptions{}
+	for _, token := range []string{authToken, os.Getenv("GH_TOKEN"), os.Getenv("GITHUB_TOKEN")} {
+		if token != "" {
+			opts.AuthToken = token
+			break
+		}
+	}
+	if opts.AuthToken != "" {
+		opts.Host = os.Getenv("GH_HOST")
+		if opts.Host == "" {
+			opts.Host = "github.com"
+		}
+	}
+	return opts
+}
+
+// NewClient returns a REST client that includes the starred date in
+// stargazers responses.
+func NewClient() (api.RESTClient, error) {
+	if maxRequests < 1 {
+		return nil, fmt.Errorf("invalid --max-requests %d, expected at least 1", maxRequests)
+	}
+	opts := clientOptions()
+	opts.Headers = map[string]string{
+		"Accept": "application/vnd.github.v3.star+json",
+	}
+	opts.Transport = countingTransport{base: http.DefaultTransport}
+	client, err := gh.RESTClient(opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w, run `gh auth login` or set GH_TOKEN to authenticate", err)
+	}
+	return client, nil
+}
+
+// FetchRepo fetches the metadata of a repository.
+func FetchRepo(client api.RESTClient, name string) (RepoMsg, error) {
+	repo, err := stars.NewFetcher(client).Repository(name)
+	return RepoMsg(repo), err
+}
+
+// canonicalName returns the canonical name of a repository and migrates its
+// cache entry if it was renamed. The API follows renames and transfers, so
+// the canonical name can differ from the requested one.
+func canonicalName(name string, repo RepoMsg) string {
+	if repo.FullName == "" || repo.FullName == name {
+		return name
+	}
+	if !strings.EqualFold(name, repo.FullName) {
+		log.Printf("%s → %s", name, repo.FullName)
+	}
+	if err := MigrateCache(name, repo.FullName); err != nil {
+		log.Printf("migrating cache from %s to %s: %v", name, repo.FullName, err)
+	}
+	return repo.FullName
+}
+
+// renamedBanner notes that the repository was opened with its former name.
+func (r *Repo) renamedBanner() string {
+	return r.theme.AccentStyle().Render(fmt.Sprintf(" %s → %s", r.renamed, r.name))
+}
+
+// failedPages are the stargazers pages that failed to be fetched, retried
+// with R.
+type failedPages struct {
+	failed int
+	pages  int
+}
+
+// logAnomalies returns a function logging the anomalies of the starred dates
+// of a repository, for the commands without a TUI.
+func logAnomalies(name string) func(stars.Anomalies) {
+	return func(a stars.Anomalies) {
+		log.Printf("Warning: %s: %s", name, a)
+	}
+}
+
+// anomaliesBanner warns that starred dates were in the future.
+func (r *Repo) anomaliesBanner() string {
+	return r.theme.WarningStyle().Render(" " + r.anomalies.String() + ", check the clock of this machine")
+}
+
+// failedBanner warns that some stargazers are missing.
+func (r *Repo) failedBanner() string {
+	return r.theme.WarningStyle().Render(fmt.Sprintf(" %d of %d pages failed, press %s to retry the failed pages", r.failed.failed, r.failed.pages, r.keys.Retry.Help().Key))
+}
+
+// FetchStargazers fetches all the stargazers of a repository sorted by
+// starred date, calling progress when set after each page, and warn when set
+// if starred dates were in the future. The complete pages of an interrupted
+// fetch are kept in the cache and reused by the next one.
+func FetchStargazers(client api.RESTClient, name string, count int, progress func(done, total int), warn func(stars.Anomalies)) ([]stars.Stargazer, error) {
+	f := stars.NewFetcher(client)
+	f.Progress = progress
+	f.Warn = warn
+	if cache, err := historyCache(); err == nil {
+		f.Pages = cache.Pages()
+	}
+	return f.Stargazers(name, count)
+}
+
+// NewHistory returns the cache entry of a repository from its stargazers.
+// Aliases and unstars of an existing cache entry are kept, and the
+// stargazers missing since it was saved are counted as unstars.
+func NewHistory(name string, count int, stargazers []stars.Stargazer) *stars.History {
+	entry := stars.NewHistory(name, count, stargazers, dailyBucketer())
+	if cached, err := LoadCache(name); err == nil && cached != nil {
+		countUnstars(cached, entry.Logins, time.Now())
+		entry.Aliases, entry.Unstars, entry.Unstarred = cached.Aliases, cached.Unstars, cached.Unstarred
+	}
+	return entry
+}
+
+// FetchHistory fetches the stargazers history of a repository and caches
+// it.
+func FetchHistory(client api.RESTClient, name string) (*stars.History, error) {
+	repo, err := FetchRepo(client, name)
+	if err != nil {
+		return nil, err
+	}
+	name = canonicalName(name, repo)
+	stargazers, err := FetchStargazers(client, name, repo.StargazersCount, nil, logAnomalies(name))
+	if err != nil {
+		return nil, err
+	}
+	entry := NewHistory(name, repo.StargazersCount, stargazers)
+	if err := SaveCache(entry); err != nil {
+		log.Printf("saving cache: %v", err)
+	}
+	return entry, nil
+}
+
+// historyLoads shares the concurrent loads of a repository between the views
+// loading it, like a dashboard listing it in two groups.
+var historyLoads singleflight.Group
+
+// dedupeRepos returns repos without the repositories listed twice, which are
+// case-insensitive, keeping the first of each.
+func dedupeRepos(repos []string) []string {
+	seen := make(map[string]bool, len(repos))
+	deduped := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		if key := strings.ToLower(repo); !seen[key] {
+			seen[key] = true
+			deduped = append(deduped, repo)
+		}
+	}
+	return deduped
+}
+
+// LoadHistory returns the cached history of a repository if it's more recent
+// than maxAge, otherwise it fetches it. Older cached data is returned when
+// offline or when the network is unavailable. Concurrent loads of the same
+// repository share their result.
+func LoadHistory(client api.RESTClient, name string, maxAge time.Duration, offline bool) (*stars.History, error) {
+	key := fmt.Sprintf("%s %t", strings.ToLower(name), offline)
+	v, err, _ := historyLoads.Do(key, func() (interface{}, error) {
+		return loadHistory(client, name, maxAge, offline)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*stars.History), nil
+}
+
+func loadHistory(client api.RESTClient, name string, maxAge time.Duration, offline bool) (*stars.History, error) {
+	if offline {
+		return loadStale(name)
+	}
+	cached, err := LoadCache(name)
+	if err == nil && cached != nil && time.Since(cached.UpdatedAt) <= maxAge {
+		return cached, nil
+	}
+	entry, err := FetchHistory(client, name)
+	if isNetworkError(err) && cached != nil {
+		return cached, nil
+	}
+	if err != nil {
+		return nil, classifyError(name, err)
+	}
+	return entry, nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ParseStarsFilter parses a filter of the stars of a day: >N, >=N, <N, <=N,
+// N for exactly N stars, or N..M for N to M stars.
+func ParseStarsFilter(expr string) (func(stars int) bool, error) {
+	expr = strings.TrimSpace(expr)
+	number := func(s string) (int, error) {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid filter %q, expected e.g. >5, <=2, 3, or 10..20", expr)
+		}
+		return n, nil
+	}
+	if from, to, ok := strings.Cut(expr, ".."); ok {
+		min, err := number(from)
+		if err != nil {
+			return nil, err
+		}
+		max, err := number(to)
+		if err != nil {
+			return nil, err
+		}
+		return func(stars int) bool { return stars >= min && stars <= max }, nil
+	}
+	// Two character operators go first so > doesn't match >=.
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if !strings.HasPrefix(expr, op) {
+			continue
+		}
+		n, err := number(expr[len(op):])
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case ">=":
+			return func(stars int) bool { return stars >= n }, nil
+		case "<=":
+			return func(stars int) bool { return stars <= n }, nil
+		case ">":
+			return func(stars int) bool { return stars > n }, nil
+		case "<":
+			return func(stars int) bool { return stars < n }, nil
+		}
+		return func(stars int) bool { return stars == n }, nil
+	}
+	n, err := number(expr)
+	if err != nil {
+		return nil, err
+	}
+	return func(stars int) bool { return stars == n }, nil
+}
+
+// filterTable shows only the days of the table matching a stars filter
+// typed in the prompt, or all of them when it's empty.
+func (r *Repo) filterTable(expr string) tea.Cmd {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		r.tableFilter = ""
+		r.table.SetFilter(nil)
+		return r.toast.Show("Showing all days")
+	}
+	keep, err := ParseStarsFilter(expr)
+	if err != nil {
+		return r.toast.Show(err.Error())
+	}
+	r.tableFilter = expr
+	r.table.SetFilter(keep)
+	return r.toast.Show(fmt.Sprintf("Showing the %d days with %s stars, / to change", len(r.table.Rows()), expr))
+}
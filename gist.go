@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/spf13/pflag"
+)
+
+const (
+	gistsPath = "gists"
+	gistPath  = "gists/%s"
+)
+
+// Gist is a GitHub gist.
+type Gist struct {
+	ID      string `json:"id"`
+	HTMLURL string `json:"html_url"`
+}
+
+// PublishGist uploads content as a gist file and returns the gist. Gists are
+// secret unless public is set.
+func PublishGist(client api.RESTClient, filename, description, content string, public bool) (*Gist, error) {
+	body, err := jsonBody(map[string]interface{}{
+		"description": description,
+		"public":      public,
+		"files": map[string]interface{}{
+			filename: map[string]string{"content": content},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var gist Gist
+	if err := client.Post(gistsPath, body, &gist); err != nil {
+		return nil, err
+	}
+	return &gist, nil
+}
+
+// gistID returns the ID of a gist given as an ID or a URL.
+func gistID(s string) string {
+	return path.Base(strings.TrimSuffix(s, "/"))
+}
+
+// FetchGistHistory reads the history published to a gist by gh stars
+// publish, from its first JSON file.
+func FetchGistHistory(client api.RESTClient, id string) (*stars.History, error) {
+	var gist struct {
+		Files map[string]struct {
+			RawURL    string `json:"raw_url"`
+			Truncated bool   `json:"truncated"`
+			Content   string `json:"content"`
+		} `json:"files"`
+	}
+	if err := client.Get(fmt.Sprintf(gistPath, gistID(id)), &gist); err != nil {
+		return nil, fmt.Errorf("Error fetching gist %s: %w", id, err)
+	}
+	names := make([]string, 0, len(gist.Files))
+	for name := range gist.Files {
+		if strings.HasSuffix(strings.ToLower(name), ".json") {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("gist %s has no JSON file, publish one with gh stars publish", id)
+	}
+	sort.Strings(names)
+	file := gist.Files[names[0]]
+	data := []byte(file.Content)
+	// The API truncates the content of large files, which are read from
+	// their raw URL instead.
+	if file.Truncated {
+		var raw json.RawMessage
+		if err := client.Get(file.RawURL, &raw); err != nil {
+			return nil, fmt.Errorf("Error fetching %s of gist %s: %w", names[0], id, err)
+		}
+		data = raw
+	}
+	entry, err := importJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s of gist %s: %w", names[0], id, err)
+	}
+	if entry.Name == "" {
+		entry.Name = strings.TrimSuffix(names[0], path.Ext(names[0]))
+	}
+	if entry.Stars == 0 {
+		for _, count := range entry.Stargazers {
+			entry.Stars += count
+		}
+	}
+	return entry, nil
+}
+
+// publishedHistory returns the part of a history published to gists: its
+// name, stars, and stars per day. Who starred the repository and when isn't
+// published.
+func publishedHistory(entry *stars.History) *stars.History {
+	return &stars.History{
+		Name:       entry.Name,
+		Stars:      entry.Stars,
+		Stargazers: entry.Stargazers,
+		UpdatedAt:  entry.UpdatedAt,
+	}
+}
+
+// checkPublic returns an error unless a repository is known to be public.
+// Offline, its visibility can't be checked.
+func checkPublic(client api.RESTClient, name string, offline bool) error {
+	if offline {
+		return fmt.Errorf("the visibility of %s can't be checked offline, run with --allow-private to publish it to a public gist anyway", name)
+	}
+	repo, err := FetchRepo(client, name)
+	if err != nil {
+		return classifyError(name, err)
+	}
+	if repo.Private {
+		return fmt.Errorf("%s is private, run with --allow-private to publish its history to a public gist anyway", name)
+	}
+	return nil
+}
+
+func runPublish(args []string) {
+	flags := pflag.NewFlagSet("publish", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gh stars publish [repository] [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Upload the stargazers history of a repository, the current one by default,\n")
+		fmt.Fprintf(os.Stderr, "to a secret gist and print its URL, viewed with gh stars --from-gist.\n\n")
+		flags.PrintDefaults()
+	}
+	public := flags.Bool("public", false, "make the gist public")
+	allowPrivate := flags.Bool("allow-private", false, "allow publishing the history of a private repository to a public gist")
+	offline := flags.Bool("offline", false, "publish the cached data without fetching the repository")
+	addTokenFlag(flags)
+	addMaxRequestsFlag(flags)
+	flags.Parse(args)
+	repo := flags.Arg(0)
+	if repo == "" {
+		repo = currentRepo()
+	}
+	if repo == "" {
+		fmt.Fprintf(os.Stderr, "Error: no repository specified\n\n")
+		flags.Usage()
+		os.Exit(1)
+	}
+	client, err := NewClient()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if *public && !*allowPrivate {
+		if err := checkPublic(client, repo, *offline); err != nil {
+			log.Fatalln(err)
+		}
+	}
+	entry, err := exportEntry(repo, "", *offline)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	data, err := json.MarshalIndent(publishedHistory(entry), "", "  ")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	gist, err := PublishGist(client, strings.ReplaceAll(entry.Name, "/", "-")+".json",
+		fmt.Sprintf("%s stargazers history", entry.Name), string(data), *public)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(gist.HTMLURL)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/starsui"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// imageDelay is how long drawing an image waits for Bubble Tea to render the
+// blank lines it covers.
+const imageDelay = 50 * time.Millisecond
+
+// DetectGraphics returns the graphics protocol supported by the terminal,
+// guessed from its environment, or ascii if there is none.
+func DetectGraphics() string {
+	term, program := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "", term == "xterm-kitty", program == "WezTerm", program == "ghostty":
+		return starsui.GraphicsKitty
+	case program == "iTerm.app", strings.HasPrefix(term, "foot"), strings.HasPrefix(term, "mlterm"), strings.Contains(term, "sixel"):
+		return starsui.GraphicsSixel
+	}
+	return starsui.GraphicsASCII
+}
+
+// ParseGraphics parses a graphics protocol, auto to detect it.
+func ParseGraphics(name string) (string, error) {
+	if name == "auto" {
+		return DetectGraphics(), nil
+	}
+	for _, p := range starsui.GraphicsProtocols {
+		if name == p {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("unknown graphics %q, expected auto, %s", name, strings.Join(starsui.GraphicsProtocols, ", "))
+}
+
+// graphImageMsg draws the image of a frame unless the frame changed since.
+type graphImageMsg struct {
+	frame string
+}
+
+// graphImage draws the graph as an image with a terminal graphics protocol.
+// The graph view renders blank lines, and the image is written to the
+// terminal over them once Bubble Tea rendered them, as it can't render
+// images itself. It's drawn again whenever the frame changes, as repainted
+// lines erase it.
+type graphImage struct {
+	protocol string
+	// shown is whether the last frame shows the graph as an image, seq.
+	shown bool
+	seq   string
+	// row is the terminal row of the top of the graph, from 1.
+	row   int
+	frame string
+	drawn bool
+}
+
+// Enabled returns whether graphs are drawn as images.
+func (g *graphImage) Enabled() bool {
+	return g.protocol != "" && g.protocol != starsui.GraphicsASCII
+}
+
+// Placeholder returns height blank lines for the image of graph to be drawn
+// over.
+func (g *graphImage) Placeholder(graph starsui.GraphModel, height int) string {
+	g.shown = true
+	g.seq = graph.RenderWith(starsui.ImageRenderer{Protocol: g.protocol})
+	return strings.Repeat("\n", height-1)
+}
+
+// Sync schedules drawing the image of frame, or clears the screen if the
+// image isn't shown anymore.
+func (g *graphImage) Sync(frame string, row int) tea.Cmd {
+	if !g.Enabled() {
+		return nil
+	}
+	shown := g.shown
+	g.shown = false
+	if !shown {
+		if !g.drawn {
+			return nil
+		}
+		g.drawn, g.frame = false, ""
+		if g.protocol == starsui.GraphicsKitty {
+			write(starsui.KittyClear)
+		}
+		return tea.ClearScreen
+	}
+	if frame == g.frame {
+		return nil
+	}
+	g.frame, g.row = frame, row
+	return tea.Tick(imageDelay, func(time.Time) tea.Msg {
+		return graphImageMsg{frame: frame}
+	})
+}
+
+// Draw writes the image to the terminal if the frame didn't change since it
+// was scheduled.
+func (g *graphImage) Draw(msg graphImageMsg) {
+	if msg.frame != g.frame || g.seq == "" {
+		return
+	}
+	g.drawn = true
+	seq := g.seq
+	if g.protocol == starsui.GraphicsKitty {
+		seq = starsui.KittyClear + seq
+	}
+	// Save and restore the cursor so Bubble Tea keeps rendering where it
+	// expects to, and write at once so the image isn't interleaved with a
+	// frame.
+	write(fmt.Sprintf("\x1b7\x1b[%d;1H%s\x1b8", g.row, seq))
+}
+
+func write(s string) {
+	_, _ = os.Stdout.WriteString(s)
+}
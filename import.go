@@ -0,0 +1,229 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/spf13/pflag"
+)
+
+var errOffline = errors.New("not available offline")
+
+// importDateLayouts are the date formats accepted in imported files.
+var importDateLayouts = []string{
+	"2006-01-02",
+	time.RFC3339,
+	"2006/01/02",
+	"Mon Jan 02 2006",
+	"Jan 2, 2006",
+}
+
+// ImportFile reads a previously exported dataset. JSON files are either a
+// cache entry, an object of daily counts keyed by date, or an array of
+// records: stargazers with a starred_at date as listed by the API, or
+// snapshots of the total stars with a date, as saved by star counters. CSV
+// files either have date and stars columns with daily counts, or are
+// star-history.com exports with repository, date, and cumulative stars
+// columns.
+func ImportFile(path string) (*stars.History, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entry *stars.History
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		entry, err = importJSON(data)
+	case ".csv":
+		entry, err = importCSV(data)
+	default:
+		return nil, fmt.Errorf("unsupported import file %q, expected a .csv or .json file", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("importing %s: %w", path, err)
+	}
+	if entry.Name == "" {
+		entry.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if entry.Stars == 0 {
+		for _, count := range entry.Stargazers {
+			entry.Stars += count
+		}
+	}
+	return entry, nil
+}
+
+func importJSON(data []byte) (*stars.History, error) {
+	var entry stars.History
+	if err := json.Unmarshal(data, &entry); err == nil && entry.Stargazers != nil {
+		return &entry, nil
+	}
+	var records []importRecord
+	if err := json.Unmarshal(data, &records); err == nil {
+		return importRecords(records)
+	}
+	var daily map[string]int
+	if err := json.Unmarshal(data, &daily); err != nil {
+		return nil, err
+	}
+	entry = stars.History{Stargazers: make(map[string]int, len(daily))}
+	for date, count := range daily {
+		day, err := parseImportDate(date)
+		if err != nil {
+			return nil, err
+		}
+		entry.Stargazers[day] += count
+	}
+	return &entry, nil
+}
+
+// importRecord is a stargazer or a snapshot of the total stars of a
+// repository.
+type importRecord struct {
+	StarredAt string     `json:"starred_at"`
+	User      stars.User `json:"user"`
+	Date      string     `json:"date"`
+	Stars     *int       `json:"stars"`
+	Repo      string     `json:"repo"`
+}
+
+func importRecords(records []importRecord) (*stars.History, error) {
+	entry := &stars.History{Stargazers: make(map[string]int)}
+	// Records may come in any order.
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Date+records[i].StarredAt < records[j].Date+records[j].StarredAt
+	})
+	var last int
+	for n, r := range records {
+		switch {
+		case r.StarredAt != "":
+			day, err := parseImportDate(r.StarredAt)
+			if err != nil {
+				return nil, fmt.Errorf("record %d: %w", n+1, err)
+			}
+			entry.Stargazers[day]++
+			if r.User.Login != "" {
+				entry.Logins = append(entry.Logins, r.User.Login)
+			}
+		case r.Date != "" && r.Stars != nil:
+			day, err := parseImportDate(r.Date)
+			if err != nil {
+				return nil, fmt.Errorf("record %d: %w", n+1, err)
+			}
+			if entry.Name == "" {
+				entry.Name = r.Repo
+			}
+			entry.Stargazers[day] += *r.Stars - last
+			last = *r.Stars
+			entry.Stars = last
+		default:
+			return nil, fmt.Errorf("record %d: expected starred_at, or date and stars", n+1)
+		}
+	}
+	return entry, nil
+}
+
+func importCSV(data []byte) (*stars.History, error) {
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, errors.New("empty file")
+	}
+	repoCol, dateCol, starsCol := -1, -1, -1
+	for i, h := range records[0] {
+		switch strings.ToLower(strings.TrimSpace(h)) {
+		case "repository", "repo":
+			repoCol = i
+		case "date", "day":
+			dateCol = i
+		case "stars", "stargazers", "count":
+			starsCol = i
+		}
+	}
+	if dateCol < 0 || starsCol < 0 {
+		return nil, errors.New("missing date or stars column")
+	}
+	// star-history.com exports have a repository column and cumulative
+	// counts.
+	cumulative := repoCol >= 0
+	entry := &stars.History{Stargazers: make(map[string]int)}
+	var last int
+	for n, record := range records[1:] {
+		if repoCol >= 0 {
+			// Only import the first repository of multi-repository
+			// exports.
+			repo := strings.TrimSpace(record[repoCol])
+			if entry.Name == "" {
+				entry.Name = repo
+			}
+			if repo != entry.Name {
+				continue
+			}
+		}
+		day, err := parseImportDate(record[dateCol])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+2, err)
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(record[starsCol]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+2, err)
+		}
+		if cumulative {
+			count, last = count-last, count
+			entry.Stars = last
+		}
+		entry.Stargazers[day] += count
+	}
+	return entry, nil
+}
+
+func parseImportDate(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range importDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format("2006-01-02"), nil
+		}
+	}
+	return "", fmt.Errorf("invalid date %q", s)
+}
+
+func runImport(args []string) {
+	flags := pflag.NewFlagSet("import", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gh stars import <file>... [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Merge previously collected stargazers histories into the cache.\n\n")
+		flags.PrintDefaults()
+	}
+	repo := flags.String("repo", "", "repository of the imported files, by default the one they name or their file name")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(1)
+	}
+	for _, path := range flags.Args() {
+		imported, err := ImportFile(path)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if *repo != "" {
+			imported.Name = *repo
+		}
+		entry, err := MergeCache(imported)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Printf("Merged %s into %s, %d days of history\n", path, entry.Name, len(entry.Stargazers))
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+)
+
+func TestImportFile(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data string
+		want *stars.History
+		// err is whether importing fails.
+		err bool
+	}{
+		{
+			name: "cache entry",
+			file: "repo.json",
+			data: `{"name": "owner/repo", "stars": 3, "stargazers": {"2024-01-01": 1, "2024-01-02": 2}}`,
+			want: &stars.History{Name: "owner/repo", Stars: 3, Stargazers: stars.Timeline{"2024-01-01": 1, "2024-01-02": 2}},
+		},
+		{
+			name: "daily counts named after the file",
+			file: "repo.json",
+			data: `{"2024-01-01": 1, "2024/01/02": 2, "2024-01-02T10:00:00Z": 1}`,
+			want: &stars.History{Name: "repo", Stars: 4, Stargazers: stars.Timeline{"2024-01-01": 1, "2024-01-02": 3}},
+		},
+		{
+			name: "stargazers of the API",
+			file: "repo.json",
+			data: `[{"starred_at": "2024-01-02T10:00:00Z", "user": {"login": "b"}}, {"starred_at": "2024-01-01T10:00:00Z", "user": {"login": "a"}}]`,
+			want: &stars.History{Name: "repo", Stars: 2, Stargazers: stars.Timeline{"2024-01-01": 1, "2024-01-02": 1}, Logins: []string{"a", "b"}},
+		},
+		{
+			name: "snapshots of the total stars",
+			file: "repo.json",
+			data: `[{"date": "2024-01-03", "stars": 5, "repo": "owner/repo"}, {"date": "2024-01-01", "stars": 2, "repo": "owner/repo"}]`,
+			want: &stars.History{Name: "owner/repo", Stars: 5, Stargazers: stars.Timeline{"2024-01-01": 2, "2024-01-03": 3}},
+		},
+		{
+			name: "records without dates",
+			file: "repo.json",
+			data: `[{"user": {"login": "a"}}]`,
+			err:  true,
+		},
+		{
+			name: "daily counts",
+			file: "repo.csv",
+			data: "date,stars\n2024-01-01,1\n2024-01-02,2\n",
+			want: &stars.History{Name: "repo", Stars: 3, Stargazers: stars.Timeline{"2024-01-01": 1, "2024-01-02": 2}},
+		},
+		{
+			name: "star-history.com export",
+			file: "export.csv",
+			data: "Repository,Date,Stars\n" +
+				"owner/repo,Mon Jan 01 2024,1\n" +
+				"owner/repo,Wed Jan 03 2024,3\n" +
+				"owner/other,Mon Jan 01 2024,7\n",
+			want: &stars.History{Name: "owner/repo", Stars: 3, Stargazers: stars.Timeline{"2024-01-01": 1, "2024-01-03": 2}},
+		},
+		{
+			name: "missing stars column",
+			file: "repo.csv",
+			data: "date,count of stars\n2024-01-01,1\n",
+			err:  true,
+		},
+		{
+			name: "invalid date",
+			file: "repo.csv",
+			data: "date,stars\nyesterday,1\n",
+			err:  true,
+		},
+		{
+			name: "unsupported extension",
+			file: "repo.txt",
+			data: "2024-01-01 1\n",
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ImportFile(path)
+			if (err != nil) != tt.err {
+				t.Fatalf("ImportFile() error = %v, want error %v", err, tt.err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ImportFile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
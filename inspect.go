@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// inspector draws a crosshair over the graph and shows the exact values
+// under it.
+type inspector struct {
+	active bool
+	index  int
+}
+
+// Active returns whether the inspect mode is on.
+func (i *inspector) Active() bool {
+	return i.active
+}
+
+// Toggle turns the inspect mode on or off. The cursor starts at the latest
+// data point.
+func (i *inspector) Toggle(total int) {
+	i.active = !i.active
+	if i.active {
+		i.index = total - 1
+	}
+}
+
+// Stop turns the inspect mode off.
+func (i *inspector) Stop() {
+	i.active = false
+}
+
+// Clamp keeps the cursor within the total data points after the stargazers
+// are replaced, turning the inspect mode off if there are none.
+func (i *inspector) Clamp(total int) {
+	if total == 0 {
+		i.Stop()
+		return
+	}
+	if i.index > total-1 {
+		i.index = total - 1
+	}
+}
+
+// Move moves the cursor by delta data points.
+func (i *inspector) Move(delta int, total int) {
+	if !i.active {
+		return
+	}
+	i.index += delta
+	if i.index < 0 {
+		i.index = 0
+	}
+	if i.index > total-1 {
+		i.index = total - 1
+	}
+}
+
+// View draws the crosshair at the column col of graph and appends a status
+// line.
+func (i *inspector) View(r *Repo, keys []string, graph string, col int) string {
+	lines := strings.Split(graph, "\n")
+	// The last line is the caption.
+	for n := 0; n < len(lines)-1; n++ {
+		lines[n] = overlayColumn(lines[n], col, r.theme.AccentStyle().Render("│"))
+	}
+	var total int
+	for _, k := range keys[:i.index+1] {
+		total += r.stargazers[k]
+	}
+	k := keys[i.index]
+	status := fmt.Sprintf(" %s  daily: %d  total: %d", k, r.stargazers[k], total)
+	return strings.Join(lines, "\n") + "\n" + r.theme.AccentStyle().Render(status)
+}
+
+// overlayColumn replaces the blank cell at the visible column col of line
+// with mark. ANSI escape sequences are skipped when counting columns and
+// non-blank cells are left untouched.
+func overlayColumn(line string, col int, mark string) string {
+	var b strings.Builder
+	runes := []rune(line)
+	visible := 0
+	for n := 0; n < len(runes); n++ {
+		if runes[n] == '\x1b' {
+			end := n
+			for end < len(runes) && runes[end] != 'm' {
+				end++
+			}
+			if end == len(runes) {
+				end--
+			}
+			b.WriteString(string(runes[n : end+1]))
+			n = end
+			continue
+		}
+		if visible == col && runes[n] == ' ' {
+			b.WriteString(mark)
+		} else {
+			b.WriteRune(runes[n])
+		}
+		visible++
+	}
+	if visible <= col {
+		b.WriteString(strings.Repeat(" ", col-visible))
+		b.WriteString(mark)
+	}
+	return b.String()
+}
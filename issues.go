@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/go-gh/pkg/api"
+)
+
+const (
+	searchIssuesPath = "search/issues?q=%s&per_page=1"
+	// issuesWeeks is the number of recent weeks compared. Each week is a
+	// request against the search rate limit of 30 requests per minute.
+	issuesWeeks = 12
+)
+
+// IssuesMsg holds the issues opened in each of the recent weeks.
+type IssuesMsg struct {
+	// Weeks are the first days of the weeks, oldest first.
+	Weeks  []string
+	Issues []int
+	Err    error
+}
+
+// issues compares the stars gained each week with the issues opened, to see
+// whether growth turns into support burden.
+type issues struct {
+	loading bool
+	loaded  bool
+	msg     IssuesMsg
+}
+
+// recentWeeks returns the first days of the last n weeks up to today, oldest
+// first.
+func recentWeeks(today time.Time, n int) []string {
+	weeks := make([]string, n)
+	for i := range weeks {
+		weeks[i] = today.AddDate(0, 0, 7*(i-n)+1).Format("2006-01-02")
+	}
+	return weeks
+}
+
+// Load counts the issues opened in the recent weeks unless they were already
+// counted.
+func (is *issues) Load(r *Repo) tea.Cmd {
+	if is.loading || is.loaded {
+		return nil
+	}
+	if r.offline {
+		is.msg.Err = errOffline
+		return nil
+	}
+	is.loading = true
+	client, name := r.client, r.name
+	weeks := recentWeeks(time.Now().In(timeZone), issuesWeeks)
+	return func() tea.Msg {
+		counts, err := CountWeeklyIssues(client, name, weeks)
+		return IssuesMsg{Weeks: weeks, Issues: counts, Err: err}
+	}
+}
+
+// CountWeeklyIssues returns the number of issues opened during the week
+// starting on each of weeks with the search API. The requests are sequential
+// as the search API limits concurrent requests.
+func CountWeeklyIssues(client api.RESTClient, name string, weeks []string) ([]int, error) {
+	counts := make([]int, len(weeks))
+	for i, week := range weeks {
+		start, err := time.Parse("2006-01-02", week)
+		if err != nil {
+			return nil, err
+		}
+		query := fmt.Sprintf("repo:%s type:issue created:%s..%s", name, week, start.AddDate(0, 0, 6).Format("2006-01-02"))
+		var result struct {
+			TotalCount int `json:"total_count"`
+		}
+		if err := client.Get(fmt.Sprintf(searchIssuesPath, url.QueryEscape(query)), &result); err != nil {
+			return nil, fmt.Errorf("Error searching the issues of the week of %s: %w", week, err)
+		}
+		counts[i] = result.TotalCount
+	}
+	return counts, nil
+}
+
+// SetIssues stores the weekly issue counts.
+func (is *issues) SetIssues(msg IssuesMsg) {
+	is.loading = false
+	is.loaded = true
+	is.msg = msg
+}
+
+// weeklyStars returns the stars gained during the week starting on each of
+// weeks.
+func weeklyStars(daily map[string]int, weeks []string) []int {
+	counts := make([]int, len(weeks))
+	for i, week := range weeks {
+		for day, n := range daily {
+			if day >= week && (i+1 == len(weeks) || day < weeks[i+1]) {
+				counts[i] += n
+			}
+		}
+	}
+	return counts
+}
+
+// correlationStrength describes a correlation coefficient.
+func correlationStrength(c float64) string {
+	switch a := math.Abs(c); {
+	case a >= 0.7:
+		return "strong"
+	case a >= 0.4:
+		return "moderate"
+	case a >= 0.2:
+		return "weak"
+	}
+	return "none"
+}
+
+// scatterPlot plots a point per pair of xs and ys, scaled to their largest
+// values.
+func scatterPlot(theme Theme, xs, ys []int, width, height int) []string {
+	maxX, maxY := 1, 1
+	for i := range xs {
+		if xs[i] > maxX {
+			maxX = xs[i]
+		}
+		if ys[i] > maxY {
+			maxY = ys[i]
+		}
+	}
+	labelWidth := len(strconv.Itoa(maxY))
+	plotWidth := width - labelWidth - 4
+	if plotWidth < 10 {
+		plotWidth = 10
+	}
+	if height < 2 {
+		height = 2
+	}
+	grid := make([][]rune, height)
+	for row := range grid {
+		grid[row] = []rune(strings.Repeat(" ", plotWidth))
+	}
+	for i := range xs {
+		col := xs[i] * (plotWidth - 1) / maxX
+		row := height - 1 - ys[i]*(height-1)/maxY
+		grid[row][col] = '•'
+	}
+	lines := make([]string, 0, height+2)
+	for row := range grid {
+		label := ""
+		switch row {
+		case 0:
+			label = strconv.Itoa(maxY)
+		case height - 1:
+			label = "0"
+		}
+		lines = append(lines, fmt.Sprintf(" %*s ┤%s", labelWidth, label, theme.AccentStyle().Render(string(grid[row]))))
+	}
+	lines = append(lines, fmt.Sprintf(" %*s ┼%s", labelWidth, "", strings.Repeat("─", plotWidth)))
+	maxLabel := strconv.Itoa(maxX)
+	lines = append(lines, fmt.Sprintf(" %*s  0%*s", labelWidth, "", plotWidth-1, maxLabel))
+	return lines
+}
+
+func (is *issues) View(r *Repo) string {
+	switch {
+	case is.loading:
+		return fmt.Sprintf("\n %s searching issues...\n", r.spinner.View())
+	case is.msg.Err != nil:
+		return fmt.Sprintf("\n Error: %s", is.msg.Err)
+	}
+	starsPerWeek := weeklyStars(r.stargazers, is.msg.Weeks)
+	xs, ys := make([]float64, len(starsPerWeek)), make([]float64, len(is.msg.Issues))
+	for i := range xs {
+		xs[i], ys[i] = float64(starsPerWeek[i]), float64(is.msg.Issues[i])
+	}
+	c := correlation(xs, ys)
+	lines := []string{"", r.theme.AccentStyle().Render(" Issues opened per week"), ""}
+	lines = append(lines, scatterPlot(r.theme, starsPerWeek, is.msg.Issues, r.width, r.height-7)...)
+	note := fmt.Sprintf(" Stars gained per week over the last %d weeks, correlation %.2f (%s)", len(is.msg.Weeks), c, correlationStrength(c))
+	lines = append(lines, r.theme.AccentStyle().Render(note))
+	return strings.Join(lines, "\n")
+}
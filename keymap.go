@@ -0,0 +1,302 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// KeyMap are the key bindings of the repository view. The keys of each
+// binding can be remapped in the keys section of the config file.
+type KeyMap struct {
+	Section    key.Binding
+	Play       key.Binding
+	Help       key.Binding
+	Quit       key.Binding
+	Faster     key.Binding
+	Slower     key.Binding
+	Stop       key.Binding
+	Inspect    key.Binding
+	Left       key.Binding
+	Right      key.Binding
+	ZoomIn     key.Binding
+	ZoomOut    key.Binding
+	PanLeft    key.Binding
+	PanRight   key.Binding
+	Spikes     key.Binding
+	PrevSpike  key.Binding
+	NextSpike  key.Binding
+	Releases   key.Binding
+	Events     key.Binding
+	Compare    key.Binding
+	Pin        key.Binding
+	Smoothing  key.Binding
+	LogScale   key.Binding
+	Buckets    key.Binding
+	Growth     key.Binding
+	Total      key.Binding
+	Columns    key.Binding
+	OpenWeb    key.Binding
+	RateLimits key.Binding
+	Explain    key.Binding
+	Day        key.Binding
+	Screenshot key.Binding
+	Watch      key.Binding
+	Command    key.Binding
+	Filter     key.Binding
+	Retry      key.Binding
+	// The range bindings plot the recent windows of rangePresets.
+	RangeAll     key.Binding
+	RangeWeek    key.Binding
+	RangeMonth   key.Binding
+	RangeQuarter key.Binding
+	RangeYear    key.Binding
+	// Table navigates the tables.
+	Table table.KeyMap
+}
+
+// DefaultKeyMap returns the default key bindings.
+func DefaultKeyMap() KeyMap {
+	binding := func(help string, keys ...string) key.Binding {
+		return key.NewBinding(key.WithKeys(keys...), key.WithHelp(keyHelp(keys[0]), help))
+	}
+	return KeyMap{
+		Section:      binding("section", "tab", "shift+tab"),
+		Play:         binding("play/pause", " "),
+		Help:         binding("help", "?"),
+		Quit:         binding("quit", "q", "ctrl+c"),
+		Faster:       binding("faster playback", "]"),
+		Slower:       binding("slower playback", "["),
+		Stop:         binding("stop playback", "esc"),
+		Inspect:      binding("inspect graph", "i"),
+		Left:         binding("move cursor left", "left"),
+		Right:        binding("move cursor right", "right"),
+		ZoomIn:       binding("zoom in", "+", "="),
+		ZoomOut:      binding("zoom out", "-"),
+		PanLeft:      binding("pan left", "h"),
+		PanRight:     binding("pan right", "l"),
+		Spikes:       binding("show spikes", "s"),
+		PrevSpike:    binding("previous spike", "up"),
+		NextSpike:    binding("next spike", "down"),
+		Releases:     binding("show releases", "r"),
+		Events:       binding("show events", "E"),
+		Compare:      binding("compare windows", "c"),
+		Pin:          binding("pin last 30 days", "p"),
+		Smoothing:    binding("smoothing", "a"),
+		LogScale:     binding("log scale", "y"),
+		Buckets:      binding("recent detail", "b"),
+		Growth:       binding("percentage growth", "%"),
+		Total:        binding("overlay total stars", "t"),
+		Columns:      binding("table columns", "C"),
+		OpenWeb:      binding("open star-history.com", "o"),
+		RateLimits:   binding("rate limits", "!"),
+		Explain:      binding("explain row", "e"),
+		Day:          binding("stargazers of the day", "enter"),
+		Screenshot:   binding("screenshot", "ctrl+s"),
+		Watch:        binding("watch for the first star", "w"),
+		Command:      binding("command, :w NAME saves the session", ":"),
+		Filter:       binding("filter days, e.g. >5", "/"),
+		Retry:        binding("retry after an error or failed pages", "R"),
+		RangeAll:     binding("all time", "0"),
+		RangeWeek:    binding("last week", "1"),
+		RangeMonth:   binding("last month", "2"),
+		RangeQuarter: binding("last quarter", "3"),
+		RangeYear:    binding("last year", "4"),
+		Table:        table.DefaultKeyMap(),
+	}
+}
+
+// rangePreset returns the range preset of a range binding matching msg.
+func (k KeyMap) rangePreset(msg tea.KeyMsg) string {
+	for name, b := range map[string]key.Binding{
+		"all":     k.RangeAll,
+		"week":    k.RangeWeek,
+		"month":   k.RangeMonth,
+		"quarter": k.RangeQuarter,
+		"year":    k.RangeYear,
+	} {
+		if key.Matches(msg, b) {
+			return name
+		}
+	}
+	return ""
+}
+
+// matchesView returns whether msg matches a binding of the view rather than
+// of the tables.
+func (k *KeyMap) matchesView(msg tea.KeyMsg) bool {
+	for name, b := range k.bindings() {
+		if !tableBindings[name] && key.Matches(msg, *b) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyHelp returns how a key is shown in the help.
+func keyHelp(k string) string {
+	switch k {
+	case " ":
+		return "space"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	}
+	return k
+}
+
+// bindings returns the bindings keyed by their name in the config file.
+func (k *KeyMap) bindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"section":        &k.Section,
+		"play":           &k.Play,
+		"help":           &k.Help,
+		"quit":           &k.Quit,
+		"faster":         &k.Faster,
+		"slower":         &k.Slower,
+		"stop":           &k.Stop,
+		"inspect":        &k.Inspect,
+		"left":           &k.Left,
+		"right":          &k.Right,
+		"zoom_in":        &k.ZoomIn,
+		"zoom_out":       &k.ZoomOut,
+		"pan_left":       &k.PanLeft,
+		"pan_right":      &k.PanRight,
+		"spikes":         &k.Spikes,
+		"previous_spike": &k.PrevSpike,
+		"next_spike":     &k.NextSpike,
+		"releases":       &k.Releases,
+		"events":         &k.Events,
+		"compare":        &k.Compare,
+		"pin":            &k.Pin,
+		"smoothing":      &k.Smoothing,
+		"log_scale":      &k.LogScale,
+		"buckets":        &k.Buckets,
+		"growth":         &k.Growth,
+		"total":          &k.Total,
+		"columns":        &k.Columns,
+		"open_web":       &k.OpenWeb,
+		"rate_limits":    &k.RateLimits,
+		"explain":        &k.Explain,
+		"day":            &k.Day,
+		"screenshot":     &k.Screenshot,
+		"watch":          &k.Watch,
+		"command":        &k.Command,
+		"filter":         &k.Filter,
+		"retry":          &k.Retry,
+		"range_all":      &k.RangeAll,
+		"range_week":     &k.RangeWeek,
+		"range_month":    &k.RangeMonth,
+		"range_quarter":  &k.RangeQuarter,
+		"range_year":     &k.RangeYear,
+		"line_up":        &k.Table.LineUp,
+		"line_down":      &k.Table.LineDown,
+		"page_up":        &k.Table.PageUp,
+		"page_down":      &k.Table.PageDown,
+		"half_page_up":   &k.Table.HalfPageUp,
+		"half_page_down": &k.Table.HalfPageDown,
+		"goto_top":       &k.Table.GotoTop,
+		"goto_bottom":    &k.Table.GotoBottom,
+	}
+}
+
+// KeyNames returns the names of the bindings in the config file.
+func KeyNames() []string {
+	var k KeyMap
+	names := make([]string, 0)
+	for name := range k.bindings() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// tableBindings are the bindings of the tables, handled by the table
+// rather than the repository view, so they may share keys with the others.
+var tableBindings = map[string]bool{
+	"line_up":        true,
+	"line_down":      true,
+	"page_up":        true,
+	"page_down":      true,
+	"half_page_up":   true,
+	"half_page_down": true,
+	"goto_top":       true,
+	"goto_bottom":    true,
+}
+
+// Remap replaces the keys of the bindings named in keys, updating their
+// help. It fails if a remapped key is also bound to another action handled
+// by the same view.
+func (k *KeyMap) Remap(keys map[string][]string) error {
+	bindings := k.bindings()
+	for name, remapped := range keys {
+		b, ok := bindings[name]
+		if !ok {
+			return fmt.Errorf("unknown key binding %q, expected one of %s", name, strings.Join(KeyNames(), ", "))
+		}
+		if len(remapped) == 0 {
+			b.SetEnabled(false)
+			continue
+		}
+		b.SetKeys(remapped...)
+		b.SetHelp(keyHelp(remapped[0]), b.Help().Desc)
+	}
+	for _, name := range KeyNames() {
+		if len(keys[name]) == 0 {
+			continue
+		}
+		for _, other := range KeyNames() {
+			b := bindings[other]
+			if other == name || tableBindings[other] != tableBindings[name] || !b.Enabled() {
+				continue
+			}
+			for _, remapped := range keys[name] {
+				if contains(b.Keys(), remapped) {
+					return fmt.Errorf("key %q is bound to both %s and %s, remap or disable one of them", remapped, name, other)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ShortHelp implements help.KeyMap.
+func (k KeyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Section, k.Play, k.Help, k.Quit}
+}
+
+// FullHelp implements help.KeyMap.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		k.ShortHelp(),
+		{k.Faster, k.Slower, k.Stop},
+		{k.Inspect, k.Left, k.Right},
+		{k.ZoomIn, k.ZoomOut, k.PanLeft, k.PanRight},
+		{k.RangeAll, k.RangeWeek, k.RangeMonth, k.RangeQuarter, k.RangeYear},
+		{
+			k.Spikes, k.PrevSpike, k.NextSpike, k.Releases, k.Events, k.Compare, k.Pin,
+			k.Smoothing, k.LogScale, k.Buckets, k.Growth, k.Total, k.Columns,
+			k.OpenWeb, k.RateLimits, k.Explain, k.Day, k.Screenshot, k.Watch,
+			k.Command, k.Filter, k.Retry,
+		},
+		{
+			k.Table.LineUp,
+			k.Table.LineDown,
+			k.Table.PageUp,
+			k.Table.PageDown,
+			k.Table.HalfPageUp,
+			k.Table.HalfPageDown,
+			k.Table.GotoTop,
+			k.Table.GotoBottom,
+		},
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeyMapRemap(t *testing.T) {
+	tests := []struct {
+		name string
+		keys map[string][]string
+		// err is a substring of the error, empty if there's none.
+		err string
+	}{
+		{
+			name: "config example",
+			keys: map[string][]string{
+				"left":      {"h"},
+				"right":     {"l"},
+				"pan_left":  {"H"},
+				"pan_right": {"L"},
+				"line_up":   {"k"},
+				"line_down": {"j"},
+			},
+		},
+		{
+			name: "unknown binding",
+			keys: map[string][]string{"jump": {"j"}},
+			err:  `unknown key binding "jump"`,
+		},
+		{
+			name: "key of another binding",
+			keys: map[string][]string{"left": {"h"}},
+			err:  `key "h" is bound to both left and pan_left`,
+		},
+		{
+			name: "two remapped bindings sharing a key",
+			keys: map[string][]string{"left": {"x"}, "right": {"x"}},
+			err:  `key "x" is bound to both left and right`,
+		},
+		{
+			name: "key of a disabled binding",
+			keys: map[string][]string{"left": {"h"}, "pan_left": {}},
+		},
+		{
+			name: "key of a binding moved to another key",
+			keys: map[string][]string{"left": {"h"}, "pan_left": {"H"}},
+		},
+		{
+			name: "table and view bindings sharing a key",
+			keys: map[string][]string{"page_up": {"s"}},
+		},
+		{
+			name: "table bindings sharing a key",
+			keys: map[string][]string{"page_up": {"k"}},
+			err:  `key "k" is bound to both page_up and line_up`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k := DefaultKeyMap()
+			err := k.Remap(tt.keys)
+			switch {
+			case tt.err == "" && err != nil:
+				t.Errorf("Remap() error = %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Errorf("Remap() error = %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestKeyMapRemapHelp(t *testing.T) {
+	k := DefaultKeyMap()
+	if err := k.Remap(map[string][]string{"play": {"P", " "}, "watch": {}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := k.Play.Help().Key; got != "P" {
+		t.Errorf("help of play = %q, want P", got)
+	}
+	if k.Watch.Enabled() {
+		t.Error("watch is enabled, want it disabled")
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/go-gh/pkg/api"
+)
+
+// kioskViews are the views rotated through for each repository.
+var kioskViews = []view{viewGraph, viewTable}
+
+type kioskTickMsg struct{}
+
+type kioskRefreshMsg struct{}
+
+// kioskEntryMsg is the history of a kiosk repository.
+type kioskEntryMsg struct {
+	index int
+	entry *stars.History
+	err   error
+}
+
+// Kiosk rotates through the views of several repositories on a timer and
+// refreshes them, for unattended displays. It only quits on ctrl+c.
+type Kiosk struct {
+	names    []string
+	entries  []*stars.History
+	errs     []error
+	opts     Options
+	client   api.RESTClient
+	interval time.Duration
+	refresh  time.Duration
+	current  int
+	view     int
+	repo     *Repo
+	width    int
+	height   int
+}
+
+func NewKiosk(repos []string, interval, refresh time.Duration, opts Options) (*Kiosk, error) {
+	if len(repos) == 0 {
+		return nil, fmt.Errorf("no repositories to show, pass them as arguments or add them to the dashboard list of the config file")
+	}
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return &Kiosk{
+		names:    repos,
+		entries:  make([]*stars.History, len(repos)),
+		errs:     make([]error, len(repos)),
+		opts:     opts,
+		client:   client,
+		interval: interval,
+		refresh:  refresh,
+	}, nil
+}
+
+func (k *Kiosk) Init() tea.Cmd {
+	return tea.Batch(k.load(), k.tick(), k.scheduleRefresh())
+}
+
+// load fetches the repositories, using the cache when it's more recent than
+// the refresh interval.
+func (k *Kiosk) load() tea.Cmd {
+	cmds := make([]tea.Cmd, len(k.names))
+	for i, name := range k.names {
+		i, name := i, name
+		cmds[i] = func() tea.Msg {
+			entry, err := LoadHistory(k.client, name, k.refresh, k.opts.Offline)
+			return kioskEntryMsg{index: i, entry: entry, err: err}
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+func (k *Kiosk) tick() tea.Cmd {
+	return tea.Tick(k.interval, func(time.Time) tea.Msg {
+		return kioskTickMsg{}
+	})
+}
+
+func (k *Kiosk) scheduleRefresh() tea.Cmd {
+	return tea.Tick(k.refresh, func(time.Time) tea.Msg {
+		return kioskRefreshMsg{}
+	})
+}
+
+// show displays the current view of the current repository.
+func (k *Kiosk) show() tea.Cmd {
+	entry := k.entries[k.current]
+	if entry == nil {
+		k.repo = nil
+		return nil
+	}
+	opts := k.opts
+	opts.Data = entry
+	opts.Keys = nil
+	r, err := NewRepo(entry.Name, opts)
+	if err != nil {
+		k.errs[k.current] = err
+		k.repo = nil
+		return nil
+	}
+	r.view = kioskViews[k.view]
+	r.Update(tea.WindowSizeMsg{Width: k.width, Height: k.height})
+	k.repo = r
+	return r.Init()
+}
+
+// advance moves to the next view, then to the next loaded repository.
+func (k *Kiosk) advance() tea.Cmd {
+	k.view++
+	if k.view < len(kioskViews) && k.repo != nil {
+		k.repo.view = kioskViews[k.view]
+		return nil
+	}
+	k.view = 0
+	for i := 1; i <= len(k.names); i++ {
+		next := (k.current + i) % len(k.names)
+		if k.entries[next] != nil {
+			k.current = next
+			break
+		}
+	}
+	return k.show()
+}
+
+func (k *Kiosk) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return k, tea.Quit
+		}
+		// The kiosk is read-only.
+		return k, nil
+	case tea.WindowSizeMsg:
+		k.width = msg.Width
+		k.height = msg.Height
+	case kioskEntryMsg:
+		if msg.err != nil {
+			// Keep showing the previous data when a refresh fails.
+			k.errs[msg.index] = msg.err
+			return k, nil
+		}
+		k.entries[msg.index] = msg.entry
+		k.errs[msg.index] = nil
+		if msg.index == k.current {
+			return k, k.show()
+		}
+		if k.repo == nil && k.entries[k.current] == nil {
+			k.current = msg.index
+			return k, k.show()
+		}
+		return k, nil
+	case kioskTickMsg:
+		return k, tea.Batch(k.advance(), k.tick())
+	case kioskRefreshMsg:
+		return k, tea.Batch(k.load(), k.scheduleRefresh())
+	}
+	if k.repo != nil {
+		_, cmd := k.repo.Update(msg)
+		return k, cmd
+	}
+	return k, nil
+}
+
+func (k *Kiosk) View() string {
+	if k.repo != nil {
+		return k.repo.View()
+	}
+	if err := k.errs[k.current]; err != nil {
+		return fmt.Sprintf("\n Error: %s", err)
+	}
+	return fmt.Sprintf("\n Loading %s...\n", k.names[k.current])
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+)
+
+// Landmark is a notable date of the history of a repository.
+type Landmark struct {
+	Label string
+	Date  string
+	Note  string
+}
+
+// fastestSpan is the number of stars of the fastest span landmark.
+const fastestSpan = 1000
+
+// Landmarks returns the first star, the days the stars crossed each power of
+// ten, the fastest 1000 stars, and the current streak of days with new stars
+// of daily up to today.
+func Landmarks(daily stars.Timeline, today string) []Landmark {
+	first := earliestKey(daily)
+	if first == "" {
+		return nil
+	}
+	days := dayRange(first, today)
+	landmarks := []Landmark{{Label: "First star", Date: first}}
+	var total int
+	next := 10
+	for _, day := range days {
+		total += daily[day]
+		for total >= next {
+			landmarks = append(landmarks, Landmark{Label: fmt.Sprintf("%d stars", next), Date: day})
+			next *= 10
+		}
+	}
+	// Slide a window over the days, shrinking it from the start while it
+	// still holds the stars.
+	best, bestFrom, bestTo := 0, 0, 0
+	var sum, start int
+	for end, day := range days {
+		sum += daily[day]
+		for sum-daily[days[start]] >= fastestSpan {
+			sum -= daily[days[start]]
+			start++
+		}
+		if sum >= fastestSpan && (best == 0 || end-start+1 < best) {
+			best, bestFrom, bestTo = end-start+1, start, end
+		}
+	}
+	if best > 0 {
+		landmarks = append(landmarks, Landmark{
+			Label: fmt.Sprintf("Fastest %d stars", fastestSpan),
+			Date:  days[bestFrom],
+			Note:  fmt.Sprintf("%d days, to %s", best, days[bestTo]),
+		})
+	}
+	// Today isn't over, so the streak goes on until a full day without
+	// stars.
+	i := len(days) - 1
+	if i > 0 && daily[days[i]] == 0 {
+		i--
+	}
+	streak := 0
+	for ; i >= 0 && daily[days[i]] > 0; i-- {
+		streak++
+	}
+	if streak > 0 {
+		landmarks = append(landmarks, Landmark{
+			Label: "Current streak",
+			Date:  days[i+1],
+			Note:  fmt.Sprintf("%d days with new stars", streak),
+		})
+	}
+	return landmarks
+}
+
+// WriteLandmarks writes the landmarks of the history of a repository.
+func WriteLandmarks(w io.Writer, entry *stars.History) error {
+	landmarks := Landmarks(entry.Stargazers, time.Now().In(timeZone).Format("2006-01-02"))
+	if len(landmarks) == 0 {
+		_, err := fmt.Fprintf(w, "%s has no stargazers.\n", entry.Name)
+		return err
+	}
+	fmt.Fprintf(w, "%s, %s stars\n\n", entry.Name, formatNumber(entry.Stars))
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, l := range landmarks {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", l.Label, formatDay(l.Date), l.Note)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// Date formats of --date-format besides Go layouts.
+const (
+	dateFormatISO    = "iso"
+	dateFormatLocale = "locale"
+)
+
+// numberPrinter formats counts with the digit grouping of the locale, or
+// nil to print plain digits.
+var numberPrinter *message.Printer
+
+// dateLayout is the layout days are shown with.
+var dateLayout = "2006-01-02"
+
+// regionDateLayouts are the short date layouts of the regions not using ISO
+// dates.
+var regionDateLayouts = map[string]string{
+	"US": "01/02/2006",
+	"PH": "01/02/2006",
+	"GB": "02/01/2006",
+	"IE": "02/01/2006",
+	"AU": "02/01/2006",
+	"NZ": "02/01/2006",
+	"IN": "02/01/2006",
+	"FR": "02/01/2006",
+	"BE": "02/01/2006",
+	"ES": "02/01/2006",
+	"IT": "02/01/2006",
+	"PT": "02/01/2006",
+	"BR": "02/01/2006",
+	"MX": "02/01/2006",
+	"AR": "02/01/2006",
+	"GR": "02/01/2006",
+	"DE": "02.01.2006",
+	"AT": "02.01.2006",
+	"CH": "02.01.2006",
+	"CZ": "02.01.2006",
+	"PL": "02.01.2006",
+	"RU": "02.01.2006",
+	"UA": "02.01.2006",
+	"TR": "02.01.2006",
+	"FI": "02.01.2006",
+	"NO": "02.01.2006",
+	"DK": "02.01.2006",
+}
+
+// envLocale returns the locale of the environment: LC_ALL, LC_NUMERIC, then
+// LANG, like the C library.
+func envLocale() string {
+	for _, name := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// ParseLocale parses a locale like de-DE or de_DE.UTF-8, auto for the locale
+// of the environment. It returns false for the C and POSIX locales, which
+// don't group digits.
+func ParseLocale(name string) (language.Tag, bool, error) {
+	if strings.ToLower(name) == "auto" {
+		name = envLocale()
+	}
+	// Drop the encoding and modifier of POSIX locales.
+	base := name
+	if i := strings.IndexAny(base, ".@"); i >= 0 {
+		base = base[:i]
+	}
+	switch base {
+	case "", "C", "POSIX":
+		return language.Und, false, nil
+	}
+	tag, err := language.Parse(base)
+	if err != nil {
+		return language.Und, false, fmt.Errorf("unknown locale %q, expected auto, C, or a name like de-DE", name)
+	}
+	return tag, true, nil
+}
+
+// ParseDateFormat returns the layout of a --date-format: iso, locale for the
+// short dates of the locale, or a Go layout like 02.01.2006.
+func ParseDateFormat(format string, locale language.Tag) (string, error) {
+	switch strings.ToLower(format) {
+	case "", dateFormatISO:
+		return "2006-01-02", nil
+	case dateFormatLocale:
+		region, _ := locale.Region()
+		if layout, ok := regionDateLayouts[region.String()]; ok {
+			return layout, nil
+		}
+		return "2006-01-02", nil
+	}
+	// A layout must at least show the day, month and year.
+	for _, part := range []string{"2006", "02"} {
+		if !strings.Contains(format, part) {
+			return "", fmt.Errorf("invalid date format %q, expected iso, locale, or a Go layout like 02.01.2006", format)
+		}
+	}
+	if !strings.Contains(format, "01") && !strings.Contains(format, "Jan") {
+		return "", fmt.Errorf("invalid date format %q, expected iso, locale, or a Go layout like 02.01.2006", format)
+	}
+	return format, nil
+}
+
+// SetLocale formats the counts and dates shown with the locale and date
+// format flags.
+func SetLocale(name, dateFormat string) error {
+	locale, grouped, err := ParseLocale(name)
+	if err != nil {
+		return err
+	}
+	numberPrinter = nil
+	if grouped {
+		numberPrinter = message.NewPrinter(locale)
+	}
+	dateLayout, err = ParseDateFormat(dateFormat, locale)
+	return err
+}
+
+// formatNumber formats a count with the digit grouping of the locale.
+func formatNumber(n int) string {
+	if numberPrinter == nil {
+		return strconv.Itoa(n)
+	}
+	return numberPrinter.Sprintf("%d", n)
+}
+
+// formatDay formats a day of the 2006-01-02 layout with the date format.
+func formatDay(day string) string {
+	if dateLayout == "2006-01-02" {
+		return day
+	}
+	t, err := time.Parse("2006-01-02", day)
+	if err != nil {
+		return day
+	}
+	return t.Format(dateLayout)
+}
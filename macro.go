@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// namedKeys are the keys that can be replayed by name besides single
+// characters.
+var namedKeys = map[string]tea.KeyType{
+	"tab":       tea.KeyTab,
+	"shift+tab": tea.KeyShiftTab,
+	"enter":     tea.KeyEnter,
+	"esc":       tea.KeyEsc,
+	"backspace": tea.KeyBackspace,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"space":     tea.KeySpace,
+}
+
+// ParseKeys parses a sequence of key names, such as tab or c, to replay.
+func ParseKeys(names []string) ([]tea.KeyMsg, error) {
+	keys := make([]tea.KeyMsg, len(names))
+	for i, name := range names {
+		if t, ok := namedKeys[name]; ok {
+			keys[i] = tea.KeyMsg{Type: t}
+			continue
+		}
+		runes := []rune(name)
+		if len(runes) != 1 {
+			return nil, fmt.Errorf("unknown key %q", name)
+		}
+		keys[i] = tea.KeyMsg{Type: tea.KeyRunes, Runes: runes}
+	}
+	return keys, nil
+}
+
+// replayKeys sends the startup keys once the stargazers are loaded.
+func (r *Repo) replayKeys() tea.Cmd {
+	if len(r.macro) == 0 {
+		return nil
+	}
+	cmds := make([]tea.Cmd, len(r.macro))
+	for i, k := range r.macro {
+		k := k
+		cmds[i] = func() tea.Msg { return k }
+	}
+	r.macro = nil
+	return tea.Sequence(cmds...)
+}
+
+// startupCmd loads the data of the view restored from a session and replays
+// the startup keys once the stargazers are loaded.
+func (r *Repo) startupCmd() tea.Cmd {
+	return tea.Batch(r.loadView(), r.events.Load(r), r.replayKeys())
+}
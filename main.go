@@ -1,13 +1,16 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"sort"
-	"sync"
+	"strings"
 	"time"
 
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/aymanbagabas/gh-stars/pkg/starsui"
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -16,19 +19,21 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/cli/go-gh"
 	"github.com/cli/go-gh/pkg/api"
-	"github.com/guptarohit/asciigraph"
+	"github.com/cli/go-gh/pkg/term"
 	"github.com/spf13/pflag"
-	"golang.org/x/sync/errgroup"
-)
-
-var (
-	debug = pflag.BoolP("debug", "d", false, "enable debug output")
 )
 
 const (
-	perPage        = 100
-	reposPath      = "repos/%s"
-	stargazersPath = "repos/%s/stargazers"
+	layoutAuto   = "auto"
+	layoutSplit  = "split"
+	layoutSingle = "single"
+
+	// autoSplitWidth is the terminal width from which the auto layout shows
+	// the graph and the table side by side.
+	autoSplitWidth = 120
+	// minSplitWidth is the terminal width under which the split layout
+	// falls back to a single view.
+	minSplitWidth = 80
 )
 
 type view int
@@ -36,6 +41,20 @@ type view int
 const (
 	viewGraph view = iota
 	viewTable
+	viewOrgs
+	viewContributors
+	viewStats
+	viewCycles
+	viewQuality
+	viewNotable
+	viewAges
+	viewPatterns
+	viewAlso
+	viewRetention
+	viewIssues
+	viewAdoption
+	viewCampaigns
+	viewCount
 )
 
 type state int
@@ -48,15 +67,25 @@ const (
 
 type ErrorMsg error
 
-type Stargazer struct {
-	StarredAt time.Time `json:"starred_at"`
+type StargazersMsg struct {
+	// Daily is the number of stargazers per day.
+	Daily map[string]int
+	// Logins are the stargazers logins from oldest to newest.
+	Logins []string
+	// StarredAt are the starred dates of Logins.
+	StarredAt []time.Time
+	// Unstars are the number of stargazers lost per day.
+	Unstars map[string]int
+	// Sources are the stars of the repository and of its aliases.
+	Sources map[string]int
+	// Failed is the number of stargazers pages that failed out of Pages,
+	// zero when all of them were fetched.
+	Failed, Pages int
+	// Anomalies are the starred dates that were in the future.
+	Anomalies stars.Anomalies
 }
 
-type StargazersMsg map[string]int
-
-type RepoMsg struct {
-	StargazersCount int `json:"stargazers_count"`
-}
+type RepoMsg stars.Repository
 
 type Repo struct {
 	state      state
@@ -67,186 +96,794 @@ type Repo struct {
 	name       string
 	client     api.RESTClient
 	stars      int
+	repo       RepoMsg
+	firstStar  firstStar
 	stargazers map[string]int
+	logins     []string
+	starredAt  []time.Time
+	unstars    map[string]int
+	termHeight int
+	resizeID   int
+	aliases    []string
+	sources    map[string]int
+	orgs       orgs
+	contribs   contributors
+	stats      stats
+	quality    quality
+	notable    notable
+	ages       ages
+	also       also
+	cycles     cycles
+	retention  retention
+	issues     issues
+	adoption   adoption
+	campaigns  campaigns
 	spinner    spinner.Model
-	table      table.Model
+	graph      starsui.GraphModel
+	table      starsui.TableModel
 	help       help.Model
 	showHelp   bool
-	mu         sync.Mutex
+	theme      Theme
+	ascii      bool
+	a11y       bool
+	alert      string
+	// script are the steps of --script, run once the stargazers are
+	// loaded.
+	script    []ScriptStep
+	layout    string
+	offline   bool
+	stale     time.Time
+	renamed   string
+	keys      KeyMap
+	sample    int
+	macro     []tea.KeyMsg
+	toast     toast
+	pngShots  bool
+	playback  playback
+	inspect   inspector
+	spikes    spikes
+	releases  releases
+	events    events
+	compare   windowComparison
+	pinned    pinned
+	limits    rateLimits
+	explain   explanation
+	day       dayStargazers
+	prompt    commandPrompt
+	themeName string
+	// tableFilter is the stars filter of the table, like >5.
+	tableFilter string
+	failed      failedPages
+	// graphHeight is the height of the graph, set by layoutGraph.
+	graphHeight int
+	// anomalies are the starred dates of the fetch that were in the
+	// future.
+	anomalies stars.Anomalies
+	// progress tracks the pages fetched of each metric while loading.
+	progress *loadProgress
+	image    graphImage
 }
 
-func NewRepo(name string) (*Repo, error) {
-	client, err := gh.RESTClient(&api.ClientOptions{
-		Headers: map[string]string{
-			"Accept": "application/vnd.github.v3.star+json",
-		},
-	})
+// Options configures how a repository is displayed.
+type Options struct {
+	Theme Theme
+	// ThemeName is the name of the theme, saved in sessions.
+	ThemeName string
+	// ASCII replaces Unicode characters with plain ASCII ones.
+	ASCII bool
+	// A11y replaces the graph with a text narration for screen readers.
+	A11y bool
+	// Layout is one of auto, split, or single.
+	Layout string
+	// Data is a previously exported dataset to show instead of fetching the
+	// repository.
+	Data *stars.History
+	// Windows are the periods compared side by side.
+	Windows []Window
+	// Offline shows the cached history instead of fetching the repository.
+	Offline bool
+	// Keys are replayed once the stargazers are loaded.
+	Keys []tea.KeyMsg
+	// ScreenshotPNG renders screenshots to PNG files besides ANSI text.
+	ScreenshotPNG bool
+	// Momentum configures the momentum ranking of compared repositories.
+	Momentum MomentumConfig
+	// Smoothing smooths the daily stargazers graph.
+	Smoothing starsui.Smoothing
+	// LogScale plots the graph on a log scale.
+	LogScale bool
+	// Aliases are the repositories merged into others, keyed by canonical
+	// repository.
+	Aliases map[string][]string
+	// Renderer draws the graphs of the stargazers history.
+	Renderer starsui.Renderer
+	// Metrics are fetched concurrently while loading, stars only by
+	// default.
+	Metrics []string
+	// Graphics is the terminal graphics protocol the graph is drawn with,
+	// ascii to draw it with characters.
+	Graphics string
+	// Columns are the column sets of the table, the default ones if empty.
+	Columns [][]string
+	// KeyMap are the key bindings of the view.
+	KeyMap KeyMap
+	// Campaigns are the campaigns of the config file stars are attributed
+	// to, keyed by repository.
+	Campaigns map[string][]Campaign
+	// Annotations are custom events marked under the graph, shown from the
+	// start.
+	Annotations []Event
+	// Range is the window of the graph, parsed by ParseRange, all time if
+	// empty.
+	Range string
+	// Sample approximates the history from every nth page of stargazers
+	// when positive, instead of fetching all of them.
+	Sample int
+	// Script are the steps run once the stargazers are loaded.
+	Script []ScriptStep
+	// Alert is the alert emitted on the terminal when the stargazers are
+	// loaded: none, bell, or osc9.
+	Alert string
+}
+
+func NewRepo(name string, opts Options) (*Repo, error) {
+	client, err := NewClient()
 	if err != nil {
 		return nil, err
 	}
 	s := spinner.New(spinner.WithSpinner(spinner.Dot))
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-	t := table.New(
-		table.WithColumns(
-			[]table.Column{
-				{Title: "Date", Width: 20},
-				{Title: "Stars", Width: 10},
-			},
-		),
-		table.WithFocused(true),
-	)
+	if opts.ASCII {
+		s.Spinner = spinner.Line
+	}
+	s.Style = opts.Theme.AccentStyle()
+	t := starsui.NewTableModel(table.WithStyles(opts.Theme.TableStyles()))
+	t.SetFormat(formatNumber, formatDay)
 	h := help.New()
 	h.ShowAll = true
-	return &Repo{
-		name:    name,
-		client:  client,
-		spinner: s,
-		table:   t,
-		help:    h,
-	}, nil
+	r := &Repo{
+		name:      name,
+		theme:     opts.Theme,
+		ascii:     opts.ASCII,
+		a11y:      opts.A11y,
+		alert:     opts.Alert,
+		script:    opts.Script,
+		layout:    opts.Layout,
+		client:    client,
+		spinner:   s,
+		table:     t,
+		help:      h,
+		orgs:      newOrgs(opts.Theme),
+		quality:   newQuality(opts.Theme),
+		notable:   newNotable(opts.Theme),
+		retention: newRetention(opts.Theme),
+		campaigns: newCampaigns(opts.Theme, campaignsOf(opts.Campaigns, name)),
+		day:       newDayStargazers(opts.Theme),
+		prompt:    newCommandPrompt(opts.Theme),
+		themeName: opts.ThemeName,
+		compare:   windowComparison{windows: opts.Windows},
+		macro:     opts.Keys,
+		pngShots:  opts.ScreenshotPNG,
+		aliases:   aliasesOf(opts.Aliases, name),
+		progress:  newLoadProgress(opts.Metrics),
+		image:     graphImage{protocol: opts.Graphics},
+		sample:    opts.Sample,
+		events:    events{active: len(opts.Annotations) > 0, custom: opts.Annotations},
+	}
+	r.graph.Smoothing = opts.Smoothing
+	r.graph.LogScale = opts.LogScale
+	r.graph.Renderer = opts.Renderer
+	if opts.Range != "" {
+		if r.graph.From, r.graph.To, err = ParseRange(opts.Range, time.Now().In(timeZone)); err != nil {
+			return nil, err
+		}
+	}
+	r.table.SetColumnSets(opts.Columns)
+	r.keys = opts.KeyMap
+	r.table.KeyMap = r.keys.Table
+	r.orgs.table.KeyMap = r.keys.Table
+	r.quality.table.KeyMap = r.keys.Table
+	r.notable.table.KeyMap = r.keys.Table
+	r.retention.table.KeyMap = r.keys.Table
+	r.campaigns.table.KeyMap = r.keys.Table
+	r.pinned.table.KeyMap = r.keys.Table
+	r.day.table.KeyMap = r.keys.Table
+	if opts.Data != nil {
+		r.name = opts.Data.Name
+		r.stars = opts.Data.Stars
+		r.setStargazers(opts.Data.Stargazers)
+		r.logins = opts.Data.Logins
+		r.starredAt = opts.Data.StarredAt
+		r.unstars = opts.Data.Unstars
+		r.state = stateReady
+		r.offline = true
+	} else if opts.Offline {
+		entry, err := loadStale(name)
+		if err != nil {
+			return nil, err
+		}
+		entry, r.sources, err = mergeAliases(client, entry, r.aliases, true)
+		if err != nil {
+			return nil, err
+		}
+		r.setStale(entry)
+	}
+	return r, nil
 }
 
 func (r *Repo) TotalStargazerPages() int {
-	return r.stars / perPage
-}
-
-func (r *Repo) GetStargazers() ([]Stargazer, error) {
-	pages := r.TotalStargazerPages()
-	if pages >= 400 {
-		return nil, fmt.Errorf("Too many pages to fetch")
-	}
-	var errg errgroup.Group
-	stargazers := make([]Stargazer, 0)
-	for page := 1; page <= pages; page++ {
-		errg.Go(func(page int) func() error {
-			return func() error {
-				path := fmt.Sprintf(stargazersPath+"?page=%d&per_page=%d", r.name, page, perPage)
-				result := make([]Stargazer, 0)
-				err := r.client.Get(path, &result)
-				if err != nil {
-					return fmt.Errorf("Error fetching stargazers page %d: %w", page, err)
-				}
-				r.mu.Lock()
-				stargazers = append(stargazers, result...)
-				r.mu.Unlock()
-				return nil
-			}
-		}(page))
+	return stars.StargazerPages(r.stars)
+}
+
+func (r *Repo) GetStargazers(warn func(stars.Anomalies)) ([]stars.Stargazer, error) {
+	return FetchStargazers(r.client, r.name, r.stars, func(done, total int) {
+		r.progress.Set(metricStars, done, total)
+	}, warn)
+}
+
+// sampleStargazers approximates the daily stargazers from every nth page of
+// them. The approximation isn't cached.
+func (r *Repo) sampleStargazers() tea.Msg {
+	f := stars.NewFetcher(r.client)
+	f.Progress = func(done, total int) {
+		r.progress.Set(metricStars, done, total)
 	}
-	if err := errg.Wait(); err != nil {
-		return stargazers, err
+	var anomalies stars.Anomalies
+	f.Warn = anomalies.Merge
+	points, err := f.Sample(r.name, r.stars, r.sample)
+	if err != nil {
+		return r.fallback(err)
 	}
-	sort.Slice(stargazers, func(i, j int) bool {
-		return stargazers[i].StarredAt.Before(stargazers[j].StarredAt)
-	})
-	return stargazers, nil
+	return StargazersMsg{Daily: stars.Interpolate(points, stars.Daily{Location: timeZone}), Anomalies: anomalies}
 }
 
 func (r *Repo) ShortHelp() []key.Binding {
-	return []key.Binding{
-		key.NewBinding(
-			key.WithKeys("tab", "shift+tab"),
-			key.WithHelp("tab", "section"),
-		),
-		key.NewBinding(
-			key.WithKeys("?"),
-			key.WithHelp("?", "help"),
-		),
-		key.NewBinding(
-			key.WithKeys("q", "ctrl+c"),
-			key.WithHelp("q", "quit"),
-		),
-	}
+	return r.keys.ShortHelp()
 }
 
 func (r *Repo) FullHelp() [][]key.Binding {
-	return [][]key.Binding{
-		r.ShortHelp(),
-		{
-			r.table.KeyMap.LineUp,
-			r.table.KeyMap.LineDown,
-			r.table.KeyMap.PageUp,
-			r.table.KeyMap.PageDown,
-			r.table.KeyMap.HalfPageUp,
-			r.table.KeyMap.HalfPageDown,
-			r.table.KeyMap.GotoTop,
-			r.table.KeyMap.GotoBottom,
-		},
-	}
+	return r.keys.FullHelp()
 }
 
 func (r *Repo) Init() tea.Cmd {
-	return tea.Batch(func() tea.Msg {
-		repoMsg := RepoMsg{}
-		err := r.client.Get(fmt.Sprintf(reposPath, r.name), &repoMsg)
+	if r.offline {
+		return tea.Batch(r.spinner.Tick, r.startupCmd())
+	}
+	r.progress.Reset()
+	cmds := []tea.Cmd{func() tea.Msg {
+		repoMsg, err := FetchRepo(r.client, r.name)
 		if err != nil {
-			return ErrorMsg(err)
+			return r.fallback(err)
 		}
 		return repoMsg
 	},
 		r.spinner.Tick,
-	)
+	}
+	if contains(r.progress.names, metricReleases) && !r.releases.loaded && !r.releases.loading {
+		cmds = append(cmds, r.releases.Preload(r, r.progress))
+	}
+	if (contains(r.progress.names, metricDependents) || contains(r.progress.names, metricDownloads)) && !r.adoption.loaded && !r.adoption.loading {
+		cmds = append(cmds, r.adoption.Preload(r, r.progress))
+	}
+	return tea.Batch(cmds...)
+}
+
+// retry loads the repository again after an error.
+func (r *Repo) retry() tea.Cmd {
+	r.state = stateInit
+	r.error = nil
+	return r.Init()
 }
 
 func (r *Repo) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := r.update(msg)
+	r.layoutGraph()
+	if !r.image.Enabled() {
+		return m, cmd
+	}
+	// Banners are above the graph.
+	row := 1
+	if len(unstarAlerts(r.unstars, time.Now())) > 0 {
+		row++
+	}
+	if !r.stale.IsZero() {
+		row++
+	}
+	if r.renamed != "" {
+		row++
+	}
+	if r.failed.failed > 0 {
+		row++
+	}
+	if r.anomalies.Future > 0 {
+		row++
+	}
+	return m, tea.Batch(cmd, r.image.Sync(r.View(), row))
+}
+
+// loadView loads the data of the current view unless it was already loaded.
+func (r *Repo) loadView() tea.Cmd {
+	switch r.view {
+	case viewOrgs:
+		return r.orgs.Load(r)
+	case viewContributors:
+		return r.contribs.Load(r)
+	case viewStats:
+		return r.stats.Load(r)
+	case viewCycles:
+		return r.cycles.Load(r)
+	case viewQuality:
+		return r.quality.Load(r)
+	case viewNotable:
+		return r.notable.Load(r)
+	case viewAges:
+		return r.ages.Load(r)
+	case viewAlso:
+		return r.also.Load(r)
+	case viewRetention:
+		return r.retention.Load(r)
+	case viewIssues:
+		return r.issues.Load(r)
+	case viewAdoption:
+		return r.adoption.Load(r)
+	}
+	return nil
+}
+
+func (r *Repo) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	cmds := make([]tea.Cmd, 0)
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		r.width = msg.Width
-		r.height = msg.Height
-		r.help.Width = r.width
-		r.table.SetWidth(r.width)
-		r.table.SetHeight(r.height - 1)
+		// Lay out the first size right away, and debounce the following
+		// ones as re-plotting is expensive while the terminal is resized.
+		if r.width == 0 {
+			r.resize(msg.Width, msg.Height)
+			break
+		}
+		r.resizeID++
+		id := r.resizeID
+		cmds = append(cmds, tea.Tick(resizeDebounce, func(time.Time) tea.Msg {
+			return resizeMsg{id: id, width: msg.Width, height: msg.Height}
+		}))
+	case resizeMsg:
+		if msg.id == r.resizeID {
+			r.resize(msg.width, msg.height)
+		}
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c":
+		if r.prompt.Active() {
+			return r, r.prompt.Update(msg)
+		}
+		switch {
+		case key.Matches(msg, r.keys.Quit):
 			return r, tea.Quit
-		case "tab", "shift+tab":
-			r.view = (r.view + 1) % 2
-		case "?":
+		case key.Matches(msg, r.keys.Retry) && r.state == stateError:
+			cmds = append(cmds, r.retry())
+		case key.Matches(msg, r.keys.Retry) && r.failed.failed > 0:
+			cmds = append(cmds, r.toast.Show(fmt.Sprintf("Retrying %d failed pages...", r.failed.failed)), r.fetchStargazers)
+		case key.Matches(msg, r.keys.Command):
+			r.prompt.Start(":", "", r.runCommand)
+			return r, nil
+		case key.Matches(msg, r.keys.Filter) && r.tableShown() && !r.day.Active():
+			r.prompt.Start("/", r.tableFilter, r.filterTable)
+			return r, nil
+		case key.Matches(msg, r.keys.Screenshot):
+			cmds = append(cmds, screenshot(r.name, r.frame(), r.pngShots))
+		case key.Matches(msg, r.keys.Section):
+			r.day.Stop()
+			r.view = (r.view + 1) % viewCount
+			cmds = append(cmds, r.loadView())
+		case key.Matches(msg, r.keys.Help):
 			r.showHelp = !r.showHelp
+		case key.Matches(msg, r.keys.Day) && r.tableShown() && !r.explain.Active():
+			cmds = append(cmds, r.day.Toggle(r))
+		case key.Matches(msg, r.keys.OpenWeb) && r.day.Active():
+			cmds = append(cmds, r.day.OpenProfile())
+		case key.Matches(msg, r.keys.Explain, r.keys.Day):
+			// Outside of the table of days, Day explains the selected row
+			// too.
+			r.explain.Toggle(r)
+		case key.Matches(msg, r.keys.RateLimits):
+			cmds = append(cmds, r.limits.Toggle(r.client, r.offline))
+		case key.Matches(msg, r.keys.Play):
+			if r.view == viewGraph && len(r.stargazers) > 0 {
+				r.inspect.Stop()
+				cmds = append(cmds, r.playback.Toggle(len(r.graph.Keys())))
+			}
+		case key.Matches(msg, r.keys.Faster):
+			r.playback.Faster()
+		case key.Matches(msg, r.keys.Slower):
+			r.playback.Slower()
+		case key.Matches(msg, r.keys.Stop):
+			r.playback.Stop()
+			r.inspect.Stop()
+			r.spikes.Stop()
+			r.compare.Stop()
+			r.pinned.Stop()
+			r.limits.Stop()
+			r.explain.Stop()
+			r.day.Stop()
+		case key.Matches(msg, r.keys.Inspect):
+			if r.view == viewGraph && len(r.stargazers) > 0 {
+				r.playback.Stop()
+				r.inspect.Toggle(len(r.graph.Keys()))
+			}
+		case key.Matches(msg, r.keys.Left):
+			if r.view == viewGraph {
+				r.inspect.Move(-1, len(r.graph.Keys()))
+			}
+		case key.Matches(msg, r.keys.Right):
+			if r.view == viewGraph {
+				r.inspect.Move(1, len(r.graph.Keys()))
+			}
+		case key.Matches(msg, r.keys.ZoomIn):
+			if r.view == viewGraph {
+				r.graph.Zoom(2, dayOf(time.Now()))
+			}
+		case key.Matches(msg, r.keys.ZoomOut):
+			if r.view == viewGraph {
+				r.graph.Zoom(0.5, dayOf(time.Now()))
+			}
+		case key.Matches(msg, r.keys.RangeAll, r.keys.RangeWeek, r.keys.RangeMonth, r.keys.RangeQuarter, r.keys.RangeYear):
+			if r.view == viewGraph {
+				// Presets always parse.
+				r.graph.From, r.graph.To, _ = ParseRange(r.keys.rangePreset(msg), time.Now().In(timeZone))
+			}
+		case key.Matches(msg, r.keys.PanLeft):
+			if r.view == viewGraph {
+				r.graph.Pan(-1, dayOf(time.Now()))
+			}
+		case key.Matches(msg, r.keys.PanRight):
+			if r.view == viewGraph {
+				r.graph.Pan(1, dayOf(time.Now()))
+			}
+		case key.Matches(msg, r.keys.Spikes):
+			if r.view == viewGraph && len(r.stargazers) > 0 {
+				r.playback.Stop()
+				cmds = append(cmds, r.spikes.Toggle(r))
+			}
+		case key.Matches(msg, r.keys.Watch):
+			if len(r.stargazers) == 0 && r.stargazers != nil && !r.offline {
+				cmds = append(cmds, r.firstStar.Toggle())
+			}
+		case key.Matches(msg, r.keys.Releases):
+			if r.view == viewGraph && len(r.stargazers) > 0 {
+				cmds = append(cmds, r.releases.Toggle(r))
+			}
+		case key.Matches(msg, r.keys.Events):
+			if r.view == viewGraph && len(r.stargazers) > 0 {
+				cmds = append(cmds, r.events.Toggle(r))
+			}
+		case key.Matches(msg, r.keys.OpenWeb):
+			name := r.name
+			cmds = append(cmds, func() tea.Msg {
+				if err := OpenWeb(io.Discard, name); err != nil {
+					log.Printf("opening star-history.com: %v", err)
+				}
+				return nil
+			})
+		case key.Matches(msg, r.keys.Pin):
+			if r.view == viewGraph && len(r.stargazers) > 0 {
+				r.playback.Stop()
+				r.inspect.Stop()
+				r.compare.Stop()
+				r.pinned.Toggle(r)
+			}
+		case key.Matches(msg, r.keys.Smoothing):
+			r.graph.Smoothing = r.graph.Smoothing.Next()
+		case key.Matches(msg, r.keys.LogScale):
+			r.graph.LogScale = !r.graph.LogScale
+		case key.Matches(msg, r.keys.Buckets):
+			r.graph.Buckets = !r.graph.Buckets
+		case key.Matches(msg, r.keys.Growth):
+			r.graph.Growth = !r.graph.Growth
+		case key.Matches(msg, r.keys.Total):
+			r.graph.Cumulative = !r.graph.Cumulative
+		case key.Matches(msg, r.keys.Columns):
+			if r.view == viewTable {
+				r.table.NextColumns()
+				r.sizeTable()
+			}
+		case key.Matches(msg, r.keys.Compare):
+			if r.view == viewGraph && len(r.stargazers) > 0 {
+				r.playback.Stop()
+				r.inspect.Stop()
+				r.compare.Toggle()
+			}
+		case key.Matches(msg, r.keys.PrevSpike):
+			if r.view == viewGraph {
+				cmds = append(cmds, r.spikes.Move(-1, r))
+			}
+		case key.Matches(msg, r.keys.NextSpike):
+			if r.view == viewGraph {
+				cmds = append(cmds, r.spikes.Move(1, r))
+			}
 		}
-		if r.view == viewTable {
+		if r.day.Active() {
+			var cmd tea.Cmd
+			r.day.table, cmd = r.day.table.Update(msg)
+			cmds = append(cmds, cmd)
+			return r, tea.Batch(cmds...)
+		}
+		switch r.view {
+		case viewGraph:
+			switch {
+			case r.pinned.Active():
+				var cmd tea.Cmd
+				r.pinned.table, cmd = r.pinned.table.Update(msg)
+				cmds = append(cmds, cmd)
+			case r.tableShown() && !r.keys.matchesView(msg):
+				// The table next to the graph moves with the keys the
+				// graph doesn't use, so Day and Explain follow its cursor.
+				var cmd tea.Cmd
+				r.table, cmd = r.table.Update(msg)
+				cmds = append(cmds, cmd)
+			}
+		case viewTable:
 			var cmd tea.Cmd
 			r.table, cmd = r.table.Update(msg)
 			cmds = append(cmds, cmd)
+		case viewOrgs:
+			var cmd tea.Cmd
+			r.orgs.table, cmd = r.orgs.table.Update(msg)
+			cmds = append(cmds, cmd)
+		case viewQuality:
+			var cmd tea.Cmd
+			r.quality.table, cmd = r.quality.table.Update(msg)
+			cmds = append(cmds, cmd)
+		case viewNotable:
+			var cmd tea.Cmd
+			r.notable.table, cmd = r.notable.table.Update(msg)
+			cmds = append(cmds, cmd)
+		case viewRetention:
+			var cmd tea.Cmd
+			r.retention.table, cmd = r.retention.table.Update(msg)
+			cmds = append(cmds, cmd)
+		case viewCampaigns:
+			var cmd tea.Cmd
+			r.campaigns.table, cmd = r.campaigns.table.Update(msg)
+			cmds = append(cmds, cmd)
 		}
 	case ErrorMsg:
 		r.state = stateError
 		r.error = msg.(error)
+		cmds = append(cmds, alertCmd(r.alert, fmt.Sprintf("gh-stars: error loading %s", r.name)))
+	case screenshotMsg:
+		text := "Saved " + strings.Join(msg.paths, ", ")
+		if msg.err != nil {
+			text = fmt.Sprintf("Error saving screenshot: %s", msg.err)
+		}
+		cmds = append(cmds, r.toast.Show(text))
+	case DayMsg:
+		r.day.SetAccounts(msg)
+	case RateLimitMsg:
+		r.limits.SetLimits(msg)
+	case rateTickMsg:
+		cmds = append(cmds, r.limits.Tick(msg))
+	case toastTimeoutMsg:
+		r.toast.Hide(msg)
+	case StaleMsg:
+		r.sources = msg.Sources
+		r.setStale(msg.Entry)
+		cmds = append(cmds, r.startupCmd())
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		r.spinner, cmd = r.spinner.Update(msg)
 		cmds = append(cmds, cmd)
+	case scriptMsg:
+		cmds = append(cmds, r.runStep(msg))
+	case playbackTickMsg:
+		cmds = append(cmds, r.playback.Advance(len(r.graph.Keys())))
 	case StargazersMsg:
-		r.stargazers = msg
+		r.setStargazers(msg.Daily)
+		r.logins = msg.Logins
+		r.starredAt = msg.StarredAt
+		r.unstars = msg.Unstars
+		r.failed = failedPages{failed: msg.Failed, pages: msg.Pages}
+		r.anomalies = msg.Anomalies
+		if len(msg.Sources) > 1 {
+			r.sources = msg.Sources
+			r.stars = 0
+			for _, stars := range msg.Sources {
+				r.stars += stars
+			}
+		}
+		// Make room for the unstar alert.
+		r.resize(r.width, r.termHeight)
+		cmds = append(cmds, r.startupCmd(), r.runScript(), alertCmd(r.alert, fmt.Sprintf("gh-stars: %s loaded, %s stars", r.name, formatNumber(r.stars))))
+	case OrgsMsg:
+		r.orgs.SetCounts(msg)
+	case QualityMsg:
+		r.quality.SetAccounts(msg)
+	case NotableMsg:
+		r.notable.SetAccounts(msg)
+	case AgesMsg:
+		r.ages.SetAccounts(msg)
+	case AlsoMsg:
+		r.also.SetStarred(msg)
+	case RetentionMsg:
+		r.retention.SetUnstarred(msg)
+	case IssuesMsg:
+		r.issues.SetIssues(msg)
+	case AdoptionMsg:
+		r.adoption.SetAdoption(msg)
+	case CyclesMsg:
+		r.cycles.SetReleases(msg)
+	case ContributorsMsg:
+		r.contribs.SetMonthly(msg)
+	case SourceHintsMsg:
+		r.spikes.SetHints(msg)
+	case ReleasesMsg:
+		r.releases.SetReleases(msg)
+	case EventsMsg:
+		r.events.SetEvents(msg)
+	case graphImageMsg:
+		r.image.Draw(msg)
+	case StatsMsg:
+		r.stats.SetStats(msg)
+	case watchTickMsg:
+		cmds = append(cmds, r.firstStar.Poll(r))
+	case watchMsg:
+		cmds = append(cmds, r.firstStar.Update(r, msg))
 	case RepoMsg:
+		name := canonicalName(r.name, msg)
+		if !strings.EqualFold(name, r.name) {
+			r.renamed = r.name
+			// Make room for the banner once the size is known.
+			if r.termHeight > 0 {
+				r.resize(r.width, r.termHeight)
+			}
+		}
+		r.name = name
+		r.repo = msg
 		r.stars = msg.StargazersCount
 		r.state = stateReady
-		cmds = append(cmds, func() tea.Msg {
-			stargazers, err := r.GetStargazers()
-			if err != nil {
-				return ErrorMsg(err)
-			}
-			stars := make(map[string]int)
-			for _, s := range stargazers {
-				t := s.StarredAt.Format("2006-01-02")
-				stars[t]++
-			}
-			return StargazersMsg(stars)
-		})
+		if r.sample > 0 {
+			cmds = append(cmds, r.sampleStargazers)
+			break
+		}
+		cmds = append(cmds, r.fetchStargazers)
 	}
 	return r, tea.Batch(cmds...)
 }
 
+// fetchStargazers fetches and caches the stargazers of the repository. When
+// only some pages fail, the others are shown and the history isn't cached.
+func (r *Repo) fetchStargazers() tea.Msg {
+	var anomalies stars.Anomalies
+	stargazers, err := r.GetStargazers(anomalies.Merge)
+	if errors.Is(err, stars.ErrTooManyStargazers) {
+		return ErrorMsg(fmt.Errorf("%w, run with --sample 10 to approximate the history", err))
+	}
+	var pagesErr *stars.PagesError
+	// The cached history is more complete when the network is down.
+	partial := errors.As(err, &pagesErr) && len(stargazers) > 0 && !isNetworkError(err)
+	if err != nil && !partial {
+		return r.fallback(err)
+	}
+	var entry *stars.History
+	if partial {
+		// The missing stargazers aren't unstars.
+		entry = stars.NewHistory(r.name, r.stars, stargazers, dailyBucketer())
+	} else {
+		entry = NewHistory(r.name, r.stars, stargazers)
+		if err := SaveCache(entry); err != nil {
+			log.Printf("saving cache: %v", err)
+		}
+	}
+	entry, sources, err := mergeAliases(r.client, entry, r.aliases, false)
+	if err != nil {
+		return ErrorMsg(err)
+	}
+	msg := StargazersMsg{
+		Daily:     entry.Stargazers,
+		Logins:    entry.Logins,
+		StarredAt: entry.StarredAt,
+		Unstars:   entry.Unstars,
+		Sources:   sources,
+		Anomalies: anomalies,
+	}
+	if partial {
+		msg.Failed, msg.Pages = len(pagesErr.Failed), pagesErr.Pages
+	}
+	return msg
+}
+
+// resizeDebounce is how long the terminal size must stay the same before the
+// views are laid out again.
+const resizeDebounce = 100 * time.Millisecond
+
+// resizeMsg lays out the views for a terminal size unless it was resized
+// again since.
+type resizeMsg struct {
+	id     int
+	width  int
+	height int
+}
+
+// resize sets the size of the repository view and its components, leaving
+// room for the stale data banner.
+func (r *Repo) resize(width, height int) {
+	r.termHeight = height
+	if !r.stale.IsZero() {
+		height--
+	}
+	if r.renamed != "" {
+		height--
+	}
+	if len(unstarAlerts(r.unstars, time.Now())) > 0 {
+		height--
+	}
+	if r.failed.failed > 0 {
+		height--
+	}
+	if r.anomalies.Future > 0 {
+		height--
+	}
+	r.width = width
+	r.height = height
+	r.help.Width = r.width
+	r.table.SetHeight(r.height - 1)
+	r.sizeTable()
+	r.orgs.SetSize(r.width, r.height)
+	r.quality.SetSize(r.width, r.height)
+	r.notable.SetSize(r.width, r.height)
+	r.retention.SetSize(r.width, r.height)
+	r.campaigns.SetSize(r.width, r.height)
+	r.day.SetSize(r.width, r.height)
+	r.pinned.table.SetHeight(r.height - 2)
+}
+
 func (r *Repo) View() string {
+	if v, ok := tooSmall(r.width, r.termHeight); ok {
+		return v
+	}
+	return r.toast.View(r.prompt.View(r.frame()), r.theme)
+}
+
+// frame renders the current view without the toast.
+func (r *Repo) frame() string {
+	v := r.render()
+	if len(unstarAlerts(r.unstars, time.Now())) > 0 {
+		v = r.unstarBanner() + "\n" + v
+	}
+	if r.failed.failed > 0 {
+		v = r.failedBanner() + "\n" + v
+	}
+	if r.anomalies.Future > 0 {
+		v = r.anomaliesBanner() + "\n" + v
+	}
+	if !r.stale.IsZero() {
+		v = r.staleBanner() + "\n" + v
+	}
+	if r.renamed != "" {
+		v = r.renamedBanner() + "\n" + v
+	}
+	if r.ascii {
+		v = asciiReplacer.Replace(v)
+	}
+	return v
+}
+
+func (r *Repo) render() string {
+	if r.limits.active {
+		return r.limits.View(r)
+	}
 	if (r.state != stateReady || r.stargazers == nil) && r.state != stateError {
+		if bars := r.progress.View(r.theme); bars != "" {
+			return fmt.Sprintf("\n %s loading...\n\n%s\n", r.spinner.View(), bars)
+		}
 		return fmt.Sprintf("\n %s loading...\n", r.spinner.View())
 	}
 	if r.state == stateError {
-		return fmt.Sprintf("\n Error: %s", r.error)
+		return fmt.Sprintf("\n Error: %s\n\n Press %s to retry or %s to quit.", r.error, r.keys.Retry.Help().Key, r.keys.Quit.Help().Key)
+	}
+	if r.explain.Active() {
+		return r.explain.View(r)
+	}
+	if r.day.Active() {
+		return r.day.View(r)
 	}
 	if r.showHelp {
+		r.help.ShowAll = r.termHeight >= compactHeight
 		return lipgloss.Place(
 			r.width,
 			r.height,
@@ -255,78 +892,682 @@ func (r *Repo) View() string {
 			r.help.View(r),
 		)
 	}
-	keys := make([]string, 0)
-	for k := range r.stargazers {
-		keys = append(keys, k)
+	keys := r.graph.Keys()
+	if len(keys) == 0 && (r.view == viewGraph || r.view == viewTable) {
+		return r.firstStar.View(r)
+	}
+	if r.split() && (r.view == viewGraph || r.view == viewTable) {
+		return lipgloss.JoinHorizontal(
+			lipgloss.Top,
+			r.graphView(keys),
+			" ",
+			r.tableView(),
+		)
 	}
-	sort.Strings(keys)
 	switch r.view {
 	case viewGraph:
-		if len(keys) == 0 {
-			return "\n No stargazers found.\n"
-		}
-		offset := 3
-		plot := make([]float64, len(keys))
-		for i, k := range keys {
-			o := fmt.Sprintf("%d", r.stargazers[k])
-			if len(o) > offset {
-				offset = len(o)
-			}
-			plot[i] = float64(r.stargazers[k])
-		}
-		graph := asciigraph.Plot(
-			plot,
-			asciigraph.SeriesColors(asciigraph.Blue),
-			asciigraph.Width(r.width-offset-1),
-			asciigraph.Height(r.height-2),
-			asciigraph.Caption(fmt.Sprintf("%s %d stargazers over time", r.name, r.stars)),
-			asciigraph.Precision(0),
-			asciigraph.Offset(offset),
-		)
-		return graph
+		return r.graphView(keys)
 	case viewTable:
-		rows := make([]table.Row, len(keys))
-		for i, j := len(keys)-1, 0; i >= 0; i, j = i-1, j+1 {
-			k := keys[i]
-			rows[j] = table.Row{k, fmt.Sprintf("%d", r.stargazers[k])}
-		}
-		r.table.SetRows(rows)
-		return r.table.View()
+		return r.tableView()
+	case viewOrgs:
+		return r.orgs.View(r)
+	case viewContributors:
+		return r.contribs.View(r)
+	case viewStats:
+		return r.stats.View(r)
+	case viewCycles:
+		return r.cycles.View(r)
+	case viewQuality:
+		return r.quality.View(r)
+	case viewNotable:
+		return r.notable.View(r)
+	case viewAges:
+		return r.ages.View(r)
+	case viewPatterns:
+		return r.patternsView()
+	case viewAlso:
+		return r.also.View(r)
+	case viewRetention:
+		return r.retention.View(r)
+	case viewIssues:
+		return r.issues.View(r)
+	case viewAdoption:
+		return r.adoption.View(r)
+	case viewCampaigns:
+		return r.campaigns.View(r)
 	default:
 		return ""
 	}
 }
 
-func main() {
-	var repo string
-	pflag.Parse()
-	r, err := gh.CurrentRepository()
-	if err == nil {
-		repo = fmt.Sprintf("%s/%s", r.Owner(), r.Name())
+// tableShown returns whether the table of daily stargazers is shown, on its
+// own or next to the graph.
+func (r *Repo) tableShown() bool {
+	return r.view == viewTable || r.view == viewGraph && r.split() && !r.pinned.Active() && !r.compare.Active()
+}
+
+// split returns whether the graph and the table are shown side by side.
+func (r *Repo) split() bool {
+	switch r.layout {
+	case layoutSplit:
+		return r.width >= minSplitWidth
+	case layoutAuto:
+		return r.width >= autoSplitWidth
+	default:
+		return false
 	}
-	if len(pflag.Args()) > 0 {
-		repo = pflag.Args()[0]
+}
+
+// graphWidth returns the width available to the graph.
+func (r *Repo) graphWidth() int {
+	if r.split() {
+		return r.width - r.table.ColumnsWidth() - 1
 	}
-	if repo == "" {
-		fmt.Printf("Error: no repository specified\n\n%s\n", "Usage: gh stars [repository]")
-		os.Exit(1)
+	return r.width
+}
+
+// setStargazers sets the daily stargazers of the repository and its
+// components.
+func (r *Repo) setStargazers(daily map[string]int) {
+	r.stargazers = daily
+	r.graph.SetData(daily)
+	r.table.SetData(daily)
+	r.campaigns.SetData(daily)
+	r.playback.Clamp(len(r.graph.Keys()))
+	r.inspect.Clamp(len(r.graph.Keys()))
+}
+
+// graphCaption returns the caption of the graph, describing how it's
+// plotted.
+func (r *Repo) graphCaption() string {
+	caption := fmt.Sprintf("%s %s stargazers over time (%s)", r.name, formatNumber(r.stars), timeZone)
+	if r.repo.Archived {
+		caption += " (archived)"
+	}
+	if r.sample > 0 {
+		caption += " (approximate)"
+	}
+	if len(r.sources) > 1 {
+		caption += " (" + sourcesCaption(r.sources) + ")"
+	}
+	switch r.graph.Smoothing {
+	case starsui.SmoothMovingAverage:
+		caption += ", 7-day average"
+	case starsui.SmoothLoess:
+		caption += ", smoothed"
+	}
+	if r.graph.LogScale {
+		caption += ", log scale"
+	}
+	if r.graph.Buckets {
+		caption += ", stars per day by day, week, then month"
+	}
+	if r.graph.Growth {
+		caption += ", % growth over the stars before"
+	}
+	if r.graph.Cumulative {
+		caption += ", total stars on the right"
+	}
+	if r.graph.Windowed() {
+		caption += fmt.Sprintf(", %s to %s", formatDay(r.graph.From), formatDay(r.graph.To))
+	}
+	return caption
+}
+
+// layoutGraph sizes the graph to the panes shown below it and sets its
+// caption and colors. It runs after every update, as the size, the panes and
+// the options of the graph change in many of them, so views only render.
+func (r *Repo) layoutGraph() {
+	height := r.height - 1
+	if r.inspect.Active() {
+		height--
+	}
+	if r.spikes.Active() {
+		height -= 1 + spikePaneHeight
+	}
+	if r.releases.Active() {
+		height -= 2
+	}
+	if r.events.Active() {
+		height -= 2
+	}
+	r.graphHeight = height
+	r.graph.SetSize(r.graphWidth(), height)
+	r.graph.Caption = r.graphCaption()
+	if r.termHeight < compactHeight {
+		r.graph.Caption = ""
+	}
+	r.graph.Colors = r.theme.Colors()
+}
+
+func (r *Repo) graphView(keys []string) string {
+	if r.playback.Active() {
+		return r.playback.View(r, keys)
+	}
+	if r.compare.Active() {
+		return r.compare.View(r)
+	}
+	if r.pinned.Active() {
+		return r.pinned.View(r)
+	}
+	var graph string
+	switch {
+	case r.a11y:
+		graph = r.narration(keys, r.graphHeight)
+	case r.inspect.Active():
+		graph = r.inspect.View(r, keys, r.graph.View(), r.graph.Column(r.inspect.index))
+	case r.image.Enabled():
+		graph = r.image.Placeholder(r.graph, r.graphHeight)
+	default:
+		graph = r.graph.View()
+	}
+	if r.releases.Active() {
+		graph += "\n" + r.releases.View(r, keys)
+	}
+	if r.events.Active() {
+		graph += "\n" + r.events.View(r, keys)
+	}
+	if r.spikes.Active() {
+		graph += "\n" + r.spikes.Markers(r, keys) + "\n" + r.spikes.View(r)
+	}
+	return graph
+}
+
+// sizeTable fits the table of daily stargazers to its columns next to the
+// graph, or to the width. Setting the width renders the rows around the
+// cursor, the table doesn't render the others, so it's only done when the
+// size or the columns change rather than on every view.
+func (r *Repo) sizeTable() {
+	if r.split() {
+		r.table.SetWidth(r.table.ColumnsWidth())
+	} else {
+		r.table.SetWidth(r.width)
+	}
+}
+
+func (r *Repo) tableView() string {
+	return r.table.View()
+}
+
+// uiFlags are the flags of the commands showing the TUI.
+type uiFlags struct {
+	debug      *bool
+	theme      *string
+	noColor    *bool
+	ascii      *bool
+	a11y       *bool
+	layout     *string
+	chart      *string
+	tz         *string
+	locale     *string
+	dateFormat *string
+	graphics   *string
+}
+
+func addUIFlags(flags *pflag.FlagSet) *uiFlags {
+	addTokenFlag(flags)
+	addMaxRequestsFlag(flags)
+	addProfileFlag(flags)
+	return &uiFlags{
+		debug:      flags.BoolP("debug", "d", false, "enable debug output"),
+		theme:      flags.StringP("theme", "t", "", "color theme: "+strings.Join(ThemeNames(), ", ")),
+		noColor:    flags.Bool("no-color", false, "disable colors, also enabled by the NO_COLOR environment variable"),
+		ascii:      flags.Bool("ascii", false, "use plain ASCII characters without colors"),
+		a11y:       flags.Bool("a11y", false, "replace the graph with a text narration for screen readers, implies --ascii and --layout single"),
+		layout:     flags.String("layout", layoutAuto, "layout of the graph and table: auto, split, single"),
+		chart:      flags.String("chart-style", "line", "style of the graph: "+strings.Join(starsui.ChartStyles, ", ")),
+		tz:         flags.String("tz", "UTC", "time zone of the days stargazers are counted in: UTC, local, or an IANA name"),
+		locale:     flags.String("locale", "auto", "locale of the digit grouping of counts: auto from LC_ALL, LC_NUMERIC or LANG, C, or a name like de-DE"),
+		dateFormat: flags.String("date-format", dateFormatISO, "format of the dates shown: iso, locale, or a Go layout like 02.01.2006"),
+		graphics:   flags.String("graphics", "ascii", "draw the graph with a terminal graphics protocol: auto, "+strings.Join(starsui.GraphicsProtocols, ", ")),
+	}
+}
+
+// Options returns the display options set by the flags and the config file.
+func (f *uiFlags) Options(cfg *Config) (Options, error) {
+	if *f.theme == "" {
+		*f.theme = cfg.Theme
+	}
+	// Screen readers read the screen line by line, so the views are shown
+	// one at a time without box drawing characters.
+	if *f.a11y {
+		*f.ascii = true
+		*f.layout = layoutSingle
+	}
+	var theme Theme
+	if _, ok := os.LookupEnv("NO_COLOR"); ok || *f.noColor || *f.ascii {
+		theme = PlainTheme()
+	} else {
+		var err error
+		theme, err = NewTheme(*f.theme, cfg.Colors)
+		if err != nil {
+			return Options{}, err
+		}
+		// Detect the terminal background before the TUI takes over the
+		// input.
+		lipgloss.HasDarkBackground()
 	}
-	m, err := NewRepo(repo)
+	switch *f.layout {
+	case layoutAuto, layoutSplit, layoutSingle:
+	default:
+		return Options{}, fmt.Errorf("unknown layout %q", *f.layout)
+	}
+	tz, err := ParseTimeZone(*f.tz)
 	if err != nil {
-		log.Fatalln(err)
+		return Options{}, err
+	}
+	timeZone = tz
+	if err := SetLocale(*f.locale, *f.dateFormat); err != nil {
+		return Options{}, err
+	}
+	renderer, err := starsui.NewRenderer(*f.chart)
+	if err != nil {
+		return Options{}, err
 	}
-	if *debug {
-		f, err := tea.LogToFile("debug.txt", "gh-stars")
+	// Braille characters have no ASCII equivalent.
+	if _, ok := renderer.(starsui.BrailleRenderer); ok && *f.ascii {
+		renderer = starsui.AsciigraphRenderer{}
+	}
+	graphics, err := ParseGraphics(*f.graphics)
+	if err != nil {
+		return Options{}, err
+	}
+	if *f.ascii {
+		graphics = starsui.GraphicsASCII
+	}
+	for _, set := range cfg.Columns {
+		if err := starsui.ValidateColumns(set); err != nil {
+			return Options{}, err
+		}
+	}
+	for _, list := range cfg.Campaigns {
+		for _, c := range list {
+			if err := c.Validate(); err != nil {
+				return Options{}, err
+			}
+		}
+	}
+	keys := DefaultKeyMap()
+	if err := keys.Remap(cfg.Keys); err != nil {
+		return Options{}, err
+	}
+	return Options{
+		Graphics:  graphics,
+		Theme:     theme,
+		ThemeName: *f.theme,
+		ASCII:     *f.ascii,
+		A11y:      *f.a11y,
+		Layout:    *f.layout,
+		Renderer:  renderer,
+		Momentum:  cfg.Momentum,
+		Aliases:   cfg.Aliases,
+		Columns:   cfg.Columns,
+		Campaigns: cfg.Campaigns,
+		KeyMap:    keys,
+	}, nil
+}
+
+// Run runs the TUI until it quits.
+func (f *uiFlags) Run(m tea.Model) {
+	if *f.debug {
+		file, err := tea.LogToFile("debug.txt", "gh-stars")
 		if err != nil {
 			log.Fatalln(err)
 		}
-		defer f.Close()
+		defer file.Close()
+	} else {
+		// Don't let log output mess up the TUI.
+		log.SetOutput(io.Discard)
 	}
 	p := tea.NewProgram(
 		m,
 		tea.WithAltScreen(),
 	)
-	if err := p.Start(); err != nil {
+	_, err := p.Run()
+	log.SetOutput(os.Stderr)
+	writeProfile()
+	if err != nil {
+		log.Fatalln(err)
+	}
+}
+
+func runView(args []string) {
+	flags := pflag.NewFlagSet("view", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gh stars [view] [repository] [flags]\n")
+		fmt.Fprintf(os.Stderr, "       gh stars <command> [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Show the stargazers of a repository, the current one by default.\n\n")
+		fmt.Fprintf(os.Stderr, "Commands:\n")
+		fmt.Fprintf(os.Stderr, "  view          show the stargazers of a repository\n")
+		fmt.Fprintf(os.Stderr, "  compare       overlay the stargazers of several repositories\n")
+		fmt.Fprintf(os.Stderr, "  activity      show when a user starred repositories\n")
+		fmt.Fprintf(os.Stderr, "  diff          compare the stars gained between two dates\n")
+		fmt.Fprintf(os.Stderr, "  export        write the stargazers history to a file\n")
+		fmt.Fprintf(os.Stderr, "  export-users  write the stargazers to a JSON file\n")
+		fmt.Fprintf(os.Stderr, "  import        merge stargazers histories into the cache\n")
+		fmt.Fprintf(os.Stderr, "  report        print or post a Markdown report\n")
+		fmt.Fprintf(os.Stderr, "  publish       upload the stargazers history to a gist\n")
+		fmt.Fprintf(os.Stderr, "  record        append a snapshot of repositories to a database file\n")
+		fmt.Fprintf(os.Stderr, "  run           run the report jobs of a workspace file\n")
+		fmt.Fprintf(os.Stderr, "  notify        announce milestones, spikes, and unstar waves\n")
+		fmt.Fprintf(os.Stderr, "  check         fail if repositories lost many stargazers in a day\n")
+		fmt.Fprintf(os.Stderr, "  serve         serve the stargazers history over HTTP\n")
+		fmt.Fprintf(os.Stderr, "  cache         manage the cached data\n")
+		fmt.Fprintf(os.Stderr, "  config        validate the config file\n")
+		fmt.Fprintf(os.Stderr, "  audit         list the API requests made by gh-stars\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		flags.PrintDefaults()
+	}
+	ui := addUIFlags(flags)
+	importFile := flags.String("import", "", "visualize a previously exported .csv or .json file without making API calls")
+	fromGist := flags.String("from-gist", "", "visualize the history published to a gist by gh stars publish, by ID or URL")
+	windows := flags.StringSlice("windows", nil, "windows to compare with c, as quarters like 2024Q1 or ranges like 2024-01-01..2024-03-31")
+	offline := flags.Bool("offline", false, "show the cached data without making API calls")
+	rangeFlag := flags.String("range", "", "plot a window of the graph: all, week, month, quarter, year, a quarter like 2024Q1, or a range like 2024-01-01..2024-03-31")
+	annotations := flags.String("annotations", "", "mark the events of a YAML file of dates and labels under the graph")
+	sessionName := flags.String("session", "", "restore a session saved with :w NAME")
+	plain := flags.Bool("plain", false, "print the stars of each day as a table instead of showing them, the default when stdout isn't a terminal")
+	summary := flags.Bool("summary", false, "print the landmark dates of the repository instead of showing it")
+	estimate := flags.Bool("estimate", false, "print how many API requests and about how long fetching the stargazers takes instead of showing them")
+	alert := flags.String("alert", alertNone, "alert emitted when the stargazers are loaded: none, bell, or osc9 for a desktop notification")
+	yes := flags.Bool("yes", false, "don't ask before fetching more than 100 pages of stargazers")
+	openWeb := flags.Bool("open-web", false, "open the star-history.com chart of the repository in the browser")
+	keys := flags.StringSlice("keys", nil, "keys to press once loaded, e.g. tab,c")
+	script := flags.String("script", "", "steps to run once loaded, e.g. \"range=year;view=graph;export=chart.svg;quit\"")
+	pngShots := flags.Bool("screenshot-png", false, "also save ctrl+s screenshots as PNG images")
+	smooth := flags.String("smooth", "none", "smoothing of the graph: none, 7d, loess")
+	logScale := flags.Bool("log-scale", false, "plot the graph on a log scale")
+	sample := flags.Int("sample", 0, "approximate the history from every nth page of stargazers, for huge repositories")
+	group := flags.String("group", "", "show the summed stargazers of a group of repositories of the config file")
+	dashboard := flags.Bool("dashboard", false, "show the repositories of the dashboard list of the config file")
+	user := flags.String("user", "", "show the public repositories of a user sorted by stars")
+	kiosk := flags.Bool("kiosk", false, "rotate through the views of the given or dashboard repositories for unattended displays, ctrl+c quits")
+	kioskInterval := flags.Duration("kiosk-interval", 30*time.Second, "time each kiosk view is shown")
+	kioskRefresh := flags.Duration("kiosk-refresh", 15*time.Minute, "interval between kiosk data refreshes")
+	snapshots := flags.String("snapshots", "", "plot the snapshots of the repository recorded in a gh stars record database file")
+	metricsFlag := flags.StringSlice("metrics", []string{metricStars}, "metrics to fetch concurrently while loading: "+strings.Join(metrics, ", "))
+	flags.Parse(args)
+	cfg, err := LoadConfig()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var session *Session
+	if *sessionName != "" {
+		s, err := LoadSession(*sessionName)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		// The flags take precedence over the session.
+		if !flags.Changed("theme") && s.Theme != "" {
+			*ui.theme = s.Theme
+		}
+		if flags.Changed("smooth") {
+			s.Smoothing = *smooth
+		}
+		if flags.Changed("log-scale") {
+			s.LogScale = *logScale
+		}
+		session = &s
+	}
+	opts, err := ui.Options(cfg)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	opts.Windows, err = ParseWindows(*windows)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	opts.Keys, err = ParseKeys(*keys)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	opts.Script, err = ParseScript(*script)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	opts.Offline = *offline
+	opts.LogScale = *logScale
+	opts.Sample = *sample
+	opts.Range = *rangeFlag
+	opts.Alert, err = ParseAlert(*alert)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if *annotations != "" {
+		if opts.Annotations, err = LoadAnnotations(*annotations); err != nil {
+			log.Fatalln(err)
+		}
+	}
+	opts.Smoothing, err = starsui.ParseSmoothing(*smooth)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	opts.ScreenshotPNG = *pngShots
+	opts.Metrics, err = ParseMetrics(*metricsFlag)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if *group != "" {
+		members, ok := cfg.Groups[*group]
+		if !ok || len(members) == 0 {
+			log.Fatalf("unknown group %q, define it in the groups of the config file", *group)
+		}
+		m, err := NewGroup(*group, members, opts)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		ui.Run(m)
+		return
+	}
+	if *kiosk {
+		repos := flags.Args()
+		if len(repos) == 0 {
+			repos = cfg.Dashboard
+		}
+		m, err := NewKiosk(repos, *kioskInterval, *kioskRefresh, opts)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		ui.Run(m)
+		return
+	}
+	if *user != "" {
+		m, err := NewUser(*user, opts)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		ui.Run(m)
+		return
+	}
+	if *dashboard {
+		m, err := NewDashboard(cfg.Dashboard, opts)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		ui.Run(m)
+		return
+	}
+	repo := currentRepo()
+	if session != nil {
+		repo = session.Repo
+	}
+	if flags.NArg() > 0 {
+		repo = flags.Arg(0)
+	}
+	if *snapshots != "" {
+		m, err := NewSnapshotGraph(*snapshots, repo, opts)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		ui.Run(m)
+		return
+	}
+	if *importFile != "" {
+		opts.Data, err = ImportFile(*importFile)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if repo != "" {
+			opts.Data.Name = repo
+		}
+		repo = opts.Data.Name
+	}
+	if *fromGist != "" {
+		client, err := NewClient()
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if opts.Data, err = FetchGistHistory(client, *fromGist); err != nil {
+			log.Fatalln(err)
+		}
+		repo = opts.Data.Name
+	}
+	if repo == "" && term.IsTerminal(os.Stdin) && term.IsTerminal(os.Stdout) {
+		repo, err = pickRepo(ui, opts)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if repo == "" {
+			return
+		}
+	}
+	if repo == "" {
+		fmt.Fprintf(os.Stderr, "Error: no repository specified\n\n")
+		flags.Usage()
+		os.Exit(1)
+	}
+	if *openWeb {
+		if err := OpenWeb(os.Stdout, repo); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+	if *estimate {
+		client, err := NewClient()
+		if err != nil {
+			log.Fatalln(err)
+		}
+		e, err := EstimateFetch(client, repo, *sample)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		if err := WriteEstimate(os.Stdout, e); err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+	// Plain output replaces the TUI when piped, and goes through the gh
+	// pager on terminals.
+	isTTY := term.IsTerminal(os.Stdout)
+	if *summary || *plain || !isTTY {
+		entry := opts.Data
+		if entry == nil {
+			client, err := NewClient()
+			if err != nil {
+				log.Fatalln(err)
+			}
+			if entry, err = LoadHistory(client, repo, time.Hour, *offline); err != nil {
+				log.Fatalln(err)
+			}
+		}
+		w, wait := startPager()
+		if *summary {
+			err = WriteLandmarks(w, entry)
+		} else {
+			width, _, _ := term.FromEnv().Size()
+			err = WritePlain(w, entry, isTTY, width)
+		}
+		if werr := wait(); err == nil {
+			err = werr
+		}
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return
+	}
+	// Ask before large fetches, unless the estimate can't be made, in which
+	// case the view reports the error.
+	if !*yes && opts.Data == nil && !*offline && term.IsTerminal(os.Stdin) {
+		if client, err := NewClient(); err == nil {
+			if e, err := EstimateFetch(client, repo, *sample); err == nil && !e.TooMany && e.Large() {
+				ok, err := confirmFetch(os.Stdin, os.Stderr, e)
+				if err != nil {
+					log.Fatalln(err)
+				}
+				if !ok {
+					return
+				}
+			}
+		}
+	}
+	m, err := NewRepo(repo, opts)
+	if err != nil {
 		log.Fatalln(err)
 	}
+	if session != nil {
+		if flags.Changed("range") {
+			session.From, session.To = m.graph.From, m.graph.To
+		}
+		if err := m.restore(*session); err != nil {
+			log.Fatalln(err)
+		}
+	}
+	ui.Run(m)
+}
+
+// currentRepo returns the repository of the current directory, or an empty
+// string if there is none.
+func currentRepo() string {
+	r, err := gh.CurrentRepository()
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s", r.Owner(), r.Name())
+}
+
+func main() {
+	args := os.Args[1:]
+	cmd := "view"
+	if len(args) > 0 {
+		switch args[0] {
+		case "view", "compare", "activity", "diff", "export", "export-users", "import", "report", "publish", "record", "run", "notify", "check", "serve", "cache", "config", "audit":
+			cmd, args = args[0], args[1:]
+		}
+	}
+	switch cmd {
+	case "compare":
+		runCompare(args)
+	case "activity":
+		runActivity(args)
+	case "diff":
+		runDiff(args)
+	case "run":
+		runWorkspace(args)
+	case "notify":
+		runNotify(args)
+	case "check":
+		runCheck(args)
+	case "export":
+		runExport(args)
+	case "export-users":
+		runExportUsers(args)
+	case "import":
+		runImport(args)
+	case "report":
+		runReport(args)
+	case "publish":
+		runPublish(args)
+	case "record":
+		runRecord(args)
+	case "serve":
+		runServe(args)
+	case "cache":
+		runCache(args)
+	case "config":
+		runConfig(args)
+	case "audit":
+		runAudit(args)
+	default:
+		runView(args)
+	}
 }
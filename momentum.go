@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+)
+
+// defaultMomentumWindow is the number of days momentum is measured over.
+const defaultMomentumWindow = 30
+
+// MomentumConfig configures the momentum ranking.
+type MomentumConfig struct {
+	// Window is the number of days momentum is measured over.
+	Window       int     `yaml:"window"`
+	Gain         float64 `yaml:"gain"`
+	Growth       float64 `yaml:"growth"`
+	Acceleration float64 `yaml:"acceleration"`
+}
+
+// withDefaults returns c with a default window and, if no weight is set,
+// equal weights.
+func (c MomentumConfig) withDefaults() MomentumConfig {
+	if c.Window <= 0 {
+		c.Window = defaultMomentumWindow
+	}
+	if c.Gain == 0 && c.Growth == 0 && c.Acceleration == 0 {
+		c.Gain, c.Growth, c.Acceleration = 1, 1, 1
+	}
+	return c
+}
+
+// Momentum is how fast a repository is gaining stars over a window.
+type Momentum struct {
+	Name string
+	// Gain is the number of stars gained over the window.
+	Gain int
+	// Growth is the gain relative to the stars at the start of the window.
+	Growth float64
+	// Acceleration is the gain of the second half of the window minus the
+	// gain of the first half, relative to the whole gain.
+	Acceleration float64
+	Score        float64
+}
+
+// RankMomentum ranks repositories by momentum over the window ending at now.
+// Each of the gain, growth, and acceleration is divided by its largest
+// absolute value among the repositories so they range from -1 to 1, and the
+// score is their weighted sum:
+//
+//	score = gain weight × gain / max gain
+//	      + growth weight × growth / max growth
+//	      + acceleration weight × acceleration / max acceleration
+//
+// Raw totals favor large repositories, while growth favors small ones and
+// acceleration favors those gaining traction, so combining them ranks
+// repositories of any size.
+func RankMomentum(entries []*stars.History, now time.Time, cfg MomentumConfig) []Momentum {
+	cfg = cfg.withDefaults()
+	start := now.AddDate(0, 0, -cfg.Window).Format("2006-01-02")
+	middle := now.AddDate(0, 0, -cfg.Window/2).Format("2006-01-02")
+	end := now.Format("2006-01-02")
+	ranking := make([]Momentum, len(entries))
+	var maxGain, maxGrowth, maxAccel float64
+	for i, entry := range entries {
+		var before, first, second int
+		for day, count := range entry.Stargazers {
+			switch {
+			case day <= start:
+				before += count
+			case day <= middle:
+				first += count
+			case day <= end:
+				second += count
+			}
+		}
+		m := Momentum{Name: entry.Name, Gain: first + second}
+		if before > 0 {
+			m.Growth = float64(m.Gain) / float64(before)
+		} else if m.Gain > 0 {
+			// A repository without stars before the window grew by all
+			// of its stars.
+			m.Growth = 1
+		}
+		if m.Gain > 0 {
+			m.Acceleration = float64(second-first) / float64(m.Gain)
+		}
+		maxGain = math.Max(maxGain, float64(m.Gain))
+		maxGrowth = math.Max(maxGrowth, math.Abs(m.Growth))
+		maxAccel = math.Max(maxAccel, math.Abs(m.Acceleration))
+		ranking[i] = m
+	}
+	for i := range ranking {
+		m := &ranking[i]
+		if maxGain > 0 {
+			m.Score += cfg.Gain * float64(m.Gain) / maxGain
+		}
+		if maxGrowth > 0 {
+			m.Score += cfg.Growth * m.Growth / maxGrowth
+		}
+		if maxAccel > 0 {
+			m.Score += cfg.Acceleration * m.Acceleration / maxAccel
+		}
+	}
+	sort.SliceStable(ranking, func(i, j int) bool {
+		return ranking[i].Score > ranking[j].Score
+	})
+	return ranking
+}
+
+// momentumTable renders a momentum ranking.
+func momentumTable(ranking []Momentum, window int) string {
+	width := len("Repository")
+	for _, m := range ranking {
+		if len(m.Name) > width {
+			width = len(m.Name)
+		}
+	}
+	lines := []string{
+		fmt.Sprintf(" Momentum over the last %d days", window),
+		"",
+		fmt.Sprintf(" %4s  %-*s %8s %8s %8s %7s", "Rank", width, "Repository", "Gain", "Growth", "Accel.", "Score"),
+	}
+	for i, m := range ranking {
+		lines = append(lines, fmt.Sprintf(" %4d  %-*s %+8d %7.1f%% %+7.0f%% %7.2f",
+			i+1, width, m.Name, m.Gain, 100*m.Growth, 100*m.Acceleration, m.Score))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// narrationDate is the layout of the dates of narrations, spelled out for
+// screen readers.
+const narrationDate = "Jan 2, 2006"
+
+// spellDay returns a day of the 2006-01-02 layout as read out in
+// narrations.
+func spellDay(day string) string {
+	t, err := time.Parse("2006-01-02", day)
+	if err != nil {
+		return day
+	}
+	return t.Format(narrationDate)
+}
+
+// starsOf spells a number of stars.
+func starsOf(n int) string {
+	if n == 1 {
+		return "1 star"
+	}
+	return fmt.Sprintf("%d stars", n)
+}
+
+// Narrate describes the stars gained by a repository between from and to,
+// both included, as sentences replacing the graph for screen readers. The
+// stars of each month follow, the most recent months up to months when the
+// window spans more of them.
+func Narrate(name string, daily map[string]int, from, to string, months int) []string {
+	days := dayRange(from, to)
+	var gained, active, peak int
+	peakDay := ""
+	monthly := make(map[string]int)
+	order := make([]string, 0)
+	for _, day := range days {
+		n := daily[day]
+		gained += n
+		if n > 0 {
+			active++
+		}
+		if n > peak {
+			peak, peakDay = n, day
+		}
+		if _, ok := monthly[day[:7]]; !ok {
+			order = append(order, day[:7])
+		}
+		monthly[day[:7]] += n
+	}
+	if gained == 0 {
+		return []string{fmt.Sprintf("Between %s and %s %s gained no stars.", spellDay(from), spellDay(to), name)}
+	}
+	lines := []string{
+		fmt.Sprintf("Between %s and %s %s gained %s, peaking at %d on %s.",
+			spellDay(from), spellDay(to), name, starsOf(gained), peak, spellDay(peakDay)),
+		fmt.Sprintf("That's %.1f stars a day on average, with new stars on %d of the %d days.",
+			float64(gained)/float64(len(days)), active, len(days)),
+	}
+	if len(order) < 2 || months <= 0 {
+		return lines
+	}
+	if len(order) > months {
+		lines = append(lines, fmt.Sprintf("Stars of the last %d months:", months))
+		order = order[len(order)-months:]
+	} else {
+		lines = append(lines, "Stars per month:")
+	}
+	for _, month := range order {
+		t, err := time.Parse("2006-01", month)
+		if err != nil {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s.", t.Format("January 2006"), starsOf(monthly[month])))
+	}
+	return lines
+}
+
+// narration returns the narration replacing the graph with --a11y, fitting
+// in height lines.
+func (r *Repo) narration(keys []string, height int) string {
+	from, to := keys[0], keys[len(keys)-1]
+	if r.graph.Windowed() {
+		from, to = r.graph.From, r.graph.To
+	}
+	lines := []string{fmt.Sprintf("%s has %s, counted per day in %s.", r.name, starsOf(r.stars), timeZone)}
+	// Leave room for the overall sentences and the months heading.
+	lines = append(lines, Narrate(r.name, r.stargazers, from, to, height-4)...)
+	return " " + strings.Join(lines, "\n ")
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// notableSampleSize is the number of most recent stargazers whose follower
+// counts are fetched.
+const notableSampleSize = 500
+
+// NotableMsg holds the accounts of the sampled stargazers with their
+// starred dates.
+type NotableMsg struct {
+	Accounts  []Account
+	StarredAt []time.Time
+	Err       error
+}
+
+// notable lists the most followed stargazers.
+type notable struct {
+	loading bool
+	loaded  bool
+	sampled int
+	err     error
+	table   table.Model
+}
+
+func newNotable(theme Theme) notable {
+	return notable{
+		table: table.New(
+			table.WithColumns(
+				[]table.Column{
+					{Title: "Stargazer", Width: 20},
+					{Title: "Followers", Width: 10},
+					{Title: "Starred", Width: 10},
+				},
+			),
+			table.WithFocused(true),
+			table.WithStyles(theme.TableStyles()),
+		),
+	}
+}
+
+// SetSize sets the size of the stargazers table.
+func (n *notable) SetSize(width, height int) {
+	n.table.SetWidth(width)
+	// Leave room for the sample note.
+	n.table.SetHeight(height - 2)
+}
+
+// Load fetches the follower counts of the most recent stargazers unless they
+// were already fetched.
+func (n *notable) Load(r *Repo) tea.Cmd {
+	if n.loading || n.loaded || len(r.logins) == 0 {
+		return nil
+	}
+	if r.offline {
+		n.err = errOffline
+		return nil
+	}
+	n.loading = true
+	logins, starredAt := r.logins, r.starredAt
+	if len(logins) > notableSampleSize {
+		logins = logins[len(logins)-notableSampleSize:]
+		if len(starredAt) > notableSampleSize {
+			starredAt = starredAt[len(starredAt)-notableSampleSize:]
+		}
+	}
+	client := r.client
+	return func() tea.Msg {
+		accounts, err := FetchAccounts(client, logins)
+		return NotableMsg{Accounts: accounts, StarredAt: starredAt, Err: err}
+	}
+}
+
+// SetAccounts fills the table with the stargazers sorted by followers.
+func (n *notable) SetAccounts(msg NotableMsg) {
+	n.loading = false
+	n.loaded = true
+	n.sampled = len(msg.Accounts)
+	n.err = msg.Err
+	order := make([]int, len(msg.Accounts))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return msg.Accounts[order[i]].Followers > msg.Accounts[order[j]].Followers
+	})
+	rows := make([]table.Row, len(order))
+	for k, i := range order {
+		// Histories cached before starred dates were stored don't have them.
+		starred := "-"
+		if len(msg.StarredAt) == len(msg.Accounts) {
+			starred = dayOf(msg.StarredAt[i])
+		}
+		a := msg.Accounts[i]
+		rows[k] = table.Row{a.Login, fmt.Sprintf("%d", a.Followers), starred}
+	}
+	n.table.SetRows(rows)
+}
+
+func (n *notable) View(r *Repo) string {
+	switch {
+	case n.loading:
+		return fmt.Sprintf("\n %s loading followers...\n", r.spinner.View())
+	case n.err != nil:
+		return fmt.Sprintf("\n Error: %s", n.err)
+	case len(r.logins) == 0:
+		return "\n No stargazers found.\n"
+	}
+	note := fmt.Sprintf(" Most followed of the %d most recent stargazers", n.sampled)
+	return n.table.View() + "\n" + r.theme.AccentStyle().Render(note)
+}
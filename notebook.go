@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+)
+
+// notebookCell is a cell of a Jupyter notebook. Code cells have an
+// execution count and outputs, markdown cells must not.
+type notebookCell map[string]interface{}
+
+func markdownCell(lines ...string) notebookCell {
+	return notebookCell{
+		"cell_type": "markdown",
+		"metadata":  map[string]interface{}{},
+		"source":    notebookSource(lines),
+	}
+}
+
+func codeCell(lines ...string) notebookCell {
+	return notebookCell{
+		"cell_type":       "code",
+		"metadata":        map[string]interface{}{},
+		"source":          notebookSource(lines),
+		"execution_count": nil,
+		"outputs":         []interface{}{},
+	}
+}
+
+// notebookSource terminates every line but the last one, as Jupyter stores
+// them.
+func notebookSource(lines []string) []string {
+	source := make([]string, len(lines))
+	for i, line := range lines {
+		if i < len(lines)-1 {
+			line += "\n"
+		}
+		source[i] = line
+	}
+	return source
+}
+
+// ExportNotebook writes a Jupyter notebook with the daily stargazers of
+// entry inlined and pandas and matplotlib cells to explore them.
+func ExportNotebook(w io.Writer, entry *stars.History) error {
+	days := make([]string, 0, len(entry.Stargazers))
+	for day := range entry.Stargazers {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	data := make([]string, len(days))
+	for i, day := range days {
+		data[i] = fmt.Sprintf("    %q: %d,", day, entry.Stargazers[day])
+	}
+	cells := []notebookCell{
+		markdownCell(
+			fmt.Sprintf("# %s stargazers", entry.Name),
+			"",
+			fmt.Sprintf("Exported by gh-stars with %d stars.", entry.Stars),
+		),
+		codeCell(
+			"import pandas as pd",
+			"import matplotlib.pyplot as plt",
+		),
+		codeCell(append(append([]string{"daily = {"}, data...), "}")...),
+		codeCell(
+			"stars = pd.Series(daily, dtype=int)",
+			"stars.index = pd.to_datetime(stars.index)",
+			"stars = stars.asfreq(\"D\", fill_value=0)",
+			"stars.describe()",
+		),
+		markdownCell("## Stargazers over time"),
+		codeCell(
+			"ax = stars.cumsum().plot(figsize=(12, 4), title=\"Total stars\")",
+			"ax.set_ylabel(\"stars\")",
+			"plt.show()",
+		),
+		markdownCell("## Weekly and monthly gains"),
+		codeCell(
+			"fig, axes = plt.subplots(1, 2, figsize=(12, 4))",
+			"stars.resample(\"W\").sum().plot(ax=axes[0], title=\"Stars per week\")",
+			"stars.resample(\"M\").sum().plot.bar(ax=axes[1], title=\"Stars per month\")",
+			"axes[1].set_xticklabels([d.strftime(\"%Y-%m\") for d in stars.resample(\"M\").sum().index], rotation=90)",
+			"plt.tight_layout()",
+			"plt.show()",
+		),
+		markdownCell("## Top days"),
+		codeCell("stars.sort_values(ascending=False).head(10)"),
+	}
+	notebook := map[string]interface{}{
+		"cells": cells,
+		"metadata": map[string]interface{}{
+			"kernelspec": map[string]string{
+				"display_name": "Python 3",
+				"language":     "python",
+				"name":         "python3",
+			},
+			"language_info": map[string]string{"name": "python"},
+		},
+		"nbformat":       4,
+		"nbformat_minor": 4,
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", " ")
+	return enc.Encode(notebook)
+}
+
+// isNotebook returns whether path is a Jupyter notebook.
+func isNotebook(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".ipynb")
+}
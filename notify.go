@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/spf13/pflag"
+)
+
+var webhookClient = &http.Client{Timeout: 10 * time.Second}
+
+// Milestones returns the multiples of threshold crossed going from before
+// to after stars, in ascending order.
+func Milestones(before, after, threshold int) []int {
+	if threshold <= 0 || after <= before {
+		return nil
+	}
+	milestones := make([]int, 0)
+	for m := (before/threshold + 1) * threshold; m <= after; m += threshold {
+		milestones = append(milestones, m)
+	}
+	return milestones
+}
+
+// NewSpikes returns the spikes of the days since since. A positive
+// threshold flags the days with at least that many stars instead of the
+// days well above the trailing average.
+func NewSpikes(daily stars.Timeline, since time.Time, threshold int) []Spike {
+	from := since.In(timeZone).Format("2006-01-02")
+	spikes := make([]Spike, 0)
+	if threshold > 0 {
+		for _, day := range daily.Keys() {
+			if day >= from && daily[day] >= threshold {
+				spikes = append(spikes, Spike{Date: day, Count: daily[day]})
+			}
+		}
+		return spikes
+	}
+	for _, s := range DetectSpikes(daily) {
+		if s.Date >= from {
+			spikes = append(spikes, s)
+		}
+	}
+	return spikes
+}
+
+// Notifications returns the messages announcing the milestones crossed, the
+// spikes, and the unstar waves since the previous history of a repository.
+func Notifications(previous, current *stars.History, threshold, spike int) []string {
+	messages := make([]string, 0)
+	for _, m := range Milestones(previous.Stars, current.Stars, threshold) {
+		messages = append(messages, fmt.Sprintf(":star: %s reached %d stars", current.Name, m))
+	}
+	for _, s := range NewSpikes(current.Stargazers, previous.UpdatedAt, spike) {
+		msg := fmt.Sprintf(":chart_with_upwards_trend: %s gained %d stars on %s", current.Name, s.Count, s.Date)
+		if s.Average > 0 {
+			msg += fmt.Sprintf(", %.1fx the daily average", float64(s.Count)/s.Average)
+		}
+		messages = append(messages, msg)
+	}
+	// A wave is announced again only when more stargazers are lost on its
+	// day.
+	for _, day := range unstarAlerts(current.Unstars, current.UpdatedAt) {
+		if current.Unstars[day] > previous.Unstars[day] {
+			messages = append(messages, fmt.Sprintf(":chart_with_downwards_trend: %s lost %d stargazers on %s", current.Name, current.Unstars[day], day))
+		}
+	}
+	return messages
+}
+
+// PostSlack sends a message to a Slack incoming webhook.
+func PostSlack(webhook, text string) error {
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+	resp, err := webhookClient.Post(webhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("slack webhook: %s", resp.Status)
+	}
+	return nil
+}
+
+func runNotify(args []string) {
+	flags := pflag.NewFlagSet("notify", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gh stars notify <repository> [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Fetch the stars of a repository and announce the milestones crossed, the\n")
+		fmt.Fprintf(os.Stderr, "spikes, and the unstar waves since the cached history, e.g. from cron.\n\n")
+		flags.PrintDefaults()
+	}
+	threshold := flags.Int("threshold", 1000, "announce every multiple of this many stars")
+	spike := flags.Int("spike", 0, "announce days with at least this many stars, 0 for days well above the trailing average")
+	webhook := flags.String("slack-webhook", os.Getenv("GH_STARS_SLACK_WEBHOOK"), "Slack incoming webhook URL, $GH_STARS_SLACK_WEBHOOK by default, or print the notifications")
+	tz := flags.String("tz", "UTC", "time zone of the days stargazers are counted in: UTC, local, or an IANA name")
+	addTokenFlag(flags)
+	addMaxRequestsFlag(flags)
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(1)
+	}
+	var err error
+	timeZone, err = ParseTimeZone(*tz)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	name := flags.Arg(0)
+	previous, err := LoadCache(name)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	client, err := NewClient()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	current, err := FetchHistory(client, name)
+	if err != nil {
+		log.Fatalln(classifyError(name, err))
+	}
+	if previous == nil {
+		// Without a previous value every milestone would be announced.
+		fmt.Fprintf(os.Stderr, "%s has %d stars, notifications start with the next run\n", current.Name, current.Stars)
+		return
+	}
+	for _, msg := range Notifications(previous, current, *threshold, *spike) {
+		if *webhook == "" {
+			fmt.Println(msg)
+			continue
+		}
+		if err := PostSlack(*webhook, msg); err != nil {
+			log.Fatalln(err)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/go-gh/pkg/api"
+)
+
+// StaleMsg holds the cached history of a repository shown when it can't be
+// fetched.
+type StaleMsg struct {
+	Entry *stars.History
+	// Sources are the stars of the repository and of its aliases.
+	Sources map[string]int
+}
+
+// isNetworkError returns whether err is a connection error rather than an
+// error response of the API.
+func isNetworkError(err error) bool {
+	var httpErr api.HTTPError
+	return err != nil && !errors.As(err, &httpErr)
+}
+
+// loadStale returns the cached history of a repository or an error if it
+// isn't cached.
+func loadStale(name string) (*stars.History, error) {
+	entry, err := LoadCache(name)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("no cached data for %s", name)
+	}
+	return entry, nil
+}
+
+// fallback returns the cached history of the repository when the network is
+// unavailable, otherwise the error.
+func (r *Repo) fallback(err error) tea.Msg {
+	if !isNetworkError(err) {
+		return ErrorMsg(classifyError(r.name, err))
+	}
+	entry, cacheErr := loadStale(r.name)
+	if cacheErr != nil {
+		log.Printf("falling back to the cache: %v", cacheErr)
+		return ErrorMsg(err)
+	}
+	entry, sources, err := mergeAliases(r.client, entry, r.aliases, true)
+	if err != nil {
+		return ErrorMsg(err)
+	}
+	return StaleMsg{Entry: entry, Sources: sources}
+}
+
+// setStale shows the cached history of the repository.
+func (r *Repo) setStale(entry *stars.History) {
+	if !strings.EqualFold(entry.Name, r.name) && containsFold(entry.Aliases, r.name) {
+		r.renamed = r.name
+	}
+	r.name = entry.Name
+	r.stars = entry.Stars
+	r.setStargazers(entry.Stargazers)
+	r.logins = entry.Logins
+	r.starredAt = entry.StarredAt
+	r.unstars = entry.Unstars
+	r.state = stateReady
+	r.offline = true
+	r.stale = entry.UpdatedAt
+	// Make room for the banner once the size is known.
+	if r.termHeight > 0 {
+		r.resize(r.width, r.termHeight)
+	}
+}
+
+func (r *Repo) staleBanner() string {
+	return r.theme.WarningStyle().Render(fmt.Sprintf(" stale data from %s", r.stale.Local().Format("2006-01-02 15:04")))
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+)
+
+// ExportOpenMetrics writes the total and daily stargazers of each day with
+// new stargazers in the OpenMetrics text format, timestamped at the end of
+// the day, so they can be backfilled into Prometheus with promtool tsdb
+// create-blocks-from openmetrics.
+func ExportOpenMetrics(w io.Writer, entries ...*stars.History) error {
+	bw := bufio.NewWriter(w)
+	now := time.Now()
+	fmt.Fprintln(bw, "# TYPE github_repo_stars counter")
+	fmt.Fprintln(bw, "# HELP github_repo_stars Stargazers of the repository.")
+	for _, entry := range entries {
+		var total int
+		err := eachDay(entry.Stargazers, now, func(day string, end time.Time) {
+			total += entry.Stargazers[day]
+			fmt.Fprintf(bw, "github_repo_stars_total{repo=%q} %d %d\n", entry.Name, total, end.Unix())
+		})
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(bw, "# TYPE github_repo_daily_stars gauge")
+	fmt.Fprintln(bw, "# HELP github_repo_daily_stars Stargazers gained during the day.")
+	for _, entry := range entries {
+		err := eachDay(entry.Stargazers, now, func(day string, end time.Time) {
+			fmt.Fprintf(bw, "github_repo_daily_stars{repo=%q} %d %d\n", entry.Name, entry.Stargazers[day], end.Unix())
+		})
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(bw, "# EOF")
+	return bw.Flush()
+}
+
+// eachDay calls fn with each day of daily in chronological order and the end
+// of the day in timeZone, or now for the current day.
+func eachDay(daily stars.Timeline, now time.Time, fn func(day string, end time.Time)) error {
+	days := make([]string, 0, len(daily))
+	for day := range daily {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	for _, day := range days {
+		t, err := time.ParseInLocation("2006-01-02", day, timeZone)
+		if err != nil {
+			return err
+		}
+		end := t.AddDate(0, 0, 1)
+		if end.After(now) {
+			end = now
+		}
+		fn(day, end)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+)
+
+func TestExportOpenMetrics(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []*stars.History
+		zone    *time.Location
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "no repositories",
+			want: "# TYPE github_repo_stars counter\n" +
+				"# HELP github_repo_stars Stargazers of the repository.\n" +
+				"# TYPE github_repo_daily_stars gauge\n" +
+				"# HELP github_repo_daily_stars Stargazers gained during the day.\n" +
+				"# EOF\n",
+		},
+		{
+			name: "totals and daily stars at the end of each day",
+			entries: []*stars.History{
+				{Name: "owner/repo", Stargazers: stars.Timeline{"2024-01-03": 2, "2024-01-01": 1}},
+			},
+			want: "# TYPE github_repo_stars counter\n" +
+				"# HELP github_repo_stars Stargazers of the repository.\n" +
+				"github_repo_stars_total{repo=\"owner/repo\"} 1 1704153600\n" +
+				"github_repo_stars_total{repo=\"owner/repo\"} 3 1704326400\n" +
+				"# TYPE github_repo_daily_stars gauge\n" +
+				"# HELP github_repo_daily_stars Stargazers gained during the day.\n" +
+				"github_repo_daily_stars{repo=\"owner/repo\"} 1 1704153600\n" +
+				"github_repo_daily_stars{repo=\"owner/repo\"} 2 1704326400\n" +
+				"# EOF\n",
+		},
+		{
+			name: "days of the time zone",
+			entries: []*stars.History{
+				{Name: "owner/repo", Stargazers: stars.Timeline{"2024-01-01": 1}},
+			},
+			zone: time.FixedZone("UTC+2", 2*60*60),
+			want: "# TYPE github_repo_stars counter\n" +
+				"# HELP github_repo_stars Stargazers of the repository.\n" +
+				"github_repo_stars_total{repo=\"owner/repo\"} 1 1704146400\n" +
+				"# TYPE github_repo_daily_stars gauge\n" +
+				"# HELP github_repo_daily_stars Stargazers gained during the day.\n" +
+				"github_repo_daily_stars{repo=\"owner/repo\"} 1 1704146400\n" +
+				"# EOF\n",
+		},
+		{
+			name:    "invalid day",
+			entries: []*stars.History{{Name: "owner/repo", Stargazers: stars.Timeline{"yesterday": 1}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.zone != nil {
+				defer func(zone *time.Location) { timeZone = zone }(timeZone)
+				timeZone = tt.zone
+			}
+			var b bytes.Buffer
+			err := ExportOpenMetrics(&b, tt.entries...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExportOpenMetrics() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && b.String() != tt.want {
+				t.Errorf("ExportOpenMetrics() =\n%s\nwant\n%s", b.String(), tt.want)
+			}
+		})
+	}
+}
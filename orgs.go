@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	userOrgsPath = "users/%s/orgs"
+	// orgsSampleSize is the number of most recent stargazers whose public
+	// organizations are fetched.
+	orgsSampleSize = 200
+	// orgsConcurrency is the number of concurrent organization requests.
+	orgsConcurrency = 8
+)
+
+// OrgsMsg holds the number of sampled stargazers per organization.
+type OrgsMsg struct {
+	Counts  map[string]int
+	Sampled int
+	Err     error
+}
+
+type Org struct {
+	Login string `json:"login"`
+}
+
+// orgs shows the organizations most represented among stargazers.
+type orgs struct {
+	loading bool
+	loaded  bool
+	sampled int
+	err     error
+	table   table.Model
+}
+
+func newOrgs(theme Theme) orgs {
+	return orgs{
+		table: table.New(
+			table.WithColumns(
+				[]table.Column{
+					{Title: "Organization", Width: 30},
+					{Title: "Stargazers", Width: 10},
+				},
+			),
+			table.WithFocused(true),
+			table.WithStyles(theme.TableStyles()),
+		),
+	}
+}
+
+// SetSize sets the size of the organizations table.
+func (o *orgs) SetSize(width, height int) {
+	o.table.SetWidth(width)
+	// Leave room for the sample note.
+	o.table.SetHeight(height - 2)
+}
+
+// Load fetches the organizations of the most recent stargazers unless they
+// were already fetched.
+func (o *orgs) Load(r *Repo) tea.Cmd {
+	if o.loading || o.loaded || len(r.logins) == 0 {
+		return nil
+	}
+	if r.offline {
+		o.err = errOffline
+		return nil
+	}
+	o.loading = true
+	logins := r.logins
+	if len(logins) > orgsSampleSize {
+		logins = logins[len(logins)-orgsSampleSize:]
+	}
+	client := r.client
+	return func() tea.Msg {
+		var mu sync.Mutex
+		var errg errgroup.Group
+		errg.SetLimit(orgsConcurrency)
+		counts := make(map[string]int)
+		for _, login := range logins {
+			login := login
+			errg.Go(func() error {
+				p := stars.NewPaginator(client, fmt.Sprintf(userOrgsPath, login), stars.PerPage)
+				for {
+					result := make([]Org, 0)
+					ok, err := p.Next(&result)
+					if err != nil {
+						return fmt.Errorf("Error fetching organizations of %s: %w", login, err)
+					}
+					if !ok {
+						return nil
+					}
+					mu.Lock()
+					for _, org := range result {
+						counts[org.Login]++
+					}
+					mu.Unlock()
+				}
+			})
+		}
+		err := errg.Wait()
+		return OrgsMsg{Counts: counts, Sampled: len(logins), Err: err}
+	}
+}
+
+// SetCounts fills the table with organizations sorted by the number of
+// stargazers.
+func (o *orgs) SetCounts(msg OrgsMsg) {
+	o.loading = false
+	o.loaded = true
+	o.sampled = msg.Sampled
+	o.err = msg.Err
+	names := make([]string, 0, len(msg.Counts))
+	for name := range msg.Counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if msg.Counts[names[i]] != msg.Counts[names[j]] {
+			return msg.Counts[names[i]] > msg.Counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	rows := make([]table.Row, len(names))
+	for i, name := range names {
+		rows[i] = table.Row{name, fmt.Sprintf("%d", msg.Counts[name])}
+	}
+	o.table.SetRows(rows)
+}
+
+func (o *orgs) View(r *Repo) string {
+	switch {
+	case o.loading:
+		return fmt.Sprintf("\n %s loading organizations...\n", r.spinner.View())
+	case o.err != nil:
+		return fmt.Sprintf("\n Error: %s", o.err)
+	case len(r.logins) == 0:
+		return "\n No stargazers found.\n"
+	case len(o.table.Rows()) == 0:
+		return fmt.Sprintf("\n None of the %d most recent stargazers belong to a public organization.\n", o.sampled)
+	}
+	note := fmt.Sprintf(" Public organizations of the %d most recent stargazers", o.sampled)
+	return o.table.View() + "\n" + r.theme.AccentStyle().Render(note)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// patternsHourHeight is the height of the bars of the stars by hour.
+const patternsHourHeight = 8
+
+// weekdays are the labels of the stars by weekday, Monday first.
+var weekdays = []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+// starPatterns counts the stargazers per weekday, Monday first, and per hour
+// of the day in timeZone.
+func starPatterns(starredAt []time.Time) ([]int, []int) {
+	days, hours := make([]int, 7), make([]int, 24)
+	for _, t := range starredAt {
+		t = t.In(timeZone)
+		days[(int(t.Weekday())+6)%7]++
+		hours[t.Hour()]++
+	}
+	return days, hours
+}
+
+// barChart renders a horizontal bar per label scaled to the largest count,
+// followed by the count and its share of the total.
+func barChart(theme Theme, labels []string, counts []int, width int) []string {
+	max, total := 0, 0
+	labelWidth := 0
+	for i, n := range counts {
+		total += n
+		if n > max {
+			max = n
+		}
+		if len(labels[i]) > labelWidth {
+			labelWidth = len(labels[i])
+		}
+	}
+	// Leave room for the labels, counts, and percentages.
+	width -= labelWidth + 20
+	if width < 10 {
+		width = 10
+	}
+	lines := make([]string, len(labels))
+	for i, label := range labels {
+		filled, share := 0, 0.0
+		if max > 0 {
+			filled = width * counts[i] / max
+			share = float64(counts[i]) / float64(total) * 100
+		}
+		bar := theme.AccentStyle().Render(strings.Repeat("█", filled))
+		lines[i] = fmt.Sprintf(" %-*s %s %d (%.1f%%)", labelWidth, label, bar, counts[i], share)
+	}
+	return lines
+}
+
+// columnChart renders a vertical bar per count scaled to the largest one,
+// labeled with its index.
+func columnChart(theme Theme, counts []int, height int) []string {
+	max := 0
+	for _, n := range counts {
+		if n > max {
+			max = n
+		}
+	}
+	lines := make([]string, 0, height+1)
+	for row := height; row > 0; row-- {
+		var b strings.Builder
+		b.WriteString(" ")
+		for _, n := range counts {
+			if max > 0 && n*height >= row*max {
+				b.WriteString(theme.AccentStyle().Render("██") + " ")
+			} else {
+				b.WriteString("   ")
+			}
+		}
+		lines = append(lines, strings.TrimRight(b.String(), " "))
+	}
+	var axis strings.Builder
+	axis.WriteString(" ")
+	for i := range counts {
+		fmt.Fprintf(&axis, "%02d ", i)
+	}
+	return append(lines, strings.TrimRight(axis.String(), " "))
+}
+
+// patternsView shows the stars by weekday and by hour of the day, to time
+// announcements.
+func (r *Repo) patternsView() string {
+	switch {
+	case len(r.logins) == 0:
+		return "\n No stargazers found.\n"
+	// Histories cached before starred dates were stored don't have them.
+	case len(r.starredAt) == 0:
+		return "\n The starred dates of the stargazers aren't cached, fetch the repository again.\n"
+	}
+	days, hours := starPatterns(r.starredAt)
+	lines := []string{"", r.theme.AccentStyle().Render(fmt.Sprintf(" Stars by weekday (%s)", timeZone)), ""}
+	lines = append(lines, barChart(r.theme, weekdays, days, r.width)...)
+	lines = append(lines, "", r.theme.AccentStyle().Render(fmt.Sprintf(" Stars by hour of the day (%s)", timeZone)), "")
+	height := r.height - len(lines) - 2
+	if height > patternsHourHeight {
+		height = patternsHourHeight
+	}
+	if height < 1 {
+		height = 1
+	}
+	lines = append(lines, columnChart(r.theme, hours, height)...)
+	return strings.Join(lines, "\n")
+}
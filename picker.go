@@ -0,0 +1,248 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/go-gh/pkg/api"
+)
+
+const (
+	recentReposPath  = "user/repos?sort=pushed&per_page=%d"
+	starredReposPath = "user/starred?per_page=%d"
+	// pickerSourceSize is the number of repositories listed per source.
+	pickerSourceSize = 30
+	// pickerResults is the number of repositories shown at once.
+	pickerResults = 15
+)
+
+// pickerItem is a repository offered by the picker.
+type pickerItem struct {
+	name   string
+	source string
+}
+
+// pickerReposMsg holds the repositories of a source of the picker.
+type pickerReposMsg struct {
+	source string
+	repos  []string
+	err    error
+}
+
+// RepoPicker lets users pick a repository to view among their recently
+// pushed repositories, their starred ones, and the cached ones, with a
+// fuzzy search. Repo is the picked repository once done.
+type RepoPicker struct {
+	Repo string
+
+	input   textinput.Model
+	client  api.RESTClient
+	items   []pickerItem
+	results []pickerItem
+	loading int
+	cursor  int
+	err     error
+	opts    Options
+}
+
+// NewRepoPicker returns a picker of the cached repositories, and of the
+// recent and starred repositories of the authenticated user unless offline.
+func NewRepoPicker(client api.RESTClient, cached []string, opts Options) *RepoPicker {
+	input := textinput.New()
+	input.Placeholder = "owner/repo"
+	input.Focus()
+	p := &RepoPicker{
+		input:  input,
+		client: client,
+		opts:   opts,
+	}
+	p.add("cached", cached)
+	if !opts.Offline {
+		p.loading = 2
+	}
+	return p
+}
+
+// add appends the repositories of a source that aren't listed yet.
+func (p *RepoPicker) add(source string, repos []string) {
+	for _, name := range repos {
+		listed := false
+		for _, item := range p.items {
+			if strings.EqualFold(item.name, name) {
+				listed = true
+				break
+			}
+		}
+		if !listed {
+			p.items = append(p.items, pickerItem{name: name, source: source})
+		}
+	}
+	p.filter()
+}
+
+// filter lists the repositories matching the query.
+func (p *RepoPicker) filter() {
+	query := strings.TrimSpace(p.input.Value())
+	p.results = p.results[:0]
+	for _, item := range p.items {
+		if fuzzyMatch(item.name, query) {
+			p.results = append(p.results, item)
+		}
+	}
+	if p.cursor >= len(p.results) {
+		p.cursor = 0
+	}
+}
+
+// fuzzyMatch returns whether the characters of query appear in s in order,
+// ignoring case.
+func fuzzyMatch(s, query string) bool {
+	s, query = strings.ToLower(s), strings.ToLower(query)
+	for _, c := range query {
+		i := strings.IndexRune(s, c)
+		if i < 0 {
+			return false
+		}
+		s = s[i+1:]
+	}
+	return true
+}
+
+// fetchRepos fetches the repositories of a source of the authenticated
+// user.
+func (p *RepoPicker) fetchRepos(source string) tea.Cmd {
+	return func() tea.Msg {
+		var repos []string
+		var err error
+		switch source {
+		case "recent":
+			var result []struct {
+				FullName string `json:"full_name"`
+			}
+			err = p.client.Get(fmt.Sprintf(recentReposPath, pickerSourceSize), &result)
+			for _, r := range result {
+				repos = append(repos, r.FullName)
+			}
+		case "starred":
+			// The client asks for the starred dates, which wraps the
+			// repositories.
+			var result []struct {
+				Repo struct {
+					FullName string `json:"full_name"`
+				} `json:"repo"`
+			}
+			err = p.client.Get(fmt.Sprintf(starredReposPath, pickerSourceSize), &result)
+			for _, r := range result {
+				repos = append(repos, r.Repo.FullName)
+			}
+		}
+		return pickerReposMsg{source: source, repos: repos, err: err}
+	}
+}
+
+// pickRepo runs the repository picker and returns the picked repository,
+// or an empty string if none was.
+func pickRepo(ui *uiFlags, opts Options) (string, error) {
+	client, err := NewClient()
+	if err != nil {
+		return "", err
+	}
+	var cached []string
+	if cache, err := historyCache(); err == nil {
+		cached, _ = cache.Names()
+	}
+	p := NewRepoPicker(client, cached, opts)
+	ui.Run(p)
+	return p.Repo, nil
+}
+
+func (p *RepoPicker) Init() tea.Cmd {
+	if p.loading == 0 {
+		return textinput.Blink
+	}
+	return tea.Batch(textinput.Blink, p.fetchRepos("recent"), p.fetchRepos("starred"))
+}
+
+func (p *RepoPicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return p, tea.Quit
+		case "up", "ctrl+p":
+			if p.cursor > 0 {
+				p.cursor--
+			}
+			return p, nil
+		case "down", "ctrl+n":
+			if p.cursor < len(p.results)-1 {
+				p.cursor++
+			}
+			return p, nil
+		case "enter":
+			value := strings.TrimSpace(p.input.Value())
+			switch {
+			case len(p.results) > 0:
+				p.Repo = p.results[p.cursor].name
+			case strings.Count(value, "/") == 1:
+				p.Repo = value
+			default:
+				p.err = fmt.Errorf("no repository matches %q", value)
+				return p, nil
+			}
+			return p, tea.Quit
+		}
+		var cmd tea.Cmd
+		p.input, cmd = p.input.Update(msg)
+		p.err = nil
+		p.filter()
+		return p, cmd
+	case pickerReposMsg:
+		p.loading--
+		if msg.err != nil {
+			p.err = msg.err
+		}
+		p.add(msg.source, msg.repos)
+	default:
+		var cmd tea.Cmd
+		p.input, cmd = p.input.Update(msg)
+		return p, cmd
+	}
+	return p, nil
+}
+
+func (p *RepoPicker) View() string {
+	accent := p.opts.Theme.AccentStyle()
+	lines := []string{"", " Repository to view", "", " " + p.input.View(), ""}
+	// Scroll the results to keep the cursor visible.
+	start := 0
+	if p.cursor >= pickerResults {
+		start = p.cursor - pickerResults + 1
+	}
+	for i := start; i < len(p.results) && i < start+pickerResults; i++ {
+		item := p.results[i]
+		line := fmt.Sprintf("%-50s %s", item.name, item.source)
+		if i == p.cursor {
+			lines = append(lines, accent.Render(" > "+line))
+			continue
+		}
+		lines = append(lines, "   "+line)
+	}
+	if len(p.results) == 0 && p.loading == 0 {
+		lines = append(lines, "   No repositories, type one as owner/repo.")
+	}
+	if p.loading > 0 {
+		lines = append(lines, "", " Loading your repositories...")
+	}
+	if p.err != nil {
+		lines = append(lines, "", fmt.Sprintf(" Error: %s", p.err))
+	}
+	lines = append(lines, "", " type to search • ↑/↓ select • enter view • esc quit")
+	v := strings.Join(lines, "\n")
+	if p.opts.ASCII {
+		v = asciiReplacer.Replace(v)
+	}
+	return v
+}
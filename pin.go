@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/starsui"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// pinDays is the length of the pinned window.
+	pinDays = 30
+	// pinTableWidth is the width of the table of daily deltas.
+	pinTableWidth = 44
+)
+
+// pinned compares the last pinDays days with the preceding ones.
+type pinned struct {
+	active bool
+	end    time.Time
+	table  table.Model
+}
+
+func newPinned(theme Theme) pinned {
+	return pinned{
+		table: table.New(
+			table.WithColumns([]table.Column{
+				{Title: "Day", Width: 4},
+				{Title: "Date", Width: 10},
+				{Title: "Stars", Width: 6},
+				{Title: "Before", Width: 6},
+				{Title: "Delta", Width: 6},
+			}),
+			table.WithFocused(true),
+			table.WithStyles(theme.TableStyles()),
+		),
+	}
+}
+
+// Active returns whether the pinned window is shown.
+func (p *pinned) Active() bool {
+	return p.active
+}
+
+// Toggle pins the window ending today, or unpins it.
+func (p *pinned) Toggle(r *Repo) {
+	p.active = !p.active
+	if !p.active {
+		return
+	}
+	p.end = time.Now()
+	current, previous := p.windows(r)
+	rows := make([]table.Row, pinDays)
+	for i := range rows {
+		rows[i] = table.Row{
+			fmt.Sprintf("%d", i+1),
+			p.end.AddDate(0, 0, i-pinDays+1).Format("2006-01-02"),
+			fmt.Sprintf("%.0f", current[i]),
+			fmt.Sprintf("%.0f", previous[i]),
+			fmt.Sprintf("%+.0f", current[i]-previous[i]),
+		}
+	}
+	p.table.SetRows(rows)
+}
+
+// Stop unpins the window.
+func (p *pinned) Stop() {
+	p.active = false
+}
+
+// windows returns the daily stargazers of the pinned window and of the
+// preceding one.
+func (p *pinned) windows(r *Repo) ([]float64, []float64) {
+	both := recentDaily(r.stargazers, p.end, 2*pinDays)
+	return both[pinDays:], both[:pinDays]
+}
+
+func (p *pinned) View(r *Repo) string {
+	current, previous := p.windows(r)
+	var sumCurrent, sumPrevious float64
+	for i := range current {
+		sumCurrent += current[i]
+		sumPrevious += previous[i]
+	}
+	change := "n/a"
+	if sumPrevious > 0 {
+		change = fmt.Sprintf("%+.1f%%", 100*(sumCurrent-sumPrevious)/sumPrevious)
+	}
+	summary := fmt.Sprintf(" Last %d days +%.0f vs. +%.0f the %d days before, %s",
+		pinDays, sumCurrent, sumPrevious, pinDays, change)
+	labels := make([]string, pinDays)
+	for i := range labels {
+		labels[i] = fmt.Sprintf("day %d", i+1)
+	}
+	lower := 0.0
+	graph := starsui.AsciigraphRenderer{}.Render(
+		[]starsui.Series{
+			{Name: "current", Labels: labels, Values: current},
+			{Name: "previous", Labels: labels, Values: previous},
+		},
+		starsui.RenderOptions{
+			Width:      r.graphWidth() - pinTableWidth - 1,
+			Height:     r.height - 2,
+			Caption:    fmt.Sprintf("%s daily stars, pinned on %s", r.name, p.end.Format("2006-01-02")),
+			Colors:     r.theme.Colors(),
+			LowerBound: &lower,
+		},
+	)
+	graph += "\n " + r.theme.Legend([]string{
+		fmt.Sprintf("last %d days", pinDays),
+		fmt.Sprintf("previous %d days", pinDays),
+	})
+	return lipgloss.JoinVertical(
+		lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, graph, " ", p.table.View()),
+		r.theme.AccentStyle().Render(summary),
+	)
+}
@@ -0,0 +1,351 @@
+package stars
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// History is the stargazers history of a repository.
+type History struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+	Stars   int      `json:"stars"`
+	// Stargazers are the number of stargazers per day.
+	Stargazers Timeline `json:"stargazers"`
+	Logins     []string `json:"logins,omitempty"`
+	// StarredAt are the starred dates of Logins.
+	StarredAt []time.Time `json:"starred_at,omitempty"`
+	// Unstars are the number of stargazers lost per day.
+	Unstars map[string]int `json:"unstars,omitempty"`
+	// Unstarred are the stargazers found missing by each fetch.
+	Unstarred []Unstar  `json:"unstarred,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Unstar is a stargazer missing from a fetch, who unstarred the repository
+// between the previous fetch and this one.
+type Unstar struct {
+	Login  string    `json:"login"`
+	After  time.Time `json:"after"`
+	Before time.Time `json:"before"`
+}
+
+// NewHistory returns the history of a repository with stars stargazers
+// from its stargazers, counted per day by b.
+func NewHistory(name string, stars int, stargazers []Stargazer, b Bucketer) *History {
+	return &History{
+		Name:       name,
+		Stars:      stars,
+		Stargazers: NewTimeline(StarredAt(stargazers), b),
+		Logins:     Logins(stargazers),
+		StarredAt:  StarredAt(stargazers),
+		UpdatedAt:  time.Now(),
+	}
+}
+
+// Rebucket counts the stargazers per day of b again, so histories counted
+// in another time zone stay consistent. Histories without the starred date
+// of every stargazer are left untouched.
+func (h *History) Rebucket(b Bucketer) {
+	if len(h.StarredAt) == 0 || len(h.StarredAt) != h.Stargazers.Total() {
+		return
+	}
+	h.Stargazers = NewTimeline(h.StarredAt, b)
+}
+
+// merge merges src into h keeping the highest count of each day, the most
+// recent totals, and the stargazers and unstars of both. Merging a history
+// twice leaves h as merging it once.
+func (h *History) merge(src *History) {
+	for day, count := range src.Stargazers {
+		if count > h.Stargazers[day] {
+			h.Stargazers[day] = count
+		}
+	}
+	if src.UpdatedAt.After(h.UpdatedAt) || h.UpdatedAt.IsZero() {
+		h.Stars = src.Stars
+		h.UpdatedAt = src.UpdatedAt
+	}
+	h.mergeLogins(src)
+	for _, u := range src.Unstarred {
+		if !containsUnstar(h.Unstarred, u) {
+			h.Unstarred = append(h.Unstarred, u)
+		}
+	}
+}
+
+// mergeLogins adds the stargazers of src missing from h. When both have the
+// starred date of every stargazer, the logins stay in the order of their
+// dates, otherwise the logins and the dates are merged apart.
+func (h *History) mergeLogins(src *History) {
+	if len(src.Logins) == 0 && len(src.StarredAt) == 0 {
+		return
+	}
+	if len(h.Logins) != len(h.StarredAt) || len(src.Logins) != len(src.StarredAt) {
+		for _, login := range src.Logins {
+			if !containsFold(h.Logins, login) {
+				h.Logins = append(h.Logins, login)
+			}
+		}
+		h.StarredAt = mergeTimes(h.StarredAt, src.StarredAt)
+		return
+	}
+	type star struct {
+		login string
+		at    time.Time
+	}
+	seen := make(map[string]bool, len(h.Logins)+len(src.Logins))
+	merged := make([]star, 0, len(h.Logins)+len(src.Logins))
+	for _, from := range []*History{h, src} {
+		for i, login := range from.Logins {
+			if !seen[strings.ToLower(login)] {
+				seen[strings.ToLower(login)] = true
+				merged = append(merged, star{login, from.StarredAt[i]})
+			}
+		}
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].at.Before(merged[j].at)
+	})
+	h.Logins, h.StarredAt = make([]string, len(merged)), make([]time.Time, len(merged))
+	for i, s := range merged {
+		h.Logins[i], h.StarredAt[i] = s.login, s.at
+	}
+}
+
+// mergeTimes returns the times of a and the ones of b missing from a,
+// sorted.
+func mergeTimes(a, b []time.Time) []time.Time {
+	seen := make(map[int64]bool, len(a))
+	merged := make([]time.Time, 0, len(a)+len(b))
+	for _, t := range a {
+		seen[t.UnixNano()] = true
+		merged = append(merged, t)
+	}
+	for _, t := range b {
+		if !seen[t.UnixNano()] {
+			merged = append(merged, t)
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Before(merged[j])
+	})
+	return merged
+}
+
+// containsUnstar returns whether the same unstar is in list.
+func containsUnstar(list []Unstar, u Unstar) bool {
+	for _, v := range list {
+		if strings.EqualFold(v.Login, u.Login) && v.Before.Equal(u.Before) {
+			return true
+		}
+	}
+	return false
+}
+
+// Cache stores histories as JSON files in a directory.
+type Cache struct {
+	Dir string
+}
+
+// Path returns the file of the history of a repository. Repository names
+// are case-insensitive so the key is lowercased.
+func (c Cache) Path(name string) string {
+	return filepath.Join(c.Dir, strings.ToLower(name)+".json")
+}
+
+// Load reads the history of a repository. It returns nil if the repository
+// isn't cached.
+func (c Cache) Load(name string) (*History, error) {
+	data, err := os.ReadFile(c.Path(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var h History
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// Lookup reads the history of a repository like Load, or the history of
+// the repository it was renamed to, which lists its former names in
+// Aliases.
+func (c Cache) Lookup(name string) (*History, error) {
+	h, err := c.Load(name)
+	if err != nil || h != nil {
+		return h, err
+	}
+	names, err := c.Names()
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range names {
+		h, err := c.Load(n)
+		if err == nil && h != nil && containsFold(h.Aliases, name) {
+			return h, nil
+		}
+	}
+	return nil, nil
+}
+
+// Save writes the history of a repository.
+func (c Cache) Save(h *History) error {
+	return writeJSON(c.Path(h.Name), h)
+}
+
+// Remove removes the history of a repository and the pages of its
+// interrupted fetch.
+func (c Cache) Remove(name string) error {
+	if err := remove(c.Path(name)); err != nil {
+		return err
+	}
+	return c.Pages().Clear(name)
+}
+
+// Migrate moves the history of a renamed repository to its canonical name.
+// If both names are cached, the histories are merged and their unstars
+// added, as each was counted by the fetches of one name.
+func (c Cache) Migrate(from, to string) error {
+	if strings.EqualFold(from, to) {
+		return nil
+	}
+	old, err := c.Load(from)
+	if err != nil || old == nil {
+		return err
+	}
+	h, err := c.Load(to)
+	if err != nil {
+		return err
+	}
+	if h == nil {
+		h = &History{Stargazers: make(Timeline)}
+	}
+	h.Name = to
+	h.merge(old)
+	for day, lost := range old.Unstars {
+		if h.Unstars == nil {
+			h.Unstars = make(map[string]int)
+		}
+		h.Unstars[day] += lost
+	}
+	for _, alias := range append(old.Aliases, from) {
+		if !containsFold(h.Aliases, alias) {
+			h.Aliases = append(h.Aliases, alias)
+		}
+	}
+	if err := c.Save(h); err != nil {
+		return err
+	}
+	return os.Remove(c.Path(from))
+}
+
+// Merge merges an imported history into the cached history of its
+// repository.
+func (c Cache) Merge(imported *History) (*History, error) {
+	h, err := c.Load(imported.Name)
+	if err != nil {
+		return nil, err
+	}
+	if h == nil {
+		h = &History{Name: imported.Name, Stargazers: make(Timeline)}
+	}
+	h.merge(imported)
+	return h, c.Save(h)
+}
+
+// Names returns the lowercased names of the cached repositories.
+func (c Cache) Names() ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(c.Dir, "*", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(paths))
+	for _, path := range paths {
+		rel, err := filepath.Rel(c.Dir, path)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, strings.TrimSuffix(filepath.ToSlash(rel), ".json"))
+	}
+	return names, nil
+}
+
+// Pages returns the store of the pages of interrupted fetches, kept in the
+// partial subdirectory.
+func (c Cache) Pages() PageStore {
+	return pageStore{dir: filepath.Join(c.Dir, "partial")}
+}
+
+// pageStore stores the pages of each repository in a JSON file.
+type pageStore struct {
+	dir string
+}
+
+type partialFetch struct {
+	Pages map[int][]Stargazer `json:"pages"`
+}
+
+func (s pageStore) path(name string) string {
+	return filepath.Join(s.dir, strings.ToLower(name)+".json")
+}
+
+func (s pageStore) Load(name string) (map[int][]Stargazer, error) {
+	data, err := os.ReadFile(s.path(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var partial partialFetch
+	if err := json.Unmarshal(data, &partial); err != nil {
+		return nil, err
+	}
+	return partial.Pages, nil
+}
+
+func (s pageStore) Save(name string, pages map[int][]Stargazer) error {
+	return writeJSON(s.path(name), partialFetch{Pages: pages})
+}
+
+func (s pageStore) Clear(name string) error {
+	return remove(s.path(name))
+}
+
+func writeJSON(path string, v interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// remove removes a file, ignoring files that don't exist.
+func remove(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package stars
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCacheMigrate(t *testing.T) {
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	after := before.Add(24 * time.Hour)
+	day := func(d int) time.Time {
+		return time.Date(2024, 1, d, 12, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name   string
+		cached []*History
+		from   string
+		to     string
+		// want is the history cached under to, nil if there's none.
+		want *History
+		// kept tells whether the history of from is still cached.
+		kept bool
+	}{
+		{
+			name:   "same name in another case",
+			cached: []*History{{Name: "owner/repo", Stars: 1, Stargazers: Timeline{"2024-01-01": 1}}},
+			from:   "owner/repo",
+			to:     "Owner/Repo",
+			want:   &History{Name: "owner/repo", Stars: 1, Stargazers: Timeline{"2024-01-01": 1}},
+			kept:   true,
+		},
+		{
+			name: "nothing cached",
+			from: "old/repo",
+			to:   "new/repo",
+		},
+		{
+			name: "renamed",
+			cached: []*History{
+				{
+					Name: "old/repo", Aliases: []string{"older/repo"}, Stars: 2, Stargazers: Timeline{"2024-01-01": 2},
+					Logins: []string{"a", "b"}, StarredAt: []time.Time{day(1), day(1)},
+					Unstars: map[string]int{"2024-01-01": 1}, Unstarred: []Unstar{{Login: "c", After: before, Before: after}},
+					UpdatedAt: before,
+				},
+			},
+			from: "old/repo",
+			to:   "new/repo",
+			want: &History{
+				Name: "new/repo", Aliases: []string{"older/repo", "old/repo"}, Stars: 2, Stargazers: Timeline{"2024-01-01": 2},
+				Logins: []string{"a", "b"}, StarredAt: []time.Time{day(1), day(1)},
+				Unstars: map[string]int{"2024-01-01": 1}, Unstarred: []Unstar{{Login: "c", After: before, Before: after}},
+				UpdatedAt: before,
+			},
+		},
+		{
+			name: "stargazers and unstars of both names",
+			cached: []*History{
+				{
+					Name: "old/repo", Stars: 3, Stargazers: Timeline{"2024-01-01": 1, "2024-01-03": 2},
+					Logins: []string{"a", "c", "d"}, StarredAt: []time.Time{day(1), day(3), day(3)},
+					Unstars: map[string]int{"2024-01-02": 1, "2024-01-04": 2}, Unstarred: []Unstar{{Login: "x", Before: before}},
+					UpdatedAt: before,
+				},
+				{
+					Name: "new/repo", Stars: 3, Stargazers: Timeline{"2024-01-01": 1, "2024-01-02": 1},
+					Logins: []string{"A", "b"}, StarredAt: []time.Time{day(1), day(2)},
+					Unstars: map[string]int{"2024-01-04": 1}, Unstarred: []Unstar{{Login: "y", Before: after}, {Login: "X", Before: before}},
+					UpdatedAt: after,
+				},
+			},
+			from: "old/repo",
+			to:   "new/repo",
+			want: &History{
+				Name: "new/repo", Aliases: []string{"old/repo"}, Stars: 3, Stargazers: Timeline{"2024-01-01": 1, "2024-01-02": 1, "2024-01-03": 2},
+				Logins: []string{"A", "b", "c", "d"}, StarredAt: []time.Time{day(1), day(2), day(3), day(3)},
+				Unstars: map[string]int{"2024-01-02": 1, "2024-01-04": 3}, Unstarred: []Unstar{{Login: "y", Before: after}, {Login: "X", Before: before}},
+				UpdatedAt: after,
+			},
+		},
+		{
+			name: "starred dates missing for some stargazers",
+			cached: []*History{
+				{Name: "old/repo", Stargazers: Timeline{}, Logins: []string{"a", "b"}, StarredAt: []time.Time{day(2)}, UpdatedAt: before},
+				{Name: "new/repo", Stargazers: Timeline{}, Logins: []string{"b", "c"}, StarredAt: []time.Time{day(3), day(1)}, UpdatedAt: after},
+			},
+			from: "old/repo",
+			to:   "new/repo",
+			want: &History{
+				Name: "new/repo", Aliases: []string{"old/repo"}, Stargazers: Timeline{},
+				Logins: []string{"b", "c", "a"}, StarredAt: []time.Time{day(1), day(2), day(3)},
+				UpdatedAt: after,
+			},
+		},
+		{
+			name: "both names cached",
+			cached: []*History{
+				{Name: "old/repo", Stars: 3, Stargazers: Timeline{"2024-01-01": 2, "2024-01-02": 1}, UpdatedAt: before},
+				{Name: "new/repo", Aliases: []string{"Old/Repo"}, Stars: 4, Stargazers: Timeline{"2024-01-01": 1, "2024-01-03": 1}, UpdatedAt: after},
+			},
+			from: "old/repo",
+			to:   "new/repo",
+			want: &History{Name: "new/repo", Aliases: []string{"Old/Repo"}, Stars: 4, Stargazers: Timeline{"2024-01-01": 2, "2024-01-02": 1, "2024-01-03": 1}, UpdatedAt: after},
+		},
+		{
+			name: "the most recent totals win",
+			cached: []*History{
+				{Name: "old/repo", Stars: 5, Stargazers: Timeline{"2024-01-02": 5}, UpdatedAt: after},
+				{Name: "new/repo", Stars: 4, Stargazers: Timeline{"2024-01-01": 4}, UpdatedAt: before},
+			},
+			from: "old/repo",
+			to:   "new/repo",
+			want: &History{Name: "new/repo", Aliases: []string{"old/repo"}, Stars: 5, Stargazers: Timeline{"2024-01-01": 4, "2024-01-02": 5}, UpdatedAt: after},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Cache{Dir: t.TempDir()}
+			for _, h := range tt.cached {
+				if err := c.Save(h); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := c.Migrate(tt.from, tt.to); err != nil {
+				t.Fatalf("Migrate() error = %v", err)
+			}
+			got, err := c.Load(tt.to)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != nil {
+				got.UpdatedAt = got.UpdatedAt.UTC()
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Load(%q) = %+v, want %+v", tt.to, got, tt.want)
+			}
+			_, err = os.Stat(c.Path(tt.from))
+			if kept := err == nil; kept != tt.kept {
+				t.Errorf("history of %s kept = %v, want %v", tt.from, kept, tt.kept)
+			}
+		})
+	}
+}
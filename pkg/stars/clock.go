@@ -0,0 +1,67 @@
+package stars
+
+import (
+	"fmt"
+	"time"
+)
+
+// ClockSkew is how far after now a starred date can be without being an
+// anomaly, as the clocks of GitHub and of the machine may disagree.
+const ClockSkew = 5 * time.Minute
+
+// Anomalies are the starred dates fixed by Normalize.
+type Anomalies struct {
+	// Future is the number of starred dates after now, clamped to now.
+	Future int
+	// Latest is the furthest of them.
+	Latest time.Time
+}
+
+func (a Anomalies) String() string {
+	if a.Future == 1 {
+		return fmt.Sprintf("1 star dated in the future (%s) was counted now", a.Latest.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("%d stars dated in the future, up to %s, were counted now", a.Future, a.Latest.Format(time.RFC3339))
+}
+
+// Merge adds the anomalies of b to a.
+func (a *Anomalies) Merge(b Anomalies) {
+	a.Future += b.Future
+	if b.Latest.After(a.Latest) {
+		a.Latest = b.Latest
+	}
+}
+
+// normalizeTime returns t in UTC, clamped to now if it's after it, and
+// whether it's an anomaly, after now by more than ClockSkew.
+func normalizeTime(t, now time.Time) (time.Time, bool) {
+	t = t.UTC()
+	if !t.After(now) {
+		return t, false
+	}
+	return now.UTC(), t.Sub(now) > ClockSkew
+}
+
+// add records an anomalous starred date.
+func (a *Anomalies) add(t time.Time) {
+	a.Future++
+	if t.After(a.Latest) {
+		a.Latest = t.UTC()
+	}
+}
+
+// Normalize converts the starred dates of stargazers to UTC, so the offsets
+// of odd time zones don't leak into the days they are counted in, and
+// clamps the dates after now to now instead of counting them in future
+// days. It returns the dates further than ClockSkew in the future.
+func Normalize(stargazers []Stargazer, now time.Time) Anomalies {
+	var a Anomalies
+	for i, s := range stargazers {
+		t, anomaly := normalizeTime(s.StarredAt, now)
+		if anomaly {
+			a.add(s.StarredAt)
+		}
+		stargazers[i].StarredAt = t
+	}
+	return a
+}
@@ -0,0 +1,120 @@
+package stars
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalize(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tokyo := time.FixedZone("JST", 9*60*60)
+	tests := []struct {
+		name      string
+		starredAt []time.Time
+		want      []time.Time
+		anomalies Anomalies
+	}{
+		{
+			name:      "past dates are kept",
+			starredAt: []time.Time{now.Add(-time.Hour), now},
+			want:      []time.Time{now.Add(-time.Hour), now},
+		},
+		{
+			name:      "offsets are converted to UTC",
+			starredAt: []time.Time{time.Date(2024, 3, 1, 8, 0, 0, 0, tokyo)},
+			want:      []time.Time{time.Date(2024, 2, 29, 23, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:      "future dates within the clock skew are clamped silently",
+			starredAt: []time.Time{now.Add(ClockSkew)},
+			want:      []time.Time{now},
+		},
+		{
+			name:      "future dates beyond the clock skew are anomalies",
+			starredAt: []time.Time{now.Add(-time.Hour), now.Add(time.Hour), now.Add(24 * time.Hour)},
+			want:      []time.Time{now.Add(-time.Hour), now, now},
+			anomalies: Anomalies{Future: 2, Latest: now.Add(24 * time.Hour)},
+		},
+		{
+			name:      "the latest anomaly is in UTC",
+			starredAt: []time.Time{now.Add(time.Hour).In(tokyo)},
+			want:      []time.Time{now},
+			anomalies: Anomalies{Future: 1, Latest: now.Add(time.Hour)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stargazers := make([]Stargazer, len(tt.starredAt))
+			for i, at := range tt.starredAt {
+				stargazers[i].StarredAt = at
+			}
+			got := Normalize(stargazers, now)
+			if got.Future != tt.anomalies.Future || !got.Latest.Equal(tt.anomalies.Latest) {
+				t.Errorf("Normalize() = %+v, want %+v", got, tt.anomalies)
+			}
+			if got.Future > 0 && got.Latest.Location() != time.UTC {
+				t.Errorf("Latest is in %s, want UTC", got.Latest.Location())
+			}
+			for i, s := range stargazers {
+				if !s.StarredAt.Equal(tt.want[i]) || s.StarredAt.Location() != time.UTC {
+					t.Errorf("StarredAt[%d] = %s, want %s", i, s.StarredAt, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAnomaliesMerge(t *testing.T) {
+	early := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	late := early.Add(48 * time.Hour)
+	tests := []struct {
+		name string
+		a, b Anomalies
+		want Anomalies
+	}{
+		{
+			name: "none",
+		},
+		{
+			name: "into none",
+			b:    Anomalies{Future: 2, Latest: late},
+			want: Anomalies{Future: 2, Latest: late},
+		},
+		{
+			name: "keeps the latest",
+			a:    Anomalies{Future: 1, Latest: late},
+			b:    Anomalies{Future: 3, Latest: early},
+			want: Anomalies{Future: 4, Latest: late},
+		},
+		{
+			name: "takes a later one",
+			a:    Anomalies{Future: 1, Latest: early},
+			b:    Anomalies{Future: 1, Latest: late},
+			want: Anomalies{Future: 2, Latest: late},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.a.Merge(tt.b)
+			if tt.a != tt.want {
+				t.Errorf("Merge() = %+v, want %+v", tt.a, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnomaliesString(t *testing.T) {
+	latest := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		a    Anomalies
+		want string
+	}{
+		{Anomalies{Future: 1, Latest: latest}, "1 star dated in the future (2024-03-02T00:00:00Z) was counted now"},
+		{Anomalies{Future: 3, Latest: latest}, "3 stars dated in the future, up to 2024-03-02T00:00:00Z, were counted now"},
+	}
+	for _, tt := range tests {
+		if got := tt.a.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package stars
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/pkg/api"
+	"golang.org/x/sync/errgroup"
+)
+
+// ErrTooManyStargazers is returned when a repository has more stargazers
+// than GitHub lists.
+var ErrTooManyStargazers = errors.New("Too many pages to fetch")
+
+// PagesError is returned when some stargazers pages failed to be fetched,
+// with the stargazers of the other pages.
+type PagesError struct {
+	// Failed are the numbers of the pages that failed, in ascending order.
+	Failed []int
+	// Pages is the number of stargazers pages.
+	Pages int
+	// Err is the error of the first failed page.
+	Err error
+}
+
+func (e *PagesError) Error() string {
+	return fmt.Sprintf("%d of %d stargazers pages failed: %s", len(e.Failed), e.Pages, e.Err)
+}
+
+func (e *PagesError) Unwrap() error {
+	return e.Err
+}
+
+// PageStore keeps the complete stargazers pages of interrupted fetches.
+type PageStore interface {
+	// Load returns the pages kept for a repository keyed by page number.
+	Load(name string) (map[int][]Stargazer, error)
+	Save(name string, pages map[int][]Stargazer) error
+	Clear(name string) error
+}
+
+// Fetcher fetches repositories and their stargazers. The client must ask
+// for the starred dates with the application/vnd.github.v3.star+json media
+// type.
+type Fetcher struct {
+	Client api.RESTClient
+	// Pages resumes interrupted fetches when set. Its errors are ignored,
+	// resuming is a best effort.
+	Pages PageStore
+	// Progress is called with the number of stargazers pages fetched and
+	// their total after each page when set.
+	Progress func(done, total int)
+	// Warn is called with the anomalies of the starred dates fetched when
+	// set and there are any, once per page by Stream and Sample.
+	Warn func(Anomalies)
+}
+
+// normalize normalizes the starred dates of stargazers, warning about their
+// anomalies.
+func (f *Fetcher) normalize(stargazers []Stargazer) {
+	if a := Normalize(stargazers, time.Now()); a.Future > 0 && f.Warn != nil {
+		f.Warn(a)
+	}
+}
+
+// NewFetcher returns a fetcher using client.
+func NewFetcher(client api.RESTClient) *Fetcher {
+	return &Fetcher{Client: client}
+}
+
+// Repository fetches the metadata of a repository. The API follows renames
+// and transfers, so the returned name can differ from the requested one.
+func (f *Fetcher) Repository(name string) (Repository, error) {
+	var repo Repository
+	err := f.Client.Get(fmt.Sprintf(reposPath, name), &repo)
+	return repo, err
+}
+
+// StargazerPages returns the number of pages listing stars stargazers.
+func StargazerPages(stars int) int {
+	return (stars + PerPage - 1) / PerPage
+}
+
+// Stargazers fetches all the stargazers of a repository with stars
+// stargazers sorted by starred date. Pages are fetched concurrently. When
+// some pages fail, the stargazers of the others are returned with a
+// *PagesError.
+func (f *Fetcher) Stargazers(name string, stars int) ([]Stargazer, error) {
+	pages := StargazerPages(stars)
+	if pages == 0 {
+		return []Stargazer{}, nil
+	}
+	if pages > MaxStargazerPages {
+		return nil, ErrTooManyStargazers
+	}
+	kept := make(map[int][]Stargazer)
+	if f.Pages != nil {
+		if p, err := f.Pages.Load(name); err == nil && p != nil {
+			kept = p
+		}
+	}
+	p := NewPaginator(f.Client, fmt.Sprintf(stargazersPath, name), PerPage)
+	first := make([]Stargazer, 0)
+	if err := p.Page(1, &first); err != nil {
+		return nil, fmt.Errorf("Error fetching stargazers page 1: %w", err)
+	}
+	last := p.Last()
+	fetched := map[int][]Stargazer{1: first}
+	missing := make([]int, 0, last)
+	for page := 2; page <= last; page++ {
+		// Only full pages before the last one are stable.
+		if result, ok := kept[page]; ok && page < last && len(result) == PerPage {
+			fetched[page] = result
+			continue
+		}
+		missing = append(missing, page)
+	}
+	progress := func() {
+		if f.Progress != nil {
+			f.Progress(len(fetched), last)
+		}
+	}
+	progress()
+	var mu sync.Mutex
+	var errg errgroup.Group
+	// The other pages are still fetched when one fails, so a retry only
+	// needs the failed ones.
+	pagesErr := &PagesError{Pages: last}
+	for _, page := range missing {
+		page := page
+		errg.Go(func() error {
+			result := make([]Stargazer, 0)
+			err := p.Page(page, &result)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				pagesErr.Failed = append(pagesErr.Failed, page)
+				if pagesErr.Err == nil {
+					pagesErr.Err = fmt.Errorf("Error fetching stargazers page %d: %w", page, err)
+				}
+				return nil
+			}
+			fetched[page] = result
+			progress()
+			return nil
+		})
+	}
+	errg.Wait()
+	stargazers := make([]Stargazer, 0, stars)
+	for _, result := range fetched {
+		stargazers = append(stargazers, result...)
+	}
+	f.normalize(stargazers)
+	sort.Slice(stargazers, func(i, j int) bool {
+		return stargazers[i].StarredAt.Before(stargazers[j].StarredAt)
+	})
+	if len(pagesErr.Failed) > 0 {
+		sort.Ints(pagesErr.Failed)
+		if f.Pages != nil {
+			complete := make(map[int][]Stargazer)
+			for page, result := range fetched {
+				if page < last && len(result) == PerPage {
+					complete[page] = result
+				}
+			}
+			_ = f.Pages.Save(name, complete)
+		}
+		return stargazers, pagesErr
+	}
+	if f.Pages != nil {
+		_ = f.Pages.Clear(name)
+	}
+	return stargazers, nil
+}
+
+// Stream calls fn with each stargazer of a repository, oldest first, as the
+// pages are fetched.
+func (f *Fetcher) Stream(name string, fn func(Stargazer) error) error {
+	p := NewPaginator(f.Client, fmt.Sprintf(stargazersPath, name), PerPage)
+	for page := 1; ; page++ {
+		result := make([]Stargazer, 0)
+		ok, err := p.Next(&result)
+		if err != nil {
+			return fmt.Errorf("Error fetching stargazers page %d: %w", page, err)
+		}
+		if !ok {
+			return nil
+		}
+		f.normalize(result)
+		for _, s := range result {
+			if err := fn(s); err != nil {
+				return err
+			}
+		}
+	}
+}
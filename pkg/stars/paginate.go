@@ -0,0 +1,141 @@
+package stars
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/cli/go-gh/pkg/api"
+)
+
+var linkRe = regexp.MustCompile(`<([^>]+)>;\s*rel="([^"]+)"`)
+
+// parseLinks returns the URLs of the Link header keyed by relation.
+func parseLinks(header string) map[string]string {
+	links := make(map[string]string)
+	for _, m := range linkRe.FindAllStringSubmatch(header, -1) {
+		links[m[2]] = m[1]
+	}
+	return links
+}
+
+// pageNumber returns the page query parameter of a URL, or 0 if it has none.
+func pageNumber(u string) int {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(parsed.Query().Get("page"))
+	return n
+}
+
+// Paginator iterates over the pages of a list endpoint of the API, following
+// the Link headers of the responses.
+type Paginator struct {
+	client  api.RESTClient
+	path    string
+	perPage int
+	reverse bool
+	next    string
+	started bool
+
+	mu   sync.Mutex
+	last int
+}
+
+// NewPaginator returns a paginator of the list at path, which may already
+// have query parameters.
+func NewPaginator(client api.RESTClient, path string, perPage int) *Paginator {
+	return &Paginator{client: client, path: path, perPage: perPage}
+}
+
+// Reverse makes the paginator iterate from the last page to the first one.
+func (p *Paginator) Reverse() *Paginator {
+	p.reverse = true
+	return p
+}
+
+// Last returns the number of the last page, known once a page was fetched.
+func (p *Paginator) Last() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.last
+}
+
+// PagePath returns the path of a page.
+func (p *Paginator) PagePath(page int) string {
+	u, err := url.Parse(p.path)
+	if err != nil {
+		return p.path
+	}
+	q := u.Query()
+	q.Set("page", strconv.Itoa(page))
+	q.Set("per_page", strconv.Itoa(p.perPage))
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// Page fetches a page into v. Pages can be fetched concurrently.
+func (p *Paginator) Page(page int, v interface{}) error {
+	_, err := p.get(p.PagePath(page), v)
+	return err
+}
+
+// Next fetches the next page into v. It returns false once there are no
+// more pages, in which case v is left untouched.
+func (p *Paginator) Next(v interface{}) (bool, error) {
+	if !p.started {
+		p.started = true
+		if !p.reverse {
+			p.next = p.PagePath(1)
+		} else {
+			// The first page tells where the last page is.
+			links, err := p.get(p.PagePath(1), v)
+			if err != nil {
+				return false, err
+			}
+			if last, ok := links["last"]; ok {
+				p.next = last
+			} else {
+				return true, nil
+			}
+		}
+	}
+	if p.next == "" {
+		return false, nil
+	}
+	links, err := p.get(p.next, v)
+	if err != nil {
+		return false, err
+	}
+	if p.reverse {
+		p.next = links["prev"]
+	} else {
+		p.next = links["next"]
+	}
+	return true, nil
+}
+
+func (p *Paginator) get(path string, v interface{}) (map[string]string, error) {
+	resp, err := p.client.Request(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	links := parseLinks(resp.Header.Get("Link"))
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if last, ok := links["last"]; ok {
+		p.last = pageNumber(last)
+	} else if _, ok := links["next"]; !ok && p.last == 0 {
+		// Without a next page, this is the last one.
+		p.last = pageNumber(resp.Request.URL.String())
+		if p.last == 0 {
+			p.last = 1
+		}
+	}
+	return links, json.NewDecoder(resp.Body).Decode(v)
+}
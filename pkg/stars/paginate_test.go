@@ -0,0 +1,52 @@
+package stars
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLinks(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   map[string]string
+	}{
+		{
+			name: "no header",
+			want: map[string]string{},
+		},
+		{
+			name:   "next and last",
+			header: `<https://api.github.com/repositories/1/stargazers?per_page=100&page=2>; rel="next", <https://api.github.com/repositories/1/stargazers?per_page=100&page=40>; rel="last"`,
+			want: map[string]string{
+				"next": "https://api.github.com/repositories/1/stargazers?per_page=100&page=2",
+				"last": "https://api.github.com/repositories/1/stargazers?per_page=100&page=40",
+			},
+		},
+		{
+			name:   "last page",
+			header: `<https://api.github.com/repositories/1/stargazers?page=39>; rel="prev", <https://api.github.com/repositories/1/stargazers?page=1>; rel="first"`,
+			want: map[string]string{
+				"prev":  "https://api.github.com/repositories/1/stargazers?page=39",
+				"first": "https://api.github.com/repositories/1/stargazers?page=1",
+			},
+		},
+		{
+			name:   "no space after the semicolon",
+			header: `<https://api.github.com/user/starred?page=2>;rel="next"`,
+			want:   map[string]string{"next": "https://api.github.com/user/starred?page=2"},
+		},
+		{
+			name:   "malformed",
+			header: `https://api.github.com/user/starred?page=2; rel=next`,
+			want:   map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLinks(tt.header); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLinks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package stars
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// SamplePoint is the number of stargazers of a repository before a time.
+type SamplePoint struct {
+	Time  time.Time
+	Count int
+}
+
+// Sample fetches every nth page of the stargazers of a repository with
+// stars stargazers, and the last one, instead of all of them. It returns the
+// number of stargazers before each stargazer of the pages, oldest first,
+// ending with stars at the current time. GitHub lists MaxStargazerPages at
+// most, the stargazers after them are only known by their number.
+func (f *Fetcher) Sample(name string, stars, every int) ([]SamplePoint, error) {
+	if every < 1 {
+		every = 1
+	}
+	last := StargazerPages(stars)
+	if last > MaxStargazerPages {
+		last = MaxStargazerPages
+	}
+	pages := make([]int, 0, last/every+2)
+	for page := 1; page <= last; page += every {
+		pages = append(pages, page)
+	}
+	if n := len(pages); n > 0 && pages[n-1] != last {
+		pages = append(pages, last)
+	}
+	p := NewPaginator(f.Client, fmt.Sprintf(stargazersPath, name), PerPage)
+	var mu sync.Mutex
+	var errg errgroup.Group
+	points := make([]SamplePoint, 0, len(pages)*PerPage+1)
+	var done int
+	for _, page := range pages {
+		page := page
+		errg.Go(func() error {
+			result := make([]Stargazer, 0)
+			if err := p.Page(page, &result); err != nil {
+				return fmt.Errorf("Error fetching stargazers page %d: %w", page, err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			f.normalize(result)
+			for i, s := range result {
+				points = append(points, SamplePoint{Time: s.StarredAt, Count: (page-1)*PerPage + i})
+			}
+			done++
+			if f.Progress != nil {
+				f.Progress(done, len(pages))
+			}
+			return nil
+		})
+	}
+	if err := errg.Wait(); err != nil {
+		return nil, err
+	}
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Count < points[j].Count
+	})
+	return append(points, SamplePoint{Time: time.Now(), Count: stars}), nil
+}
+
+// Interpolate returns the number of stargazers per day of d from the
+// samples of their cumulative number, linearly interpolated between
+// samples. Samples must be in chronological order.
+func Interpolate(points []SamplePoint, d Daily) Timeline {
+	t := make(Timeline)
+	if len(points) == 0 {
+		return t
+	}
+	loc := d.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	// count returns the interpolated number of stargazers before at.
+	i := 0
+	count := func(at time.Time) float64 {
+		for i+1 < len(points) && !points[i+1].Time.After(at) {
+			i++
+		}
+		if i+1 == len(points) || at.Before(points[i].Time) {
+			return float64(points[i].Count)
+		}
+		a, b := points[i], points[i+1]
+		span := b.Time.Sub(a.Time)
+		if span <= 0 {
+			return float64(b.Count)
+		}
+		return float64(a.Count) + float64(b.Count-a.Count)*float64(at.Sub(a.Time))/float64(span)
+	}
+	first := points[0].Time.In(loc)
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc)
+	end := points[len(points)-1].Time
+	before := points[0].Count
+	for !day.After(end) {
+		next := day.AddDate(0, 0, 1)
+		total := int(math.Round(count(next)))
+		if next.After(end) {
+			total = points[len(points)-1].Count
+		}
+		if n := total - before; n > 0 {
+			t[d.Bucket(day)] = n
+		}
+		before = total
+		day = next
+	}
+	return t
+}
@@ -0,0 +1,64 @@
+package stars
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestInterpolate(t *testing.T) {
+	day := func(d, hour int) time.Time {
+		return time.Date(2024, 1, d, hour, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name   string
+		points []SamplePoint
+		daily  Daily
+		want   Timeline
+	}{
+		{
+			name: "no samples",
+			want: Timeline{},
+		},
+		{
+			name:   "a single day",
+			points: []SamplePoint{{day(1, 0), 0}, {day(1, 12), 10}},
+			want:   Timeline{"2024-01-01": 10},
+		},
+		{
+			name:   "spread linearly between samples",
+			points: []SamplePoint{{day(1, 0), 0}, {day(3, 0), 20}},
+			want:   Timeline{"2024-01-01": 10, "2024-01-02": 10},
+		},
+		{
+			name:   "flat between samples without stars",
+			points: []SamplePoint{{day(1, 0), 0}, {day(2, 0), 0}, {day(3, 12), 5}},
+			want:   Timeline{"2024-01-02": 3, "2024-01-03": 2},
+		},
+		{
+			name:   "starts from the count of the first sample",
+			points: []SamplePoint{{day(1, 0), 100}, {day(2, 0), 110}, {day(2, 12), 115}},
+			want:   Timeline{"2024-01-01": 10, "2024-01-02": 5},
+		},
+		{
+			name:   "days of the time zone",
+			points: []SamplePoint{{day(1, 0), 0}, {day(1, 20), 10}},
+			daily:  Daily{Location: time.FixedZone("UTC+8", 8*60*60)},
+			want:   Timeline{"2024-01-01": 8, "2024-01-02": 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Interpolate(tt.points, tt.daily)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Interpolate() = %v, want %v", got, tt.want)
+			}
+			if len(tt.points) > 0 {
+				want := tt.points[len(tt.points)-1].Count - tt.points[0].Count
+				if got.Total() != want {
+					t.Errorf("Interpolate() totals %d, want %d", got.Total(), want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+// Package stars fetches, caches, and aggregates the stargazers history of
+// GitHub repositories. It's the library behind gh-stars, for other tools to
+// embed star history logic:
+//
+//	f := stars.NewFetcher(client)
+//	repo, err := f.Repository("charmbracelet/bubbletea")
+//	stargazers, err := f.Stargazers(repo.FullName, repo.StargazersCount)
+//	daily := stars.NewTimeline(stars.StarredAt(stargazers), stars.Daily{Location: time.UTC})
+package stars
+
+import "time"
+
+const (
+	// PerPage is the number of items per page of the list endpoints.
+	PerPage = 100
+	// MaxStargazerPages is the number of pages of stargazers GitHub lists at
+	// most.
+	MaxStargazerPages = 400
+
+	reposPath      = "repos/%s"
+	stargazersPath = "repos/%s/stargazers"
+)
+
+// Stargazer is a user who starred a repository.
+type Stargazer struct {
+	StarredAt time.Time `json:"starred_at"`
+	User      User      `json:"user"`
+}
+
+// User is a GitHub user.
+type User struct {
+	Login string `json:"login"`
+	ID    int64  `json:"id"`
+}
+
+// Repository is the metadata of a repository.
+type Repository struct {
+	FullName         string    `json:"full_name"`
+	Description      string    `json:"description"`
+	Language         string    `json:"language"`
+	CreatedAt        time.Time `json:"created_at"`
+	ForksCount       int       `json:"forks_count"`
+	SubscribersCount int       `json:"subscribers_count"`
+	StargazersCount  int       `json:"stargazers_count"`
+	OpenIssuesCount  int       `json:"open_issues_count"`
+	Archived         bool      `json:"archived"`
+	Private          bool      `json:"private"`
+}
+
+// StarredAt returns the starred dates of stargazers.
+func StarredAt(stargazers []Stargazer) []time.Time {
+	times := make([]time.Time, len(stargazers))
+	for i, s := range stargazers {
+		times[i] = s.StarredAt
+	}
+	return times
+}
+
+// Logins returns the logins of stargazers.
+func Logins(stargazers []Stargazer) []string {
+	logins := make([]string, len(stargazers))
+	for i, s := range stargazers {
+		logins[i] = s.User.Login
+	}
+	return logins
+}
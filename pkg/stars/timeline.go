@@ -0,0 +1,95 @@
+package stars
+
+import (
+	"sort"
+	"time"
+)
+
+// Bucketer assigns times to the buckets of a timeline, such as days.
+// Bucket keys sort in chronological order.
+type Bucketer interface {
+	Bucket(t time.Time) string
+}
+
+// Daily buckets times per day, formatted as 2006-01-02.
+type Daily struct {
+	// Location is the time zone of the days, UTC if nil. GitHub counts
+	// stars in UTC.
+	Location *time.Location
+}
+
+// Bucket implements Bucketer.
+func (d Daily) Bucket(t time.Time) string {
+	return in(t, d.Location).Format("2006-01-02")
+}
+
+// Weekly buckets times per week starting on Monday, keyed by the Monday
+// formatted as 2006-01-02.
+type Weekly struct {
+	Location *time.Location
+}
+
+// Bucket implements Bucketer.
+func (w Weekly) Bucket(t time.Time) string {
+	t = in(t, w.Location)
+	return t.AddDate(0, 0, -(int(t.Weekday())+6)%7).Format("2006-01-02")
+}
+
+// Monthly buckets times per month, formatted as 2006-01.
+type Monthly struct {
+	Location *time.Location
+}
+
+// Bucket implements Bucketer.
+func (m Monthly) Bucket(t time.Time) string {
+	return in(t, m.Location).Format("2006-01")
+}
+
+func in(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc)
+}
+
+// Timeline is the number of stargazers per bucket.
+type Timeline map[string]int
+
+// NewTimeline counts times per bucket.
+func NewTimeline(times []time.Time, b Bucketer) Timeline {
+	t := make(Timeline)
+	for _, s := range times {
+		t[b.Bucket(s)]++
+	}
+	return t
+}
+
+// Keys returns the buckets of the timeline in chronological order.
+func (t Timeline) Keys() []string {
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Total returns the number of stargazers of all buckets.
+func (t Timeline) Total() int {
+	var total int
+	for _, count := range t {
+		total += count
+	}
+	return total
+}
+
+// Since returns the number of stargazers of the buckets after key.
+func (t Timeline) Since(key string) int {
+	var n int
+	for k, count := range t {
+		if k > key {
+			n += count
+		}
+	}
+	return n
+}
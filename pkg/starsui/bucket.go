@@ -0,0 +1,120 @@
+package starsui
+
+import (
+	"time"
+)
+
+const (
+	// dailyBucketDays is the number of recent days bucketed per day.
+	dailyBucketDays = 30
+	// weeklyBucketDays is the number of recent days bucketed per week, the
+	// older ones are bucketed per month.
+	weeklyBucketDays = 365
+)
+
+// Bucket returns the series with the last 30 days kept daily, the rest of
+// the last year summed per week, and older days summed per month. Each bucket
+// is divided by its number of days so the scale stays the same along the
+// series. Labels must be dates formatted as 2006-01-02 in chronological
+// order. The second value maps each index of s to the index of its bucket.
+func (s Series) Bucket(now time.Time) (Series, []int) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	dailyStart := today.AddDate(0, 0, -dailyBucketDays+1)
+	weeklyStart := today.AddDate(0, 0, -weeklyBucketDays+1)
+	bucketed := Series{Name: s.Name}
+	index := make([]int, len(s.Labels))
+	for i, label := range s.Labels {
+		day, err := time.Parse("2006-01-02", label)
+		if err != nil {
+			bucketed.Labels = append(bucketed.Labels, label)
+			bucketed.Values = append(bucketed.Values, s.Values[i])
+			index[i] = len(bucketed.Labels) - 1
+			continue
+		}
+		start, end := bucketRange(day, dailyStart, weeklyStart)
+		days := end.Sub(start).Hours() / 24
+		bucket := start.Format("2006-01-02")
+		if n := len(bucketed.Labels); n > 0 && bucketed.Labels[n-1] == bucket {
+			bucketed.Values[n-1] += s.Values[i] / days
+		} else {
+			bucketed.Labels = append(bucketed.Labels, bucket)
+			bucketed.Values = append(bucketed.Values, s.Values[i]/days)
+		}
+		index[i] = len(bucketed.Labels) - 1
+	}
+	return bucketed, index
+}
+
+// Growth returns the stars of each bucket as a percentage of the stars
+// before it, so 100 new stars weigh more for a repository with 100 stars
+// than for one with 10k. labels are the labels of the buckets, and index maps
+// each index of s to its bucket, or is nil for a bucket per value. Buckets
+// without stars before them are zero.
+func (s Series) Growth(labels []string, index []int) Series {
+	growth := Series{Name: s.Name, Labels: labels, Values: make([]float64, len(labels))}
+	gained := make([]float64, len(labels))
+	before := make([]float64, len(labels))
+	started := make([]bool, len(labels))
+	var total float64
+	for i, v := range s.Values {
+		b := i
+		if index != nil {
+			b = index[i]
+		}
+		if !started[b] {
+			before[b] = total
+			started[b] = true
+		}
+		gained[b] += v
+		total += v
+	}
+	for b := range growth.Values {
+		if before[b] > 0 {
+			growth.Values[b] = 100 * gained[b] / before[b]
+		}
+	}
+	return growth
+}
+
+// Totals returns the running total of s at the end of each bucket. labels
+// and index are as for Growth.
+func (s Series) Totals(labels []string, index []int) Series {
+	totals := Series{Name: s.Name, Labels: labels, Values: make([]float64, len(labels))}
+	var total float64
+	for i, v := range s.Values {
+		b := i
+		if index != nil {
+			b = index[i]
+		}
+		total += v
+		totals.Values[b] = total
+	}
+	return totals
+}
+
+// bucketRange returns the first day and the day after the last one of the
+// bucket of day.
+func bucketRange(day, dailyStart, weeklyStart time.Time) (time.Time, time.Time) {
+	switch {
+	case !day.Before(dailyStart):
+		return day, day.AddDate(0, 0, 1)
+	case !day.Before(weeklyStart):
+		// Weeks start on Monday.
+		start := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		end := start.AddDate(0, 0, 7)
+		if start.Before(weeklyStart) {
+			start = weeklyStart
+		}
+		if end.After(dailyStart) {
+			end = dailyStart
+		}
+		return start, end
+	default:
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+		end := start.AddDate(0, 1, 0)
+		if end.After(weeklyStart) {
+			end = weeklyStart
+		}
+		return start, end
+	}
+}
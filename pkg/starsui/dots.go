@@ -0,0 +1,321 @@
+package starsui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/guptarohit/asciigraph"
+)
+
+// BrailleRenderer renders series as lines of braille dots, which have four
+// times the vertical resolution and twice the horizontal resolution of
+// characters.
+type BrailleRenderer struct{}
+
+// BlockRenderer renders series as bars of block characters, which have
+// eight times the vertical resolution of characters.
+type BlockRenderer struct{}
+
+var (
+	_ CursorRenderer = BrailleRenderer{}
+	_ CursorRenderer = BlockRenderer{}
+)
+
+// brailleDots are the bits of the dots of a braille cell, by row and column.
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// blockEighths are the blocks filling a cell from the bottom in eighths.
+var blockEighths = []rune(" ▁▂▃▄▅▆▇█")
+
+// dotGrid is the plot area of a dot renderer.
+type dotGrid struct {
+	series     []Series
+	opts       RenderOptions
+	min, max   float64
+	labelWidth int
+	width      int
+	height     int
+}
+
+// newDotGrid lays out the plot area of series, with the values converted
+// to a log scale when needed.
+func newDotGrid(series []Series, opts RenderOptions) dotGrid {
+	g := dotGrid{opts: opts, min: math.Inf(1), max: math.Inf(-1)}
+	for _, s := range series {
+		values := make([]float64, len(s.Values))
+		for i, v := range s.Values {
+			if opts.LogScale {
+				v = toLog(math.Max(v, 0))
+			}
+			values[i] = v
+			if !math.IsNaN(v) {
+				g.min = math.Min(g.min, v)
+				g.max = math.Max(g.max, v)
+			}
+		}
+		g.series = append(g.series, Series{Name: s.Name, Labels: s.Labels, Values: values})
+	}
+	bound := func(v float64) float64 {
+		if opts.LogScale {
+			return toLog(v)
+		}
+		return v
+	}
+	if opts.LowerBound != nil {
+		g.min = math.Min(g.min, bound(*opts.LowerBound))
+	}
+	if opts.UpperBound != nil {
+		g.max = math.Max(g.max, bound(*opts.UpperBound))
+	}
+	if g.max == g.min {
+		g.max = g.min + 1
+	}
+	g.labelWidth = len(g.label(g.max))
+	if w := len(g.label(g.min)); w > g.labelWidth {
+		g.labelWidth = w
+	}
+	g.height = opts.Height
+	if opts.Caption != "" {
+		g.height--
+	}
+	// Labels are followed by a space and the axis.
+	g.width = opts.Width - g.labelWidth - 2
+	if g.height < 1 {
+		g.height = 1
+	}
+	if g.width < 1 {
+		g.width = 1
+	}
+	return g
+}
+
+// label formats a plotted value of the Y axis.
+func (g dotGrid) label(v float64) string {
+	if g.opts.LogScale {
+		v = math.Max(fromLog(v), 0)
+	}
+	return fmt.Sprintf("%.0f", v)
+}
+
+// resample returns the values of s at n evenly spaced points. Values are
+// interpolated when there are fewer of them than points, otherwise the
+// largest value of each point is kept so spikes aren't dropped.
+func resample(values []float64, n int) []float64 {
+	out := make([]float64, n)
+	if len(values) == 0 {
+		return out
+	}
+	if len(values) == 1 || n == 1 {
+		for i := range out {
+			out[i] = values[0]
+		}
+		return out
+	}
+	if len(values) <= n {
+		for i := range out {
+			x := float64(i) * float64(len(values)-1) / float64(n-1)
+			j := int(x)
+			if j >= len(values)-1 {
+				out[i] = values[len(values)-1]
+				continue
+			}
+			out[i] = values[j] + (values[j+1]-values[j])*(x-float64(j))
+		}
+		return out
+	}
+	for i := range out {
+		from := i * len(values) / n
+		to := (i + 1) * len(values) / n
+		out[i] = math.Inf(-1)
+		for _, v := range values[from:to] {
+			out[i] = math.Max(out[i], v)
+		}
+	}
+	return out
+}
+
+// scale returns the position of v among steps evenly spaced steps from the
+// bottom of the plot.
+func (g dotGrid) scale(v float64, steps int) int {
+	return int(math.Round((v - g.min) / (g.max - g.min) * float64(steps-1)))
+}
+
+// column returns the plot column of the data point at index of n points.
+func (g dotGrid) column(index, n, perCell int) int {
+	col := 0
+	if n > 1 {
+		col = int(math.Round(float64(index)*float64(g.width*perCell-1)/float64(n-1))) / perCell
+	}
+	return g.labelWidth + 2 + col
+}
+
+// frame renders the rows of the plot area with the Y axis and the caption.
+func (g dotGrid) frame(rows []string) string {
+	colors := g.opts.Colors
+	lines := make([]string, 0, len(rows)+1)
+	for i, row := range rows {
+		v := g.max - (g.max-g.min)*float64(i)/math.Max(float64(len(rows)-1), 1)
+		label := fmt.Sprintf("%*s", g.labelWidth, g.label(v))
+		lines = append(lines, colorize(colors.Label, label)+" "+colorize(colors.Axis, "┤")+row)
+	}
+	if g.opts.Caption != "" {
+		pad := g.labelWidth + 2 + (g.width-len([]rune(g.opts.Caption)))/2
+		if pad < 0 {
+			pad = 0
+		}
+		lines = append(lines, strings.Repeat(" ", pad)+colorize(colors.Caption, g.opts.Caption))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// seriesColor returns the color of the series at index.
+func (g dotGrid) seriesColor(index int) asciigraph.AnsiColor {
+	if len(g.opts.Colors.Series) == 0 {
+		return asciigraph.Default
+	}
+	return g.opts.Colors.Series[index%len(g.opts.Colors.Series)]
+}
+
+func colorize(c asciigraph.AnsiColor, s string) string {
+	if c == asciigraph.Default {
+		return s
+	}
+	return c.String() + s + asciigraph.Default.String()
+}
+
+// Render implements Renderer.
+func (BrailleRenderer) Render(series []Series, opts RenderOptions) string {
+	if len(series) == 0 || len(series[0].Values) == 0 {
+		return ""
+	}
+	g := newDotGrid(series, opts)
+	dots := make([][]rune, g.height)
+	colors := make([][]int, g.height)
+	for y := range dots {
+		dots[y] = make([]rune, g.width)
+		colors[y] = make([]int, g.width)
+		for x := range colors[y] {
+			colors[y][x] = -1
+		}
+	}
+	rows := g.height * 4
+	set := func(x, y, color int) {
+		// y counts dots from the bottom.
+		row := rows - 1 - y
+		dots[row/4][x/2] |= brailleDots[row%4][x%2]
+		colors[row/4][x/2] = color
+	}
+	for i, s := range g.series {
+		values := resample(s.Values, g.width*2)
+		prev := -1
+		for x, v := range values {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				prev = -1
+				continue
+			}
+			y := g.scale(v, rows)
+			from, to := y, y
+			// Connect the dot to the previous one.
+			if prev >= 0 {
+				from, to = int(math.Min(float64(prev), float64(y))), int(math.Max(float64(prev), float64(y)))
+			}
+			for d := from; d <= to; d++ {
+				set(x, d, i)
+			}
+			prev = y
+		}
+	}
+	lines := make([]string, g.height)
+	for y := range dots {
+		var b strings.Builder
+		for x, dot := range dots[y] {
+			if dot == 0 {
+				b.WriteRune(' ')
+				continue
+			}
+			b.WriteString(colorize(g.seriesColor(colors[y][x]), string(0x2800+dot)))
+		}
+		lines[y] = b.String()
+	}
+	return g.frame(lines)
+}
+
+// Column implements CursorRenderer.
+func (BrailleRenderer) Column(series []Series, opts RenderOptions, index int) int {
+	g := newDotGrid(series, opts)
+	return g.column(index, len(series[0].Values), 2)
+}
+
+// Render implements Renderer.
+func (BlockRenderer) Render(series []Series, opts RenderOptions) string {
+	if len(series) == 0 || len(series[0].Values) == 0 {
+		return ""
+	}
+	g := newDotGrid(series, opts)
+	steps := g.height*8 + 1
+	heights := make([][]int, len(g.series))
+	for i, s := range g.series {
+		values := resample(s.Values, g.width)
+		heights[i] = make([]int, g.width)
+		for x, v := range values {
+			if !math.IsNaN(v) && !math.IsInf(v, 0) {
+				heights[i][x] = g.scale(v, steps)
+			}
+		}
+	}
+	lines := make([]string, g.height)
+	for y := range lines {
+		// bottom is the number of eighths below the row.
+		bottom := (g.height - 1 - y) * 8
+		var b strings.Builder
+		for x := 0; x < g.width; x++ {
+			// The shortest bar is drawn in front of the taller ones.
+			color, best := -1, math.MaxInt
+			for i := range heights {
+				if h := heights[i][x] - bottom; h > 0 && h < best {
+					color, best = i, h
+				}
+			}
+			if color < 0 {
+				b.WriteRune(' ')
+				continue
+			}
+			if best > 8 {
+				best = 8
+			}
+			b.WriteString(colorize(g.seriesColor(color), string(blockEighths[best])))
+		}
+		lines[y] = b.String()
+	}
+	return g.frame(lines)
+}
+
+// Column implements CursorRenderer.
+func (BlockRenderer) Column(series []Series, opts RenderOptions, index int) int {
+	g := newDotGrid(series, opts)
+	return g.column(index, len(series[0].Values), 1)
+}
+
+// ChartStyles are the names of the renderers of NewRenderer.
+var ChartStyles = []string{"line", "braille", "block"}
+
+// NewRenderer returns the renderer of a chart style: line for asciigraph,
+// braille, or block.
+func NewRenderer(style string) (Renderer, error) {
+	switch style {
+	case "", "line":
+		return AsciigraphRenderer{}, nil
+	case "braille":
+		return BrailleRenderer{}, nil
+	case "block":
+		return BlockRenderer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown chart style %q, expected %s", style, strings.Join(ChartStyles, ", "))
+	}
+}
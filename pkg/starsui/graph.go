@@ -0,0 +1,223 @@
+// Package starsui provides Bubble Tea components to display the stargazers
+// history of a repository. They can be embedded in other TUIs and gh
+// extensions.
+package starsui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DataMsg sets the daily stargazers of the components that receive it. Keys
+// are dates formatted as 2006-01-02.
+type DataMsg map[string]int
+
+// GraphModel plots the number of stargazers per day.
+type GraphModel struct {
+	// Caption is shown below the graph.
+	Caption string
+	Colors  Colors
+	// Renderer draws the graph, it defaults to AsciigraphRenderer.
+	Renderer Renderer
+	// Smoothing smooths the plotted series.
+	Smoothing Smoothing
+	// LogScale plots the series on a log scale.
+	LogScale bool
+	// Buckets plots the last days daily and older history per week, then
+	// per month.
+	Buckets bool
+	// Growth plots the stars of each day or bucket as a percentage of the
+	// stars before it.
+	Growth bool
+	// Cumulative overlays the total stars, labeled on a second Y axis.
+	Cumulative bool
+	// From and To limit the plotted days to a window, as dates formatted as
+	// 2006-01-02. Empty bounds are open. Zoom and Pan move them.
+	From, To string
+
+	width  int
+	height int
+	series Series
+	// cache holds the last rendered graph and plotted series, views are
+	// rendered on every update.
+	cache *graphCache
+}
+
+type graphCache struct {
+	key  string
+	view string
+	// plotKey identifies the settings plot was computed with. Smoothing a
+	// long history is slow, and Column needs the plot for every marker.
+	plotKey string
+	plot    windowedPlot
+}
+
+// windowedPlot is the result of windowed.
+type windowedPlot struct {
+	series, totals Series
+	index          []int
+	offset         int
+}
+
+// NewGraphModel returns a graph of daily stargazers.
+func NewGraphModel(daily map[string]int) GraphModel {
+	var m GraphModel
+	m.SetData(daily)
+	return m
+}
+
+// SetData sets the daily stargazers.
+func (m *GraphModel) SetData(daily map[string]int) {
+	m.series = NewDailySeries("stars", daily)
+	m.cache = &graphCache{}
+}
+
+// SetSize sets the size of the graph including its caption.
+func (m *GraphModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// Keys returns the dates of the graph in chronological order.
+func (m GraphModel) Keys() []string {
+	return m.series.Labels
+}
+
+// Series returns the plotted series.
+func (m GraphModel) Series() Series {
+	return m.series
+}
+
+func (m GraphModel) renderer() Renderer {
+	if m.Renderer == nil {
+		return AsciigraphRenderer{}
+	}
+	return m.Renderer
+}
+
+func (m GraphModel) options() RenderOptions {
+	return RenderOptions{
+		Width:    m.width,
+		Height:   m.height,
+		Caption:  m.Caption,
+		Colors:   m.Colors,
+		LogScale: m.LogScale,
+	}
+}
+
+// RenderWith renders the graph with another renderer than its own, such as
+// an ImageRenderer. The output isn't cached.
+func (m GraphModel) RenderWith(r Renderer) string {
+	if len(m.series.Values) == 0 {
+		return ""
+	}
+	s, totals, _, _ := m.windowed()
+	if len(s.Values) == 0 {
+		return ""
+	}
+	if m.Cumulative {
+		// The output can't be labeled, the totals are only scaled.
+		scaled, _ := scaleTo(totals, s)
+		return r.Render([]Series{s, scaled}, m.options())
+	}
+	return r.Render([]Series{s}, m.options())
+}
+
+// plotted returns the series as plotted, and the index of the plotted data
+// point of each data point when they are bucketed.
+func (m GraphModel) plotted() (Series, []int) {
+	s := m.series.Smooth(m.Smoothing)
+	plotted, index := s, []int(nil)
+	if m.Buckets {
+		plotted, index = s.Bucket(time.Now())
+	}
+	if m.Growth {
+		plotted = s.Growth(plotted.Labels, index)
+	}
+	return plotted, index
+}
+
+// windowed returns the plotted series and its running total within the
+// window, the index of the plotted data point of each data point, and the
+// index of the first plotted data point in the window.
+func (m GraphModel) windowed() (Series, Series, []int, int) {
+	// Buckets end today, so they change with the day.
+	key := fmt.Sprintf("%v %v %v %s %s %s", m.Smoothing, m.Buckets, m.Growth, m.From, m.To, time.Now().Format("2006-01-02"))
+	if m.cache != nil && m.cache.plotKey == key {
+		p := m.cache.plot
+		return p.series, p.totals, p.index, p.offset
+	}
+	s, index := m.plotted()
+	totals := m.series.Totals(s.Labels, index)
+	s, offset := s.Window(m.From, m.To)
+	totals, _ = totals.Window(m.From, m.To)
+	if m.cache != nil {
+		m.cache.plotKey, m.cache.plot = key, windowedPlot{series: s, totals: totals, index: index, offset: offset}
+	}
+	return s, totals, index, offset
+}
+
+// Column returns the column of the data point at index, or -1 if the
+// renderer can't locate data points.
+func (m GraphModel) Column(index int) int {
+	r, ok := m.renderer().(CursorRenderer)
+	if !ok || len(m.series.Values) == 0 {
+		return -1
+	}
+	s, totals, buckets, offset := m.windowed()
+	opts := m.options()
+	if m.Cumulative {
+		opts.Width -= dualAxisWidth(totals)
+	}
+	if buckets != nil && index >= 0 && index < len(buckets) {
+		index = buckets[index]
+	}
+	index -= offset
+	if index < 0 || index >= len(s.Values) {
+		return -1
+	}
+	return r.Column([]Series{s}, opts, index)
+}
+
+// Init implements tea.Model.
+func (m GraphModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles DataMsg and tea.WindowSizeMsg.
+func (m GraphModel) Update(msg tea.Msg) (GraphModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case DataMsg:
+		m.SetData(msg)
+	case tea.WindowSizeMsg:
+		m.SetSize(msg.Width, msg.Height)
+	}
+	return m, nil
+}
+
+// View renders the graph.
+func (m GraphModel) View() string {
+	if len(m.series.Values) == 0 {
+		return "\n No stargazers found.\n"
+	}
+	key := fmt.Sprintf("%d %d %q %v %v %v %v %v %v %s %s %T", m.width, m.height, m.Caption, m.Colors, m.Smoothing, m.LogScale, m.Buckets, m.Growth, m.Cumulative, m.From, m.To, m.renderer())
+	if m.cache != nil && m.cache.key == key {
+		return m.cache.view
+	}
+	s, totals, _, _ := m.windowed()
+	if len(s.Values) == 0 {
+		return "\n No stargazers in the window.\n"
+	}
+	var view string
+	if m.Cumulative {
+		view = RenderDualAxis(m.renderer(), s, totals, m.options())
+	} else {
+		view = m.renderer().Render([]Series{s}, m.options())
+	}
+	if m.cache != nil {
+		m.cache.key, m.cache.view = key, view
+	}
+	return view
+}
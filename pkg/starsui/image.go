@@ -0,0 +1,298 @@
+package starsui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"strings"
+
+	"github.com/guptarohit/asciigraph"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Terminal graphics protocols.
+const (
+	GraphicsASCII = "ascii"
+	GraphicsKitty = "kitty"
+	GraphicsSixel = "sixel"
+)
+
+// GraphicsProtocols are the ways graphs can be drawn in the terminal.
+var GraphicsProtocols = []string{GraphicsASCII, GraphicsKitty, GraphicsSixel}
+
+const (
+	// CellWidth and CellHeight are the size of a terminal cell in pixels
+	// assumed for images. Kitty scales images to the cells they cover, sixel
+	// images are drawn at this size.
+	CellWidth  = 10
+	CellHeight = 20
+	// kittyChunk is the size of the base64 chunks of kitty images.
+	kittyChunk = 4096
+)
+
+// ImageRenderer renders series as a bitmap line chart drawn with a terminal
+// graphics protocol. The width and height of the render options are in
+// cells, and the output is the escape sequence drawing the chart at the
+// cursor position, caption included.
+//
+// Bubble Tea truncates lines with escape sequences it doesn't know, so TUIs
+// must write the output to the terminal themselves, over blank lines of the
+// size of the chart.
+type ImageRenderer struct {
+	// Protocol is GraphicsKitty or GraphicsSixel.
+	Protocol string
+}
+
+var _ Renderer = ImageRenderer{}
+
+// Render implements Renderer.
+func (r ImageRenderer) Render(series []Series, opts RenderOptions) string {
+	cols, rows := opts.Width, opts.Height
+	if cols <= 0 || rows <= 0 {
+		return ""
+	}
+	img := Rasterize(series, opts, cols*CellWidth, rows*CellHeight)
+	if r.Protocol == GraphicsSixel {
+		return EncodeSixel(img)
+	}
+	return EncodeKitty(img, cols, rows)
+}
+
+// imageColor converts an ANSI 256 color to RGB, the default color being
+// replaced by fallback.
+func imageColor(c asciigraph.AnsiColor, fallback string) color.RGBA {
+	var rgb color.RGBA
+	rgb.A = 0xff
+	fmt.Sscanf(svgColor(c, fallback), "#%02x%02x%02x", &rgb.R, &rgb.G, &rgb.B)
+	return rgb
+}
+
+// Rasterize draws series as a line chart of width by height pixels on a
+// transparent background, with the range of the Y axis, the first and last
+// labels, and the caption.
+func Rasterize(series []Series, opts RenderOptions, width, height int) *image.Paletted {
+	palette := color.Palette{
+		color.RGBA{},
+		imageColor(opts.Colors.Axis, "#888888"),
+		imageColor(opts.Colors.Label, "#888888"),
+		imageColor(opts.Colors.Caption, "#888888"),
+	}
+	const (
+		axisIndex    = 1
+		labelIndex   = 2
+		captionIndex = 3
+	)
+	for i := range series {
+		fallback := defaultSVGColors[i%len(defaultSVGColors)]
+		if i < len(opts.Colors.Series) {
+			palette = append(palette, imageColor(opts.Colors.Series[i], fallback))
+		} else {
+			palette = append(palette, imageColor(asciigraph.Default, fallback))
+		}
+	}
+	img := image.NewPaletted(image.Rect(0, 0, width, height), palette)
+
+	g := newDotGrid(series, opts)
+	face := basicfont.Face7x13
+	text := func(x, y int, s string, index uint8) {
+		d := font.Drawer{
+			Dst:  img,
+			Src:  image.NewUniform(palette[index]),
+			Face: face,
+			Dot:  fixed.P(x, y),
+		}
+		d.DrawString(s)
+	}
+	textWidth := func(s string) int {
+		return font.MeasureString(face, s).Round()
+	}
+	maxLabel, minLabel := g.label(g.max), g.label(g.min)
+	left := textWidth(maxLabel)
+	if w := textWidth(minLabel); w > left {
+		left = w
+	}
+	left += 6
+	top, bottom := 8, height-2*CellHeight
+	if opts.Caption == "" {
+		bottom = height - CellHeight
+	}
+	right := width - 8
+	if right <= left || bottom <= top {
+		return img
+	}
+	text(left-textWidth(maxLabel)-4, top+5, maxLabel, labelIndex)
+	text(left-textWidth(minLabel)-4, bottom+5, minLabel, labelIndex)
+	for x := left; x <= right; x++ {
+		img.SetColorIndex(x, bottom, axisIndex)
+	}
+	for y := top; y <= bottom; y++ {
+		img.SetColorIndex(left, y, axisIndex)
+	}
+	if len(series) > 0 && len(series[0].Labels) > 0 {
+		labels := series[0].Labels
+		text(left, bottom+15, labels[0], labelIndex)
+		last := labels[len(labels)-1]
+		text(right-textWidth(last), bottom+15, last, labelIndex)
+	}
+	if opts.Caption != "" {
+		text((width-textWidth(opts.Caption))/2, height-6, opts.Caption, captionIndex)
+	}
+
+	for i, s := range g.series {
+		n := len(s.Values)
+		x := func(j int) int {
+			if n <= 1 {
+				return left + 1
+			}
+			return left + 1 + j*(right-left-1)/(n-1)
+		}
+		y := func(v float64) int {
+			return bottom - 1 - int(math.Round((v-g.min)*float64(bottom-top-1)/(g.max-g.min)))
+		}
+		index := uint8(4 + i)
+		px, py, started := 0, 0, false
+		for j, v := range s.Values {
+			if math.IsNaN(v) {
+				started = false
+				continue
+			}
+			cx, cy := x(j), y(v)
+			if started {
+				drawLine(img, px, py, cx, cy, index)
+			} else {
+				drawLine(img, cx, cy, cx, cy, index)
+			}
+			px, py, started = cx, cy, true
+		}
+	}
+	return img
+}
+
+// drawLine draws a line two pixels thick between two points.
+func drawLine(img *image.Paletted, x0, y0, x1, y1 int, index uint8) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	e := dx + dy
+	for {
+		img.SetColorIndex(x0, y0, index)
+		img.SetColorIndex(x0+1, y0, index)
+		img.SetColorIndex(x0, y0+1, index)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		if e2 := 2 * e; e2 >= dy {
+			e += dy
+			x0 += sx
+		} else {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// EncodeKitty returns the kitty graphics protocol sequence drawing img
+// scaled to cols by rows cells at the cursor position, without moving the
+// cursor or getting a response from the terminal.
+func EncodeKitty(img image.Image, cols, rows int) string {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return ""
+	}
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+	var b strings.Builder
+	for first := true; first || len(data) > 0; first = false {
+		chunk := data
+		if len(chunk) > kittyChunk {
+			chunk = chunk[:kittyChunk]
+		}
+		data = data[len(chunk):]
+		more := 0
+		if len(data) > 0 {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,q=2,C=1,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return b.String()
+}
+
+// KittyClear is the kitty graphics protocol sequence deleting the images
+// shown.
+const KittyClear = "\x1b_Ga=d,q=2\x1b\\"
+
+// EncodeSixel returns the sixel sequence drawing img at the cursor position.
+// Transparent pixels, the first color of the palette, are left untouched.
+func EncodeSixel(img *image.Paletted) string {
+	var b strings.Builder
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	fmt.Fprintf(&b, "\x1bP0;1;0q\"1;1;%d;%d", width, height)
+	for i, c := range img.Palette[1:] {
+		r, g, bl, _ := c.RGBA()
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i+1, r*100/0xffff, g*100/0xffff, bl*100/0xffff)
+	}
+	for band := 0; band < height; band += 6 {
+		for index := 1; index < len(img.Palette); index++ {
+			row := make([]byte, width)
+			used := false
+			for x := 0; x < width; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && band+dy < height; dy++ {
+					if img.ColorIndexAt(bounds.Min.X+x, bounds.Min.Y+band+dy) == uint8(index) {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = 63 + bits
+				used = used || bits != 0
+			}
+			if !used {
+				continue
+			}
+			fmt.Fprintf(&b, "#%d", index)
+			writeSixelRun(&b, row)
+			b.WriteByte('$')
+		}
+		b.WriteByte('-')
+	}
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+// writeSixelRun writes a row of sixels with repeated sixels run-length
+// encoded.
+func writeSixelRun(b *strings.Builder, row []byte) {
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(b, "!%d%c", n, row[i])
+		} else {
+			b.Write(row[i:j])
+		}
+		i = j
+	}
+}
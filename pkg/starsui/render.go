@@ -0,0 +1,309 @@
+package starsui
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guptarohit/asciigraph"
+)
+
+// Series is a sequence of labeled values, such as the number of stargazers
+// per day.
+type Series struct {
+	Name   string
+	Labels []string
+	Values []float64
+}
+
+// NewDailySeries returns the series of daily stargazers in chronological
+// order.
+func NewDailySeries(name string, daily map[string]int) Series {
+	s := Series{Name: name, Labels: make([]string, 0, len(daily))}
+	for k := range daily {
+		s.Labels = append(s.Labels, k)
+	}
+	sort.Strings(s.Labels)
+	s.Values = make([]float64, len(s.Labels))
+	for i, k := range s.Labels {
+		s.Values[i] = float64(daily[k])
+	}
+	return s
+}
+
+// Cumulative returns the running total of the series.
+func (s Series) Cumulative() Series {
+	c := Series{Name: s.Name, Labels: s.Labels, Values: make([]float64, len(s.Values))}
+	var total float64
+	for i, v := range s.Values {
+		total += v
+		c.Values[i] = total
+	}
+	return c
+}
+
+// Max returns the largest value of the series, ignoring NaNs.
+func (s Series) Max() float64 {
+	max := math.Inf(-1)
+	for _, v := range s.Values {
+		if !math.IsNaN(v) && v > max {
+			max = v
+		}
+	}
+	return max
+}
+
+// Colors are the ANSI 256 colors of a rendered graph.
+type Colors struct {
+	// Series are the colors of each series in order.
+	Series  []asciigraph.AnsiColor
+	Axis    asciigraph.AnsiColor
+	Label   asciigraph.AnsiColor
+	Caption asciigraph.AnsiColor
+}
+
+// RenderOptions configures how series are rendered.
+type RenderOptions struct {
+	// Width and Height are the size of the output including the caption.
+	Width   int
+	Height  int
+	Caption string
+	Colors  Colors
+	// LowerBound and UpperBound fix the range of the Y axis when set.
+	LowerBound *float64
+	UpperBound *float64
+	// LogScale plots the logarithm of the values, with the labels of the Y
+	// axis showing the values.
+	LogScale bool
+}
+
+// Renderer renders series. The same series can be rendered to the terminal
+// or exported to other formats.
+type Renderer interface {
+	Render(series []Series, opts RenderOptions) string
+}
+
+// CursorRenderer is a Renderer that can locate a data point in its output.
+type CursorRenderer interface {
+	Renderer
+	// Column returns the column of the data point at index.
+	Column(series []Series, opts RenderOptions, index int) int
+}
+
+// AsciigraphRenderer renders series as a line chart using asciigraph.
+type AsciigraphRenderer struct{}
+
+var _ CursorRenderer = AsciigraphRenderer{}
+
+// logScale is the factor of the logarithms plotted on a log scale, so the
+// labels keep enough precision without decimals.
+const logScale = 100
+
+func toLog(v float64) float64 {
+	return logScale * math.Log10(1+v)
+}
+
+func fromLog(v float64) float64 {
+	return math.Pow(10, v/logScale) - 1
+}
+
+// prepare returns the series and options as plotted, and how many columns
+// the labels of the Y axis are shifted by when converted back from a log
+// scale.
+func (AsciigraphRenderer) prepare(series []Series, opts RenderOptions) ([]Series, RenderOptions, int) {
+	if !opts.LogScale {
+		return series, opts, 0
+	}
+	logs := make([]Series, len(series))
+	max := 0.0
+	for i, s := range series {
+		logs[i] = Series{Name: s.Name, Labels: s.Labels, Values: make([]float64, len(s.Values))}
+		for j, v := range s.Values {
+			logs[i].Values[j] = toLog(math.Max(v, 0))
+		}
+		max = math.Max(max, s.Max())
+	}
+	if opts.LowerBound != nil {
+		lower := toLog(*opts.LowerBound)
+		opts.LowerBound = &lower
+	}
+	if opts.UpperBound != nil {
+		max = math.Max(max, *opts.UpperBound)
+		upper := toLog(*opts.UpperBound)
+		opts.UpperBound = &upper
+	}
+	shift := len(fmt.Sprintf("%.0f", max)) - len(fmt.Sprintf("%.0f", toLog(max)))
+	if shift < 0 {
+		shift = 0
+	}
+	return logs, opts, shift
+}
+
+// layout returns the offset of the axis, the number of digits of the largest
+// value, and the number of columns the data is interpolated to.
+func (AsciigraphRenderer) layout(series []Series, opts RenderOptions, shift int) (int, int, int) {
+	max := math.Inf(-1)
+	for _, s := range series {
+		max = math.Max(max, s.Max())
+	}
+	if opts.UpperBound != nil {
+		max = math.Max(max, *opts.UpperBound)
+	}
+	digits := len(fmt.Sprintf("%.0f", max))
+	offset := digits
+	if offset < 3 {
+		offset = 3
+	}
+	return offset, digits, opts.Width - offset - 1 - shift
+}
+
+// labelRe matches the label of the Y axis at the start of a line.
+var labelRe = regexp.MustCompile(`^((?:\x1b\[[0-9;]*m)*)( *-?[0-9]+)`)
+
+// unlogLabels converts the labels of the Y axis back from a log scale.
+func unlogLabels(graph string, width int) string {
+	lines := strings.Split(graph, "\n")
+	for i, line := range lines {
+		if !strings.ContainsAny(line, "┤┼") {
+			continue
+		}
+		m := labelRe.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(line[m[4]:m[5]]), 64)
+		if err != nil {
+			continue
+		}
+		label := fmt.Sprintf("%*.0f", width, math.Max(fromLog(v), 0))
+		lines[i] = line[:m[4]] + label + line[m[5]:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// scaleTo returns secondary scaled to the range of primary, and the factor
+// converting the values of primary back to the values of secondary.
+func scaleTo(secondary, primary Series) (Series, float64) {
+	pmax, smax := primary.Max(), secondary.Max()
+	if pmax <= 0 || smax <= 0 || math.IsInf(pmax, 0) || math.IsInf(smax, 0) {
+		return secondary, 1
+	}
+	scaled := Series{Name: secondary.Name, Labels: secondary.Labels, Values: make([]float64, len(secondary.Values))}
+	for i, v := range secondary.Values {
+		scaled.Values[i] = v * pmax / smax
+	}
+	return scaled, smax / pmax
+}
+
+// dualAxisWidth returns the width of the second Y axis labeling the values
+// of secondary: the axis, a space, and the labels.
+func dualAxisWidth(secondary Series) int {
+	digits := len(fmt.Sprintf("%.0f", secondary.Max()))
+	if digits < 3 {
+		digits = 3
+	}
+	return digits + 2
+}
+
+// RenderDualAxis renders secondary over primary, scaled to its range, with
+// the values of secondary labeled on a second Y axis on the right. The
+// right labels are derived from the left ones, so r must label the rows of
+// its plot like AsciigraphRenderer.
+func RenderDualAxis(r Renderer, primary, secondary Series, opts RenderOptions) string {
+	scaled, factor := scaleTo(secondary, primary)
+	opts.Width -= dualAxisWidth(secondary)
+	digits := dualAxisWidth(secondary) - 2
+	graph := r.Render([]Series{primary, scaled}, opts)
+	lines := strings.Split(graph, "\n")
+	width := 0
+	for _, line := range lines {
+		if strings.ContainsAny(line, "┤┼") {
+			if w := lipgloss.Width(line); w > width {
+				width = w
+			}
+		}
+	}
+	color := asciigraph.Default
+	if len(opts.Colors.Series) > 1 {
+		color = opts.Colors.Series[1]
+	}
+	for i, line := range lines {
+		if !strings.ContainsAny(line, "┤┼") {
+			continue
+		}
+		m := labelRe.FindStringSubmatchIndex(line)
+		if m == nil {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(line[m[4]:m[5]]), 64)
+		if err != nil {
+			continue
+		}
+		label := fmt.Sprintf("├ %*.0f", digits, math.Max(v*factor, 0))
+		if color != asciigraph.Default {
+			label = color.String() + label + "\x1b[0m"
+		}
+		lines[i] = line + strings.Repeat(" ", width-lipgloss.Width(line)) + label
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Render implements Renderer.
+func (a AsciigraphRenderer) Render(series []Series, opts RenderOptions) string {
+	if len(series) == 0 || len(series[0].Values) == 0 {
+		return ""
+	}
+	series, opts, shift := a.prepare(series, opts)
+	offset, digits, width := a.layout(series, opts, shift)
+	height := opts.Height
+	if opts.Caption != "" {
+		height--
+	}
+	data := make([][]float64, len(series))
+	for i, s := range series {
+		// asciigraph modifies the data in place.
+		data[i] = append([]float64(nil), s.Values...)
+	}
+	options := []asciigraph.Option{
+		asciigraph.SeriesColors(opts.Colors.Series...),
+		asciigraph.AxisColor(opts.Colors.Axis),
+		asciigraph.LabelColor(opts.Colors.Label),
+		asciigraph.CaptionColor(opts.Colors.Caption),
+		asciigraph.Width(width),
+		asciigraph.Height(height),
+		asciigraph.Caption(opts.Caption),
+		asciigraph.Precision(0),
+		asciigraph.Offset(offset),
+	}
+	if opts.LowerBound != nil {
+		options = append(options, asciigraph.LowerBound(*opts.LowerBound))
+	}
+	if opts.UpperBound != nil {
+		options = append(options, asciigraph.UpperBound(*opts.UpperBound))
+	}
+	graph := asciigraph.PlotMany(data, options...)
+	if opts.LogScale {
+		// Labels are padded to the widest one plus one column.
+		graph = unlogLabels(graph, digits+1+shift)
+	}
+	return graph
+}
+
+// Column implements CursorRenderer.
+func (a AsciigraphRenderer) Column(series []Series, opts RenderOptions, index int) int {
+	series, opts, shift := a.prepare(series, opts)
+	offset, digits, width := a.layout(series, opts, shift)
+	// asciigraph pads the labels to the widest value plus one column, then
+	// draws the axis at the offset. Each data point is drawn one column
+	// before its segment.
+	col := digits + offset - 1 + shift
+	n := len(series[0].Values)
+	if n > 1 && width > 1 {
+		col += int(math.Round(float64(index) * float64(width-1) / float64(n-1)))
+	}
+	return col
+}
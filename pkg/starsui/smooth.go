@@ -0,0 +1,105 @@
+package starsui
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Smoothing is a way to smooth the noise of daily series.
+type Smoothing int
+
+const (
+	// SmoothNone plots the raw values.
+	SmoothNone Smoothing = iota
+	// SmoothMovingAverage plots the average of the last 7 days.
+	SmoothMovingAverage
+	// SmoothLoess plots a local linear regression weighted by distance,
+	// in the style of LOESS.
+	SmoothLoess
+	smoothingCount
+)
+
+const (
+	movingAverageDays = 7
+	// loessBandwidth is the number of days on each side of a point that
+	// weigh on its regression.
+	loessBandwidth = 15
+)
+
+var smoothingNames = [...]string{"none", "7d", "loess"}
+
+// String returns the name of the smoothing as accepted by ParseSmoothing.
+func (s Smoothing) String() string {
+	return smoothingNames[s]
+}
+
+// Next returns the next smoothing, wrapping around.
+func (s Smoothing) Next() Smoothing {
+	return (s + 1) % smoothingCount
+}
+
+// ParseSmoothing parses one of none, 7d, or loess.
+func ParseSmoothing(name string) (Smoothing, error) {
+	for i, n := range smoothingNames {
+		if n == name {
+			return Smoothing(i), nil
+		}
+	}
+	return SmoothNone, fmt.Errorf("unknown smoothing %q, expected none, 7d, or loess", name)
+}
+
+// Smooth returns the series smoothed over calendar days. Labels must be
+// dates formatted as 2006-01-02, days without a label count as zero.
+func (s Series) Smooth(smoothing Smoothing) Series {
+	if smoothing == SmoothNone {
+		return s
+	}
+	daily := make(map[string]float64, len(s.Labels))
+	for i, label := range s.Labels {
+		daily[label] = s.Values[i]
+	}
+	smoothed := Series{Name: s.Name, Labels: s.Labels, Values: make([]float64, len(s.Values))}
+	for i, label := range s.Labels {
+		day, err := time.Parse("2006-01-02", label)
+		if err != nil {
+			smoothed.Values[i] = s.Values[i]
+			continue
+		}
+		switch smoothing {
+		case SmoothMovingAverage:
+			var sum float64
+			for k := 0; k < movingAverageDays; k++ {
+				sum += daily[day.AddDate(0, 0, -k).Format("2006-01-02")]
+			}
+			smoothed.Values[i] = sum / movingAverageDays
+		case SmoothLoess:
+			smoothed.Values[i] = loess(daily, day)
+		}
+	}
+	return smoothed
+}
+
+// loess returns the value at day of the linear regression of the
+// surrounding days weighted with the tricube function.
+func loess(daily map[string]float64, day time.Time) float64 {
+	var sw, sx, sy, sxx, sxy float64
+	for k := -loessBandwidth; k <= loessBandwidth; k++ {
+		d := math.Abs(float64(k)) / (loessBandwidth + 1)
+		w := math.Pow(1-d*d*d, 3)
+		x := float64(k)
+		y := daily[day.AddDate(0, 0, k).Format("2006-01-02")]
+		sw += w
+		sx += w * x
+		sy += w * y
+		sxx += w * x * x
+		sxy += w * x * y
+	}
+	// The regression is evaluated at x = 0.
+	denom := sw*sxx - sx*sx
+	if denom == 0 {
+		return sy / sw
+	}
+	slope := (sw*sxy - sx*sy) / denom
+	return math.Max(0, (sy-slope*sx)/sw)
+}
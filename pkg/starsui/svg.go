@@ -0,0 +1,138 @@
+package starsui
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+
+	"github.com/guptarohit/asciigraph"
+)
+
+// svgPadding is the space around the plot area in pixels. It holds the axis
+// labels and the caption.
+const svgPadding = 40
+
+// defaultSVGColors are used for series without an explicit color.
+var defaultSVGColors = []string{"#1f6feb", "#fb8500", "#2a9d8f", "#e63946"}
+
+// SVGRenderer renders series as an SVG line chart. The width and height of
+// the render options are in pixels.
+type SVGRenderer struct{}
+
+var _ Renderer = SVGRenderer{}
+
+// Render implements Renderer.
+func (SVGRenderer) Render(series []Series, opts RenderOptions) string {
+	width, height := opts.Width, opts.Height
+	if width <= 0 {
+		width = 800
+	}
+	if height <= 0 {
+		height = 400
+	}
+	min, max := math.Inf(1), math.Inf(-1)
+	n := 0
+	for _, s := range series {
+		for _, v := range s.Values {
+			if math.IsNaN(v) {
+				continue
+			}
+			min = math.Min(min, v)
+			max = math.Max(max, v)
+		}
+		if len(s.Values) > n {
+			n = len(s.Values)
+		}
+	}
+	if opts.LowerBound != nil {
+		min = math.Min(min, *opts.LowerBound)
+	}
+	if opts.UpperBound != nil {
+		max = math.Max(max, *opts.UpperBound)
+	}
+	if n == 0 {
+		min, max = 0, 0
+	}
+	if max == min {
+		max = min + 1
+	}
+	left, top := float64(svgPadding), float64(svgPadding/2)
+	plotWidth := float64(width) - 1.5*svgPadding
+	plotHeight := float64(height) - 1.5*svgPadding
+	x := func(i int) float64 {
+		if n <= 1 {
+			return left
+		}
+		return left + float64(i)*plotWidth/float64(n-1)
+	}
+	y := func(v float64) float64 {
+		return top + plotHeight - (v-min)*plotHeight/(max-min)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="white"/>`+"\n")
+	axis := svgColor(opts.Colors.Axis, "#888888")
+	fmt.Fprintf(&b, `<path d="M%.1f %.1f V%.1f H%.1f" stroke="%s" fill="none"/>`+"\n",
+		left, top, top+plotHeight, left+plotWidth, axis)
+	label := svgColor(opts.Colors.Label, "#333333")
+	fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="end" fill="%s">%.0f</text>`+"\n", left-4, top+4, label, max)
+	fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="end" fill="%s">%.0f</text>`+"\n", left-4, top+plotHeight+4, label, min)
+	if len(series) > 0 && len(series[0].Labels) > 0 {
+		labels := series[0].Labels
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" fill="%s">%s</text>`+"\n",
+			left, top+plotHeight+16, label, html.EscapeString(labels[0]))
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="end" fill="%s">%s</text>`+"\n",
+			left+plotWidth, top+plotHeight+16, label, html.EscapeString(labels[len(labels)-1]))
+	}
+	for i, s := range series {
+		color := defaultSVGColors[i%len(defaultSVGColors)]
+		if i < len(opts.Colors.Series) {
+			color = svgColor(opts.Colors.Series[i], color)
+		}
+		points := make([]string, 0, len(s.Values))
+		for j, v := range s.Values {
+			if math.IsNaN(v) {
+				continue
+			}
+			points = append(points, fmt.Sprintf("%.1f,%.1f", x(j), y(v)))
+		}
+		fmt.Fprintf(&b, `<polyline points="%s" stroke="%s" stroke-width="2" fill="none"><title>%s</title></polyline>`+"\n",
+			strings.Join(points, " "), color, html.EscapeString(s.Name))
+	}
+	if opts.Caption != "" {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle" fill="%s">%s</text>`+"\n",
+			left+plotWidth/2, height-8, svgColor(opts.Colors.Caption, "#333333"), html.EscapeString(opts.Caption))
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+// ansi16 are the RGB values of the 16 standard ANSI colors.
+var ansi16 = [16]string{
+	"#000000", "#800000", "#008000", "#808000", "#000080", "#800080", "#008080", "#c0c0c0",
+	"#808080", "#ff0000", "#00ff00", "#ffff00", "#0000ff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// svgColor converts an ANSI 256 color to a hex color. The default color is
+// replaced by fallback.
+func svgColor(c asciigraph.AnsiColor, fallback string) string {
+	switch {
+	case c == asciigraph.Default:
+		return fallback
+	case c == asciigraph.Black:
+		// asciigraph uses a dummy value for black.
+		return ansi16[0]
+	case c < 16:
+		return ansi16[c]
+	case c < 232:
+		levels := [6]int{0, 95, 135, 175, 215, 255}
+		n := int(c) - 16
+		return fmt.Sprintf("#%02x%02x%02x", levels[n/36], levels[n/6%6], levels[n%6])
+	default:
+		gray := 8 + 10*(int(c)-232)
+		return fmt.Sprintf("#%02x%02x%02x", gray, gray, gray)
+	}
+}
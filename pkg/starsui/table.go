@@ -0,0 +1,263 @@
+package starsui
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Table columns.
+const (
+	ColumnDate    = "date"
+	ColumnStars   = "stars"
+	ColumnTotal   = "total"
+	ColumnAverage = "average"
+	ColumnWeekday = "weekday"
+	ColumnPercent = "percent"
+)
+
+// TableColumns are the columns the table can show.
+var TableColumns = []string{ColumnDate, ColumnStars, ColumnTotal, ColumnAverage, ColumnWeekday, ColumnPercent}
+
+// DefaultColumnSets are the column sets cycled through when none are
+// configured.
+var DefaultColumnSets = [][]string{
+	{ColumnDate, ColumnStars},
+	{ColumnDate, ColumnStars, ColumnTotal, ColumnAverage},
+	{ColumnDate, ColumnWeekday, ColumnStars, ColumnPercent},
+}
+
+// tableColumns are the titles and widths of the columns.
+var tableColumns = map[string]table.Column{
+	ColumnDate:    {Title: "Date", Width: 20},
+	ColumnStars:   {Title: "Stars", Width: 10},
+	ColumnTotal:   {Title: "Total", Width: 10},
+	ColumnAverage: {Title: "7d avg", Width: 8},
+	ColumnWeekday: {Title: "Day", Width: 5},
+	ColumnPercent: {Title: "% total", Width: 8},
+}
+
+// ValidateColumns returns an error if a column is unknown or there are
+// none.
+func ValidateColumns(columns []string) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("no table columns")
+	}
+	for _, c := range columns {
+		if _, ok := tableColumns[c]; !ok {
+			return fmt.Errorf("unknown table column %q, expected one of %v", c, TableColumns)
+		}
+	}
+	return nil
+}
+
+// TableModel lists the number of stargazers per day, newest first.
+type TableModel struct {
+	table.Model
+	// sets are the column sets cycled through by NextColumns,
+	// DefaultColumnSets if empty.
+	sets  [][]string
+	set   int
+	daily map[string]int
+	// keep returns whether to show a day with stars, all days are shown
+	// when nil.
+	keep func(stars int) bool
+	// number and date format the counts and days of the cells, plain
+	// digits and 2006-01-02 days when nil.
+	number func(int) string
+	date   func(day string) string
+	// keys are the days of the rows.
+	keys []string
+}
+
+// NewTableModel returns a focused table of daily stargazers showing the
+// first default column set.
+func NewTableModel(opts ...table.Option) TableModel {
+	m := TableModel{
+		Model: table.New(
+			append([]table.Option{
+				table.WithFocused(true),
+			}, opts...)...,
+		),
+	}
+	m.showColumns(m.ColumnNames())
+	return m
+}
+
+// ColumnNames returns the columns shown.
+func (m TableModel) ColumnNames() []string {
+	sets := m.sets
+	if len(sets) == 0 {
+		sets = DefaultColumnSets
+	}
+	return sets[m.set%len(sets)]
+}
+
+// SetColumnSets sets the column sets cycled through and shows the first
+// one. Their columns must be valid.
+func (m *TableModel) SetColumnSets(sets [][]string) {
+	m.sets, m.set = sets, 0
+	m.showColumns(m.ColumnNames())
+}
+
+// NextColumns shows the next column set, wrapping around.
+func (m *TableModel) NextColumns() {
+	m.set++
+	m.showColumns(m.ColumnNames())
+}
+
+// showColumns shows columns, which must be valid.
+func (m *TableModel) showColumns(columns []string) {
+	cols := make([]table.Column, len(columns))
+	for i, c := range columns {
+		cols[i] = tableColumns[c]
+	}
+	// Rows must match the columns before they are rendered again.
+	m.SetRows(nil)
+	m.Model.SetColumns(cols)
+	m.SetData(m.daily)
+}
+
+// ColumnsWidth returns the width of the columns shown, padding included.
+func (m TableModel) ColumnsWidth() int {
+	var width int
+	for _, c := range m.ColumnNames() {
+		width += tableColumns[c].Width + 2
+	}
+	return width
+}
+
+// SelectedDay returns the day of the selected row, or an empty string if
+// there is none.
+func (m TableModel) SelectedDay() string {
+	i := m.Cursor()
+	if i < 0 || i >= len(m.keys) {
+		return ""
+	}
+	return m.keys[i]
+}
+
+// SetFilter shows only the days whose stars keep returns true for, or all
+// days when keep is nil. The totals still count the hidden days.
+func (m *TableModel) SetFilter(keep func(stars int) bool) {
+	m.keep = keep
+	m.SetData(m.daily)
+	m.GotoTop()
+}
+
+// SetFormat formats the counts and the days of the cells with number and
+// date, or shows plain digits and 2006-01-02 days when they are nil.
+func (m *TableModel) SetFormat(number func(int) string, date func(day string) string) {
+	m.number, m.date = number, date
+	m.SetData(m.daily)
+}
+
+// SetData sets the daily stargazers.
+func (m *TableModel) SetData(daily map[string]int) {
+	m.daily = daily
+	keys := make([]string, 0, len(daily))
+	var total int
+	for k, count := range daily {
+		keys = append(keys, k)
+		total += count
+	}
+	sort.Strings(keys)
+	columns := m.ColumnNames()
+	rows := make([]table.Row, 0, len(keys))
+	m.keys = make([]string, 0, len(keys))
+	var cumulative int
+	for _, k := range keys {
+		cumulative += daily[k]
+		if m.keep != nil && !m.keep(daily[k]) {
+			continue
+		}
+		row := make(table.Row, len(columns))
+		for j, c := range columns {
+			row[j] = m.cell(c, k, daily, cumulative, total)
+		}
+		rows = append(rows, row)
+		m.keys = append(m.keys, k)
+	}
+	// Newest first.
+	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+		rows[i], rows[j] = rows[j], rows[i]
+		m.keys[i], m.keys[j] = m.keys[j], m.keys[i]
+	}
+	m.SetRows(rows)
+}
+
+// count formats a count of the cells.
+func (m TableModel) count(n int) string {
+	if m.number != nil {
+		return m.number(n)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// cell returns the value of a column for a day with cumulative stargazers
+// out of total.
+func (m TableModel) cell(column, day string, daily map[string]int, cumulative, total int) string {
+	switch column {
+	case ColumnDate:
+		if m.date != nil {
+			return m.date(day)
+		}
+		return day
+	case ColumnStars:
+		return m.count(daily[day])
+	case ColumnTotal:
+		return m.count(cumulative)
+	case ColumnAverage:
+		t, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			return ""
+		}
+		var sum int
+		for i := 0; i < 7; i++ {
+			sum += daily[t.AddDate(0, 0, -i).Format("2006-01-02")]
+		}
+		return fmt.Sprintf("%.1f", float64(sum)/7)
+	case ColumnWeekday:
+		t, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			return ""
+		}
+		return t.Format("Mon")
+	case ColumnPercent:
+		if total == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%.2f%%", float64(daily[day])*100/float64(total))
+	}
+	return ""
+}
+
+// Init implements tea.Model.
+func (m TableModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles DataMsg, tea.WindowSizeMsg, and the table key bindings.
+func (m TableModel) Update(msg tea.Msg) (TableModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case DataMsg:
+		m.SetData(msg)
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.SetWidth(msg.Width)
+		// Leave room for the header.
+		m.SetHeight(msg.Height - 1)
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.Model, cmd = m.Model.Update(msg)
+	return m, cmd
+}
+
+// View renders the table.
+func (m TableModel) View() string {
+	return m.Model.View()
+}
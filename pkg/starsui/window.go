@@ -0,0 +1,108 @@
+package starsui
+
+import (
+	"sort"
+	"time"
+)
+
+// minWindowDays is the narrowest window Zoom goes down to.
+const minWindowDays = 7
+
+// Window returns the values of s labeled between from and to inclusive, and
+// the index of the first one in s. Labels must be sorted, empty bounds are
+// open.
+func (s Series) Window(from, to string) (Series, int) {
+	start, end := 0, len(s.Labels)
+	if from != "" {
+		start = sort.SearchStrings(s.Labels, from)
+	}
+	if to != "" {
+		end = sort.Search(len(s.Labels), func(i int) bool { return s.Labels[i] > to })
+	}
+	if start > end {
+		start = end
+	}
+	return Series{Name: s.Name, Labels: s.Labels[start:end], Values: s.Values[start:end]}, start
+}
+
+// Windowed returns whether the graph is limited to a window of days.
+func (m GraphModel) Windowed() bool {
+	return m.From != "" || m.To != ""
+}
+
+// bounds returns the first and last day of the plotted window, and of the
+// whole history ending today.
+func (m GraphModel) bounds(today string) (from, to, first, last time.Time, ok bool) {
+	if len(m.series.Labels) == 0 {
+		return
+	}
+	first, err := time.Parse("2006-01-02", m.series.Labels[0])
+	if err != nil {
+		return
+	}
+	last, err = time.Parse("2006-01-02", m.series.Labels[len(m.series.Labels)-1])
+	if err != nil {
+		return
+	}
+	if t, err := time.Parse("2006-01-02", today); err == nil && t.After(last) {
+		last = t
+	}
+	from, to = first, last
+	if t, err := time.Parse("2006-01-02", m.From); err == nil {
+		from = t
+	}
+	if t, err := time.Parse("2006-01-02", m.To); err == nil {
+		to = t
+	}
+	return from, to, first, last, true
+}
+
+// Zoom divides the width of the plotted window by factor, keeping its end:
+// 2 halves it and 0.5 doubles it. today, formatted as 2006-01-02, ends the
+// history. The window is removed once it covers the whole history.
+func (m *GraphModel) Zoom(factor float64, today string) {
+	from, to, first, last, ok := m.bounds(today)
+	if !ok || factor <= 0 {
+		return
+	}
+	width := int(float64(daysBetween(from, to)) / factor)
+	if width < minWindowDays {
+		width = minWindowDays
+	}
+	if width >= daysBetween(first, last) {
+		m.From, m.To = "", ""
+		return
+	}
+	from = to.AddDate(0, 0, -width+1)
+	if from.Before(first) {
+		from, to = first, first.AddDate(0, 0, width-1)
+	}
+	m.From, m.To = from.Format("2006-01-02"), to.Format("2006-01-02")
+}
+
+// Pan moves the plotted window by steps times its width, back in time for
+// negative steps, without leaving the history. today is as for Zoom.
+func (m *GraphModel) Pan(steps int, today string) {
+	if !m.Windowed() {
+		return
+	}
+	from, to, first, last, ok := m.bounds(today)
+	if !ok {
+		return
+	}
+	width := daysBetween(from, to)
+	from = from.AddDate(0, 0, steps*width)
+	to = to.AddDate(0, 0, steps*width)
+	if from.Before(first) {
+		from, to = first, first.AddDate(0, 0, width-1)
+	}
+	if to.After(last) {
+		from, to = last.AddDate(0, 0, -width+1), last
+	}
+	m.From, m.To = from.Format("2006-01-02"), to.Format("2006-01-02")
+}
+
+// daysBetween returns the number of days from from to to inclusive.
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours()/24) + 1
+}
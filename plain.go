@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/cli/go-gh/pkg/config"
+	"github.com/cli/go-gh/pkg/tableprinter"
+	"github.com/cli/go-gh/pkg/term"
+	"github.com/google/shlex"
+)
+
+// pagerCommand returns the pager gh is configured with: GH_PAGER, the pager
+// of the gh config file, or PAGER. An empty string or cat disables it.
+func pagerCommand() string {
+	if pager, ok := os.LookupEnv("GH_PAGER"); ok {
+		return pager
+	}
+	if cfg, err := config.Read(); err == nil {
+		if pager, err := cfg.Get([]string{"pager"}); err == nil && pager != "" {
+			return pager
+		}
+	}
+	return os.Getenv("PAGER")
+}
+
+// startPager returns stdout, piped through the gh pager when stdout is a
+// terminal, and a function waiting for the pager to exit once the output is
+// written.
+func startPager() (io.Writer, func() error) {
+	noop := func() error { return nil }
+	pager := pagerCommand()
+	if pager == "" || pager == "cat" || !term.IsTerminal(os.Stdout) {
+		return os.Stdout, noop
+	}
+	args, err := shlex.Split(pager)
+	if err != nil || len(args) == 0 {
+		return os.Stdout, noop
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// Let less show colors and quit when the output fits, like gh.
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+	w, err := cmd.StdinPipe()
+	if err != nil {
+		return os.Stdout, noop
+	}
+	if err := cmd.Start(); err != nil {
+		return os.Stdout, noop
+	}
+	return w, func() error {
+		w.Close()
+		return cmd.Wait()
+	}
+}
+
+// WritePlain writes the stars of each day with new stars and the total as
+// a table, column-aligned with the locale formats on terminals and
+// tab-separated otherwise.
+func WritePlain(w io.Writer, entry *stars.History, isTTY bool, width int) error {
+	t := tableprinter.New(w, isTTY, width)
+	if isTTY {
+		t.AddField("DATE")
+		t.AddField("STARS")
+		t.AddField("TOTAL")
+		t.EndRow()
+	}
+	var total int
+	for _, day := range entry.Stargazers.Keys() {
+		total += entry.Stargazers[day]
+		if isTTY {
+			t.AddField(formatDay(day))
+			t.AddField(formatNumber(entry.Stargazers[day]))
+			t.AddField(formatNumber(total))
+		} else {
+			t.AddField(day)
+			t.AddField(strconv.Itoa(entry.Stargazers[day]))
+			t.AddField(strconv.Itoa(total))
+		}
+		t.EndRow()
+	}
+	if err := t.Render(); err != nil {
+		return err
+	}
+	if total == 0 && isTTY {
+		_, err := fmt.Fprintf(w, "%s has no stargazers.\n", entry.Name)
+		return err
+	}
+	return nil
+}
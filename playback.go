@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/starsui"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	playbackInterval = 100 * time.Millisecond
+	maxPlaybackSpeed = 64
+)
+
+type playbackTickMsg struct{}
+
+// playback animates the cumulative stargazers graph from the first star to
+// now. The speed is the number of data points advanced on every tick.
+type playback struct {
+	active  bool
+	playing bool
+	index   int
+	speed   int
+}
+
+func playbackTick() tea.Cmd {
+	return tea.Tick(playbackInterval, func(time.Time) tea.Msg {
+		return playbackTickMsg{}
+	})
+}
+
+// Active returns whether the playback view is shown, either playing or paused.
+func (p *playback) Active() bool {
+	return p.active
+}
+
+// Toggle starts the playback if it's stopped, otherwise it pauses or resumes
+// it. Resuming a finished playback starts it over.
+func (p *playback) Toggle(total int) tea.Cmd {
+	if !p.active {
+		p.active = true
+		p.index = 0
+		if p.speed == 0 {
+			p.speed = 1
+		}
+	}
+	p.playing = !p.playing
+	if !p.playing {
+		return nil
+	}
+	if p.index >= total-1 {
+		p.index = 0
+	}
+	return playbackTick()
+}
+
+// Advance moves the playback forward and schedules the next tick.
+func (p *playback) Advance(total int) tea.Cmd {
+	if !p.playing {
+		return nil
+	}
+	p.index += p.speed
+	if p.index >= total-1 {
+		p.index = total - 1
+		p.playing = false
+		return nil
+	}
+	return playbackTick()
+}
+
+// Faster doubles the playback speed.
+func (p *playback) Faster() {
+	if p.speed < maxPlaybackSpeed {
+		p.speed *= 2
+	}
+}
+
+// Slower halves the playback speed.
+func (p *playback) Slower() {
+	if p.speed > 1 {
+		p.speed /= 2
+	}
+}
+
+// Clamp keeps the playback position within the total data points after the
+// stargazers are replaced, stopping it if there are none.
+func (p *playback) Clamp(total int) {
+	if total == 0 {
+		p.Stop()
+		return
+	}
+	if p.index > total-1 {
+		p.index = total - 1
+	}
+}
+
+// Stop stops the playback and goes back to the regular graph.
+func (p *playback) Stop() {
+	p.active = false
+	p.playing = false
+	p.index = 0
+}
+
+// View renders the cumulative graph up to the current playback position. The
+// axes are fixed to the full history so the graph grows in place.
+func (p *playback) View(r *Repo, keys []string) string {
+	series := r.graph.Series().Cumulative()
+	total := series.Max()
+	current := series.Values[p.index]
+	for i := p.index + 1; i < len(series.Values); i++ {
+		series.Values[i] = math.NaN()
+	}
+	status := "playing"
+	if !p.playing {
+		status = "paused"
+	}
+	lower := 0.0
+	return starsui.AsciigraphRenderer{}.Render(
+		[]starsui.Series{series},
+		starsui.RenderOptions{
+			Width:  r.graphWidth(),
+			Height: r.height - 1,
+			Caption: fmt.Sprintf("%s %d stargazers on %s (%s %dx)",
+				r.name, int(current), keys[p.index], status, p.speed),
+			Colors:     r.theme.Colors(),
+			LowerBound: &lower,
+			UpperBound: &total,
+			LogScale:   r.graph.LogScale,
+		},
+	)
+}
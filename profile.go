@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// profileSample is a request timed by the profiler.
+type profileSample struct {
+	Endpoint string
+	Start    time.Time
+	// Wait is how long the request waited for a slot of the request budget.
+	Wait      time.Duration
+	Took      time.Duration
+	Status    int
+	Resource  string
+	Remaining int
+	Retry     bool
+}
+
+// profiler times the API requests of a session to tune the fetch
+// concurrency against real repositories.
+type profiler struct {
+	mu      sync.Mutex
+	samples []profileSample
+	// failed holds the endpoints whose last request failed, the next
+	// request of one of them is a retry.
+	failed map[string]bool
+}
+
+// sessionProfile times the requests of the session, its report is written
+// with --profile.
+var sessionProfile = &profiler{failed: make(map[string]bool)}
+
+// profilePath is the report file of --profile.
+var profilePath string
+
+// addProfileFlag adds the --profile flag to flags.
+func addProfileFlag(flags *pflag.FlagSet) {
+	flags.StringVar(&profilePath, "profile", "", "time the API requests and write a report to this file on exit, - for stderr")
+}
+
+// writeProfile writes the profiler report if --profile is set.
+func writeProfile() {
+	if profilePath == "" {
+		return
+	}
+	w := io.Writer(os.Stderr)
+	if profilePath != "-" {
+		f, err := os.Create(profilePath)
+		if err != nil {
+			log.Printf("writing profile: %v", err)
+			return
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := sessionProfile.Report(w); err != nil {
+		log.Printf("writing profile: %v", err)
+	}
+}
+
+// record times a request.
+func (p *profiler) record(req *http.Request, resp *http.Response, start time.Time, wait, took time.Duration) {
+	s := profileSample{
+		Endpoint: req.URL.RequestURI(),
+		Start:    start,
+		Wait:     wait,
+		Took:     took,
+	}
+	if resp != nil {
+		s.Status = resp.StatusCode
+		s.Resource = resp.Header.Get("X-RateLimit-Resource")
+		s.Remaining, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s.Retry = p.failed[s.Endpoint]
+	p.failed[s.Endpoint] = resp == nil || resp.StatusCode >= 400
+	p.samples = append(p.samples, s)
+}
+
+// Report writes the wall time, retries, rate limit consumption, and the
+// timings of the requests.
+func (p *profiler) Report(w io.Writer) error {
+	p.mu.Lock()
+	samples := append([]profileSample(nil), p.samples...)
+	p.mu.Unlock()
+	if len(samples) == 0 {
+		_, err := fmt.Fprintln(w, "No API requests made.")
+		return err
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].Start.Before(samples[j].Start)
+	})
+	first, last := samples[0].Start, samples[0].Start
+	var retries int
+	durations := make([]time.Duration, len(samples))
+	// most and least are the most and least remaining rate limit seen per
+	// resource.
+	most, least := make(map[string]int), make(map[string]int)
+	for i, s := range samples {
+		if end := s.Start.Add(s.Wait + s.Took); end.After(last) {
+			last = end
+		}
+		if s.Retry {
+			retries++
+		}
+		durations[i] = s.Took
+		if s.Resource == "" {
+			continue
+		}
+		if n, ok := most[s.Resource]; !ok || s.Remaining > n {
+			most[s.Resource] = s.Remaining
+		}
+		if n, ok := least[s.Resource]; !ok || s.Remaining < n {
+			least[s.Resource] = s.Remaining
+		}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	percentile := func(p float64) time.Duration {
+		return durations[int(p*float64(len(durations)-1))]
+	}
+	fmt.Fprintf(w, "%d requests in %s, %d retries\n", len(samples), last.Sub(first).Round(time.Millisecond), retries)
+	fmt.Fprintf(w, "request time: min %s, median %s, p95 %s, max %s\n",
+		durations[0].Round(time.Millisecond), percentile(0.5).Round(time.Millisecond),
+		percentile(0.95).Round(time.Millisecond), durations[len(durations)-1].Round(time.Millisecond))
+	resources := make([]string, 0, len(most))
+	for r := range most {
+		resources = append(resources, r)
+	}
+	sort.Strings(resources)
+	for _, r := range resources {
+		// The first response already counts its request.
+		fmt.Fprintf(w, "rate limit %s: %d used, %d remaining\n", r, most[r]-least[r]+1, least[r])
+	}
+	fmt.Fprintln(w)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "START\tWAIT\tTIME\tSTATUS\tENDPOINT")
+	for _, s := range samples {
+		endpoint := s.Endpoint
+		if s.Retry {
+			endpoint += " (retry)"
+		}
+		fmt.Fprintf(tw, "+%s\t%s\t%s\t%d\t%s\n",
+			s.Start.Sub(first).Round(time.Millisecond), s.Wait.Round(time.Millisecond),
+			s.Took.Round(time.Millisecond), s.Status, endpoint)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Metrics fetched while loading a repository.
+const (
+	metricStars      = "stars"
+	metricReleases   = "releases"
+	metricDependents = "dependents"
+	metricDownloads  = "downloads"
+)
+
+// metrics are the metrics that can be enabled. Stars are always fetched.
+var metrics = []string{metricStars, metricReleases, metricDependents, metricDownloads}
+
+// ParseMetrics parses a list of metrics, adding stars if missing.
+func ParseMetrics(names []string) ([]string, error) {
+	parsed := []string{metricStars}
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case metricStars:
+		case metricReleases, metricDependents, metricDownloads:
+			if !contains(parsed, name) {
+				parsed = append(parsed, name)
+			}
+		default:
+			return nil, fmt.Errorf("unknown metric %q, expected %s", name, strings.Join(metrics, ", "))
+		}
+	}
+	return parsed, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// progressBarWidth is the number of cells of a progress bar.
+const progressBarWidth = 30
+
+// loadProgress tracks the pages fetched of each metric while loading. It's
+// updated by the fetching goroutines and read when rendering.
+type loadProgress struct {
+	mu    sync.Mutex
+	names []string
+	done  map[string]int
+	total map[string]int
+}
+
+func newLoadProgress(names []string) *loadProgress {
+	return &loadProgress{
+		names: names,
+		done:  make(map[string]int),
+		total: make(map[string]int),
+	}
+}
+
+// Set records that done pages out of total are fetched for a metric.
+func (p *loadProgress) Set(name string, done, total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done[name] = done
+	p.total[name] = total
+}
+
+// Reset forgets the progress of all metrics.
+func (p *loadProgress) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done = make(map[string]int)
+	p.total = make(map[string]int)
+}
+
+// View renders a progress bar for each metric, or nothing if there is a
+// single metric.
+func (p *loadProgress) View(theme Theme) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.names) < 2 {
+		return ""
+	}
+	lines := make([]string, len(p.names))
+	for i, name := range p.names {
+		done, total := p.done[name], p.total[name]
+		if total == 0 {
+			lines[i] = fmt.Sprintf(" %-10s %s", name, strings.Repeat("░", progressBarWidth))
+			continue
+		}
+		filled := progressBarWidth * done / total
+		if filled > progressBarWidth {
+			filled = progressBarWidth
+		}
+		bar := theme.AccentStyle().Render(strings.Repeat("█", filled)) + strings.Repeat("░", progressBarWidth-filled)
+		lines[i] = fmt.Sprintf(" %-10s %s %d/%d pages", name, bar, done, total)
+	}
+	return strings.Join(lines, "\n")
+}
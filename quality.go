@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// qualitySampleSize is the number of most recent stargazers whose
+	// accounts are checked.
+	qualitySampleSize = 200
+	// qualityClusterSize is the number of sampled accounts created the same
+	// week above which they are flagged.
+	qualityClusterSize = 5
+)
+
+// QualityMsg holds the accounts of the sampled stargazers.
+type QualityMsg struct {
+	Accounts []Account
+	Err      error
+}
+
+// quality flags stargazer accounts that look like star farming: empty
+// accounts and accounts created the same week as many others.
+type quality struct {
+	loading bool
+	loaded  bool
+	sampled int
+	flagged int
+	err     error
+	table   table.Model
+}
+
+func newQuality(theme Theme) quality {
+	return quality{
+		table: table.New(
+			table.WithColumns(
+				[]table.Column{
+					{Title: "Stargazer", Width: 20},
+					{Title: "Created", Width: 10},
+					{Title: "Repos", Width: 6},
+					{Title: "Followers", Width: 9},
+					{Title: "Signals", Width: 30},
+				},
+			),
+			table.WithFocused(true),
+			table.WithStyles(theme.TableStyles()),
+		),
+	}
+}
+
+// SetSize sets the size of the flagged accounts table.
+func (q *quality) SetSize(width, height int) {
+	q.table.SetWidth(width)
+	// Leave room for the summary.
+	q.table.SetHeight(height - 2)
+}
+
+// Load fetches the accounts of the most recent stargazers unless they were
+// already fetched.
+func (q *quality) Load(r *Repo) tea.Cmd {
+	if q.loading || q.loaded || len(r.logins) == 0 {
+		return nil
+	}
+	if r.offline {
+		q.err = errOffline
+		return nil
+	}
+	q.loading = true
+	logins := r.logins
+	if len(logins) > qualitySampleSize {
+		logins = logins[len(logins)-qualitySampleSize:]
+	}
+	client := r.client
+	return func() tea.Msg {
+		accounts, err := FetchAccounts(client, logins)
+		return QualityMsg{Accounts: accounts, Err: err}
+	}
+}
+
+// SetAccounts fills the table with the flagged accounts, newest first.
+func (q *quality) SetAccounts(msg QualityMsg) {
+	q.loading = false
+	q.loaded = true
+	q.sampled = len(msg.Accounts)
+	q.err = msg.Err
+	signals := accountSignals(msg.Accounts)
+	accounts := make([]Account, 0, len(signals))
+	for _, a := range msg.Accounts {
+		if len(signals[a.Login]) > 0 {
+			accounts = append(accounts, a)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].CreatedAt.After(accounts[j].CreatedAt)
+	})
+	q.flagged = len(accounts)
+	rows := make([]table.Row, len(accounts))
+	for i, a := range accounts {
+		rows[i] = table.Row{
+			a.Login,
+			a.CreatedAt.Format("2006-01-02"),
+			fmt.Sprintf("%d", a.PublicRepos),
+			fmt.Sprintf("%d", a.Followers),
+			strings.Join(signals[a.Login], ", "),
+		}
+	}
+	q.table.SetRows(rows)
+}
+
+// accountSignals returns the suspicious patterns of each account, keyed by
+// login.
+func accountSignals(accounts []Account) map[string][]string {
+	weeks := make(map[string]int)
+	for _, a := range accounts {
+		weeks[creationWeek(a.CreatedAt)]++
+	}
+	signals := make(map[string][]string)
+	for _, a := range accounts {
+		if a.PublicRepos == 0 && a.Followers == 0 {
+			signals[a.Login] = append(signals[a.Login], "no repos or followers")
+		}
+		if n := weeks[creationWeek(a.CreatedAt)]; n >= qualityClusterSize {
+			signals[a.Login] = append(signals[a.Login], fmt.Sprintf("created the same week as %d others", n-1))
+		}
+	}
+	return signals
+}
+
+// creationWeek returns the ISO week of t formatted as 2006-W01.
+func creationWeek(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+func (q *quality) View(r *Repo) string {
+	switch {
+	case q.loading:
+		return fmt.Sprintf("\n %s loading stargazer accounts...\n", r.spinner.View())
+	case q.err != nil:
+		return fmt.Sprintf("\n Error: %s", q.err)
+	case len(r.logins) == 0:
+		return "\n No stargazers found.\n"
+	case q.flagged == 0:
+		return fmt.Sprintf("\n None of the %d most recent stargazers look suspicious.\n", q.sampled)
+	}
+	summary := fmt.Sprintf(" %d of the %d most recent stargazers (%.1f%%) look suspicious",
+		q.flagged, q.sampled, float64(q.flagged)/float64(q.sampled)*100)
+	return q.table.View() + "\n" + r.theme.AccentStyle().Render(summary)
+}
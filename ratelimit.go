@@ -0,0 +1,264 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/go-gh/pkg/api"
+)
+
+const (
+	rateLimitPath = "rate_limit"
+	// defaultMaxRequests is the default number of requests in flight, set
+	// with --max-requests.
+	defaultMaxRequests = 16
+)
+
+// maxRequests is the number of requests in flight shared by every client
+// and every metric being fetched, to stay clear of the secondary rate
+// limits, which apply to the token rather than to a client.
+var maxRequests = defaultMaxRequests
+
+var (
+	// requestBudget holds a slot per request in flight. It's sized by the
+	// first client, as slots must be released to the budget they were taken
+	// from.
+	requestBudget     chan struct{}
+	requestBudgetOnce sync.Once
+)
+
+// budget returns the request budget.
+func budget() chan struct{} {
+	requestBudgetOnce.Do(func() {
+		requestBudget = make(chan struct{}, maxRequests)
+	})
+	return requestBudget
+}
+
+// RateLimit is the state of the rate limit of an API resource.
+type RateLimit struct {
+	Limit     int   `json:"limit"`
+	Remaining int   `json:"remaining"`
+	Used      int   `json:"used"`
+	Reset     int64 `json:"reset"`
+}
+
+// rateUsage tracks the rate limits and the requests made by each part of
+// gh-stars during the session.
+type rateUsage struct {
+	mu         sync.Mutex
+	resources  map[string]RateLimit
+	subsystems map[string]int
+}
+
+var sessionUsage = &rateUsage{
+	resources:  make(map[string]RateLimit),
+	subsystems: make(map[string]int),
+}
+
+// record counts a request and stores the rate limit of its response.
+func (u *rateUsage) record(req *http.Request, resp *http.Response) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.subsystems[subsystem(req.URL.Path)]++
+	if resp == nil {
+		return
+	}
+	resource := resp.Header.Get("X-RateLimit-Resource")
+	if resource == "" {
+		return
+	}
+	var rl RateLimit
+	rl.Limit, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	rl.Remaining, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	rl.Used, _ = strconv.Atoi(resp.Header.Get("X-RateLimit-Used"))
+	rl.Reset, _ = strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	u.resources[resource] = rl
+}
+
+// setResources replaces the rate limits of all resources.
+func (u *rateUsage) setResources(resources map[string]RateLimit) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for name, rl := range resources {
+		u.resources[name] = rl
+	}
+}
+
+// snapshot returns a copy of the rate limits and of the requests per
+// subsystem.
+func (u *rateUsage) snapshot() (map[string]RateLimit, map[string]int) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	resources := make(map[string]RateLimit, len(u.resources))
+	for k, v := range u.resources {
+		resources[k] = v
+	}
+	subsystems := make(map[string]int, len(u.subsystems))
+	for k, v := range u.subsystems {
+		subsystems[k] = v
+	}
+	return resources, subsystems
+}
+
+// subsystem returns the part of gh-stars that requests an API path.
+func subsystem(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case len(parts) > 0 && parts[0] == "graphql":
+		return "graphql"
+	case len(parts) > 0 && parts[0] == "search":
+		return "search"
+	case len(parts) > 0 && parts[0] == rateLimitPath:
+		return "rate limit"
+	case len(parts) >= 3 && parts[0] == "users":
+		return parts[2]
+	case len(parts) >= 4 && parts[0] == "repos":
+		if parts[3] == "stats" && len(parts) > 4 {
+			return parts[4]
+		}
+		return parts[3]
+	case len(parts) >= 3 && parts[0] == "repos":
+		return "repository"
+	default:
+		return "other"
+	}
+}
+
+// countingTransport records the requests and rate limits of the API, times
+// them, and writes them to the audit log. Requests wait for a slot of the
+// shared request budget.
+type countingTransport struct {
+	base http.RoundTripper
+}
+
+func (t countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	b := budget()
+	b <- struct{}{}
+	sent := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	<-b
+	sessionProfile.record(req, resp, start, sent.Sub(start), time.Since(sent))
+	sessionUsage.record(req, resp)
+	audit(req, resp)
+	return resp, err
+}
+
+// RateLimitMsg holds the rate limits of all resources.
+type RateLimitMsg struct {
+	Resources map[string]RateLimit
+	Err       error
+}
+
+// rateTickMsg updates the countdown of the rate limits panel unless it was
+// hidden or shown again since.
+type rateTickMsg struct {
+	id int
+}
+
+func rateTick(id int) tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return rateTickMsg{id: id}
+	})
+}
+
+// rateLimits shows the rate limits with a countdown to their reset.
+type rateLimits struct {
+	active bool
+	err    error
+	// tickID tells the ticks of the panel shown last from the ones of
+	// before, so toggling it doesn't start another chain of ticks.
+	tickID int
+}
+
+// Toggle shows the rate limits panel and refreshes the rate limits, or
+// hides it.
+func (rl *rateLimits) Toggle(client api.RESTClient, offline bool) tea.Cmd {
+	rl.active = !rl.active
+	rl.tickID++
+	if !rl.active || offline {
+		return nil
+	}
+	return tea.Batch(rateTick(rl.tickID), func() tea.Msg {
+		var result struct {
+			Resources map[string]RateLimit `json:"resources"`
+		}
+		err := client.Get(rateLimitPath, &result)
+		return RateLimitMsg{Resources: result.Resources, Err: err}
+	})
+}
+
+// Stop hides the rate limits panel.
+func (rl *rateLimits) Stop() {
+	rl.active = false
+}
+
+// SetLimits stores the fetched rate limits.
+func (rl *rateLimits) SetLimits(msg RateLimitMsg) {
+	rl.err = msg.Err
+	if msg.Err == nil {
+		sessionUsage.setResources(msg.Resources)
+	}
+}
+
+// Tick schedules the next countdown update while the panel is shown,
+// dropping the ticks scheduled before it was last toggled.
+func (rl *rateLimits) Tick(msg rateTickMsg) tea.Cmd {
+	if !rl.active || msg.id != rl.tickID {
+		return nil
+	}
+	return rateTick(rl.tickID)
+}
+
+func (rl *rateLimits) View(r *Repo) string {
+	resources, subsystems := sessionUsage.snapshot()
+	lines := []string{"", " API rate limits", ""}
+	if r.offline {
+		lines = append(lines, " No requests are made offline.")
+	}
+	if rl.err != nil {
+		lines = append(lines, fmt.Sprintf(" Error: %s", rl.err))
+	}
+	lines = append(lines, fmt.Sprintf(" %-10s %8s %10s %8s  %s", "Resource", "Used", "Remaining", "Limit", "Resets in"))
+	for _, name := range []string{"core", "graphql", "search"} {
+		res, ok := resources[name]
+		if !ok {
+			lines = append(lines, fmt.Sprintf(" %-10s %8s %10s %8s  %s", name, "-", "-", "-", "-"))
+			continue
+		}
+		resets := time.Until(time.Unix(res.Reset, 0)).Round(time.Second)
+		if resets < 0 {
+			resets = 0
+		}
+		line := fmt.Sprintf(" %-10s %8d %10d %8d  %s", name, res.Used, res.Remaining, res.Limit, resets)
+		if res.Remaining == 0 {
+			line = r.theme.AccentStyle().Render(line + "  requests are paused until the reset")
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", " Requests this session", "")
+	names := make([]string, 0, len(subsystems))
+	for name := range subsystems {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if subsystems[names[i]] != subsystems[names[j]] {
+			return subsystems[names[i]] > subsystems[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) == 0 {
+		lines = append(lines, " None yet.")
+	}
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf(" %-14s %6d", name, subsystems[name]))
+	}
+	return strings.Join(lines, "\n")
+}
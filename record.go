@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/starsui"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/pflag"
+)
+
+// Snapshot is the state of a repository at a time, recorded by gh stars
+// record.
+type Snapshot struct {
+	Repo       string    `json:"repo"`
+	Time       time.Time `json:"time"`
+	Stars      int       `json:"stars"`
+	Forks      int       `json:"forks"`
+	Watchers   int       `json:"watchers"`
+	OpenIssues int       `json:"open_issues"`
+}
+
+// snapshotMetrics are the recorded metrics, in the order they are plotted.
+var snapshotMetrics = []string{"stars", "forks", "watchers", "open issues"}
+
+// values returns the metrics of the snapshot in the order of
+// snapshotMetrics.
+func (s Snapshot) values() []int {
+	return []int{s.Stars, s.Forks, s.Watchers, s.OpenIssues}
+}
+
+// AppendSnapshot appends a snapshot to a database file. The file holds a
+// JSON snapshot per line, so it can be committed to a repository and grow
+// without conflicts.
+func AppendSnapshot(path string, s Snapshot) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// LoadSnapshots reads the snapshots of a repository from a database file in
+// the order they were recorded. An empty name reads all of them.
+func LoadSnapshots(path, name string) ([]Snapshot, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no snapshots recorded in %s, run `gh stars record --db %s` first", path, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	snapshots := make([]Snapshot, 0)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var s Snapshot
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if name == "" || strings.EqualFold(s.Repo, name) {
+			snapshots = append(snapshots, s)
+		}
+	}
+	return snapshots, scanner.Err()
+}
+
+// snapshotSeries returns a series per metric with the last snapshot of each
+// day.
+func snapshotSeries(snapshots []Snapshot) []starsui.Series {
+	days := make([]string, 0)
+	last := make(map[string]Snapshot)
+	for _, s := range snapshots {
+		day := dayOf(s.Time)
+		if _, ok := last[day]; !ok {
+			days = append(days, day)
+		}
+		last[day] = s
+	}
+	series := make([]starsui.Series, len(snapshotMetrics))
+	for i, metric := range snapshotMetrics {
+		series[i] = starsui.Series{Name: metric, Labels: days, Values: make([]float64, len(days))}
+		for d, day := range days {
+			series[i].Values[d] = float64(last[day].values()[i])
+		}
+	}
+	return series
+}
+
+// SnapshotGraph plots the recorded snapshots of a repository. Metrics are
+// toggled with the number keys.
+type SnapshotGraph struct {
+	name      string
+	snapshots []Snapshot
+	hidden    []bool
+	width     int
+	height    int
+	opts      Options
+}
+
+func NewSnapshotGraph(path, name string, opts Options) (*SnapshotGraph, error) {
+	if name == "" {
+		return nil, errors.New("no repository given to plot the snapshots of")
+	}
+	snapshots, err := LoadSnapshots(path, name)
+	if err != nil {
+		return nil, err
+	}
+	return &SnapshotGraph{
+		name:      name,
+		snapshots: snapshots,
+		hidden:    make([]bool, len(snapshotMetrics)),
+		opts:      opts,
+	}, nil
+}
+
+func (g *SnapshotGraph) Init() tea.Cmd {
+	return nil
+}
+
+func (g *SnapshotGraph) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		g.width = msg.Width
+		g.height = msg.Height
+	case tea.KeyMsg:
+		switch k := msg.String(); k {
+		case "q", "ctrl+c":
+			return g, tea.Quit
+		case "1", "2", "3", "4":
+			i := int(k[0] - '1')
+			g.hidden[i] = !g.hidden[i]
+		}
+	}
+	return g, nil
+}
+
+func (g *SnapshotGraph) View() string {
+	if v, ok := tooSmall(g.width, g.height); ok {
+		return v
+	}
+	v := g.render()
+	if g.opts.ASCII {
+		v = asciiReplacer.Replace(v)
+	}
+	return v
+}
+
+func (g *SnapshotGraph) render() string {
+	if len(g.snapshots) == 0 {
+		return fmt.Sprintf("\n No snapshots of %s recorded.\n", g.name)
+	}
+	all := snapshotSeries(g.snapshots)
+	series := make([]starsui.Series, 0, len(all))
+	labels := make([]string, len(all))
+	latest := g.snapshots[len(g.snapshots)-1].values()
+	for i, s := range all {
+		labels[i] = fmt.Sprintf("%s %d", s.Name, latest[i])
+		if !g.hidden[i] {
+			series = append(series, s)
+		}
+	}
+	if len(series) == 0 {
+		return "\n All metrics are hidden, press 1-4 to show them.\n\n " + g.opts.Theme.ToggleLegend(labels, g.hidden)
+	}
+	renderer := g.opts.Renderer
+	if renderer == nil {
+		renderer = starsui.AsciigraphRenderer{}
+	}
+	graph := renderer.Render(series, starsui.RenderOptions{
+		Width:    g.width,
+		Height:   g.height - 2,
+		Caption:  fmt.Sprintf("%s snapshots since %s", g.name, all[0].Labels[0]),
+		Colors:   g.opts.Theme.VisibleColors(g.hidden),
+		LogScale: g.opts.LogScale,
+	})
+	return graph + "\n " + g.opts.Theme.ToggleLegend(labels, g.hidden)
+}
+
+func runRecord(args []string) {
+	flags := pflag.NewFlagSet("record", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gh stars record [repository]... [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Append a timestamped snapshot of the stars, forks, watchers, and open issues\n")
+		fmt.Fprintf(os.Stderr, "of repositories to a database file, e.g. from a nightly GitHub Action. Plot\n")
+		fmt.Fprintf(os.Stderr, "them with gh stars --snapshots.\n\n")
+		flags.PrintDefaults()
+	}
+	db := flags.String("db", "stars.db", "database file the snapshots are appended to, a JSON snapshot per line")
+	addTokenFlag(flags)
+	addMaxRequestsFlag(flags)
+	flags.Parse(args)
+	repos := flags.Args()
+	if len(repos) == 0 {
+		if repo := currentRepo(); repo != "" {
+			repos = []string{repo}
+		}
+	}
+	if len(repos) == 0 {
+		flags.Usage()
+		os.Exit(1)
+	}
+	client, err := NewClient()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	now := time.Now().UTC()
+	for _, name := range repos {
+		repo, err := FetchRepo(client, name)
+		if err != nil {
+			log.Fatalln(classifyError(name, err))
+		}
+		s := Snapshot{
+			Repo:       canonicalName(name, repo),
+			Time:       now,
+			Stars:      repo.StargazersCount,
+			Forks:      repo.ForksCount,
+			Watchers:   repo.SubscribersCount,
+			OpenIssues: repo.OpenIssuesCount,
+		}
+		if err := AppendSnapshot(*db, s); err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Fprintf(os.Stderr, "%s: %d stars, %d forks, %d watchers, %d open issues\n", s.Repo, s.Stars, s.Forks, s.Watchers, s.OpenIssues)
+	}
+}
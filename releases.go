@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/go-gh/pkg/api"
+)
+
+const (
+	releasesPath = "repos/%s/releases"
+	// maxReleasePages caps the number of release pages to fetch.
+	maxReleasePages = 10
+)
+
+// Release is a GitHub release.
+type Release struct {
+	TagName     string    `json:"tag_name"`
+	CreatedAt   time.Time `json:"created_at"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// Date returns the day the release was published.
+func (r Release) Date() string {
+	if r.PublishedAt.IsZero() {
+		return r.CreatedAt.Format("2006-01-02")
+	}
+	return r.PublishedAt.Format("2006-01-02")
+}
+
+// ReleasesMsg holds the releases of a repository.
+type ReleasesMsg struct {
+	Releases []Release
+	Err      error
+}
+
+// FetchReleases fetches the releases of a repository sorted by date.
+func FetchReleases(client api.RESTClient, name string) ([]Release, error) {
+	return fetchReleases(client, name, nil)
+}
+
+// fetchReleases fetches the releases of a repository, calling progress when
+// set with the number of pages fetched and their total after each page.
+func fetchReleases(client api.RESTClient, name string, progress func(done, total int)) ([]Release, error) {
+	releases := make([]Release, 0)
+	p := stars.NewPaginator(client, fmt.Sprintf(releasesPath, name), stars.PerPage)
+	for page := 1; page <= maxReleasePages; page++ {
+		result := make([]Release, 0)
+		ok, err := p.Next(&result)
+		if err != nil {
+			return nil, fmt.Errorf("Error fetching releases page %d: %w", page, err)
+		}
+		if !ok {
+			break
+		}
+		releases = append(releases, result...)
+		if progress != nil {
+			total := p.Last()
+			if total < page {
+				total = page
+			}
+			if total > maxReleasePages {
+				total = maxReleasePages
+			}
+			progress(page, total)
+		}
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].Date() < releases[j].Date()
+	})
+	return releases, nil
+}
+
+// releases overlays the releases of the repository on the graph.
+type releases struct {
+	active  bool
+	loading bool
+	loaded  bool
+	list    []Release
+	err     error
+}
+
+// Active returns whether releases are shown.
+func (rl *releases) Active() bool {
+	return rl.active
+}
+
+// Toggle shows or hides the releases, fetching them the first time.
+func (rl *releases) Toggle(r *Repo) tea.Cmd {
+	rl.active = !rl.active
+	if !rl.active || rl.loading || rl.loaded {
+		return nil
+	}
+	if r.offline {
+		rl.err = errOffline
+		return nil
+	}
+	rl.loading = true
+	client, name := r.client, r.name
+	return func() tea.Msg {
+		list, err := FetchReleases(client, name)
+		return ReleasesMsg{Releases: list, Err: err}
+	}
+}
+
+// Preload fetches the releases while the stargazers are loading, reporting
+// the pages fetched to progress, and shows them once loaded.
+func (rl *releases) Preload(r *Repo, progress *loadProgress) tea.Cmd {
+	rl.active = true
+	rl.loading = true
+	client, name := r.client, r.name
+	return func() tea.Msg {
+		list, err := fetchReleases(client, name, func(done, total int) {
+			progress.Set(metricReleases, done, total)
+		})
+		return ReleasesMsg{Releases: list, Err: err}
+	}
+}
+
+// SetReleases stores the fetched releases.
+func (rl *releases) SetReleases(msg ReleasesMsg) {
+	rl.loading = false
+	rl.loaded = true
+	rl.list = msg.Releases
+	rl.err = msg.Err
+}
+
+// View renders a marker under each release and a legend of the most recent
+// ones.
+func (rl *releases) View(r *Repo, keys []string) string {
+	switch {
+	case rl.loading:
+		return fmt.Sprintf("\n %s loading releases...", r.spinner.View())
+	case rl.err != nil:
+		return fmt.Sprintf("\n Error: %s", rl.err)
+	case len(rl.list) == 0:
+		return "\n No releases found."
+	}
+	dates := make([]string, len(rl.list))
+	for i, rel := range rl.list {
+		dates[i] = rel.Date()
+	}
+	legend := make([]string, 0)
+	for i := len(rl.list) - 1; i >= 0 && len(legend) < 5; i-- {
+		legend = append(legend, fmt.Sprintf("%s (%s)", rl.list[i].TagName, dates[i]))
+	}
+	return markerLine(r, keys, dates, "◆") + "\n" +
+		fmt.Sprintf(" ◆ %d releases, latest: %s", len(rl.list), strings.Join(legend, ", "))
+}
+
+// markerLine returns a line with mark under the graph column of each date.
+// Dates without stargazers are placed on the next day that has some. The
+// line is empty with --a11y, where the legends list the dates.
+func markerLine(r *Repo, keys []string, dates []string, mark string) string {
+	if r.a11y {
+		return ""
+	}
+	cols := make(map[int]bool)
+	for _, date := range dates {
+		i := sort.SearchStrings(keys, date)
+		if i == len(keys) {
+			continue
+		}
+		cols[r.graph.Column(i)] = true
+	}
+	var b strings.Builder
+	for col := 0; col < r.graphWidth(); col++ {
+		if cols[col] {
+			b.WriteString(mark)
+		} else {
+			b.WriteString(" ")
+		}
+	}
+	return r.theme.AccentStyle().Render(strings.TrimRight(b.String(), " "))
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/cli/go-gh"
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/spf13/pflag"
+)
+
+const (
+	issuesPath        = "repos/%s/issues"
+	issuePath         = "repos/%s/issues/%d"
+	issueCommentsPath = "repos/%s/issues/%d/comments"
+	// reportLabel marks the issue holding the stargazers reports.
+	reportLabel = "stargazers-report"
+)
+
+// Issue is a GitHub issue.
+type Issue struct {
+	Number  int    `json:"number"`
+	NodeID  string `json:"node_id"`
+	HTMLURL string `json:"html_url"`
+}
+
+func runReport(args []string) {
+	flags := pflag.NewFlagSet("report", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gh stars report <repository> [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Print a Markdown report of the repository stargazers.\n\n")
+		flags.PrintDefaults()
+	}
+	asIssue := flags.Bool("as-issue", false, "post the report to a pinned issue instead of printing it")
+	target := flags.String("repo", "", "repository of the report issue, defaults to the reported repository")
+	publishGist := flags.Bool("publish-gist", false, "upload the report to a secret gist and print its URL")
+	public := flags.Bool("public", false, "make the gist public")
+	addTokenFlag(flags)
+	addMaxRequestsFlag(flags)
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(1)
+	}
+	client, err := NewClient()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	entry, err := FetchHistory(client, flags.Arg(0))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	report := Report(entry, time.Now())
+	if *publishGist {
+		gist, err := PublishGist(client, "stargazers-report.md",
+			fmt.Sprintf("%s stargazers report", entry.Name), report, *public)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Println(gist.HTMLURL)
+	}
+	if !*asIssue {
+		if !*publishGist {
+			fmt.Print(report)
+		}
+		return
+	}
+	if *target == "" {
+		*target = entry.Name
+	}
+	issue, err := postReport(client, *target, report)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(issue.HTMLURL)
+}
+
+// Report returns a Markdown report of the stargazers gained in the week and
+// the month before now.
+func Report(entry *stars.History, now time.Time) string {
+	gained := func(days int) int {
+		since := now.AddDate(0, 0, -days).Format("2006-01-02")
+		var n int
+		for day, count := range entry.Stargazers {
+			if day > since {
+				n += count
+			}
+		}
+		return n
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s stargazers report for %s\n\n", entry.Name, now.Format("2006-01-02"))
+	fmt.Fprintf(&b, "| Period | Stars |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Total | %d |\n", entry.Stars)
+	fmt.Fprintf(&b, "| Last 7 days | +%d |\n", gained(7))
+	fmt.Fprintf(&b, "| Last 30 days | +%d |\n", gained(30))
+
+	since := now.AddDate(0, 0, -7).Format("2006-01-02")
+	days := make([]string, 0)
+	for day := range entry.Stargazers {
+		if day > since {
+			days = append(days, day)
+		}
+	}
+	if alerts := unstarAlerts(entry.Unstars, now); len(alerts) > 0 {
+		fmt.Fprintf(&b, "\n> **Unstar wave:** more than %d stargazers were lost in a day.\n\n| Date | Lost |\n|---|---|\n", unstarAlertThreshold)
+		for _, day := range alerts {
+			fmt.Fprintf(&b, "| %s | %d |\n", day, entry.Unstars[day])
+		}
+	}
+	if len(days) > 0 {
+		sort.Strings(days)
+		fmt.Fprintf(&b, "\n### This week\n\n| Date | Stars |\n|---|---|\n")
+		for _, day := range days {
+			fmt.Fprintf(&b, "| %s | %d |\n", day, entry.Stargazers[day])
+		}
+	}
+	return b.String()
+}
+
+// postReport updates the report issue of a repository with report and adds
+// it as a comment to keep the history of reports. The issue is created and
+// pinned if it doesn't exist.
+func postReport(client api.RESTClient, repo string, report string) (*Issue, error) {
+	issues := make([]Issue, 0)
+	path := fmt.Sprintf(issuesPath+"?state=open&labels=%s", repo, reportLabel)
+	if err := client.Get(path, &issues); err != nil {
+		return nil, err
+	}
+	if len(issues) == 0 {
+		var issue Issue
+		body, err := jsonBody(map[string]interface{}{
+			"title":  "Stargazers report",
+			"body":   report,
+			"labels": []string{reportLabel},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := client.Post(fmt.Sprintf(issuesPath, repo), body, &issue); err != nil {
+			return nil, err
+		}
+		if err := pinIssue(issue.NodeID); err != nil {
+			log.Printf("pinning issue #%d: %v", issue.Number, err)
+		}
+		return &issue, nil
+	}
+	issue := issues[0]
+	body, err := jsonBody(map[string]interface{}{"body": report})
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Patch(fmt.Sprintf(issuePath, repo, issue.Number), body, nil); err != nil {
+		return nil, err
+	}
+	body, err = jsonBody(map[string]interface{}{"body": report})
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Post(fmt.Sprintf(issueCommentsPath, repo, issue.Number), body, nil); err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+// pinIssue pins an issue to its repository. Pinning is only available
+// through the GraphQL API.
+func pinIssue(id string) error {
+	client, err := gh.GQLClient(clientOptions())
+	if err != nil {
+		return err
+	}
+	return client.Do(`mutation($id: ID!) { pinIssue(input: {issueId: $id}) { issue { id } } }`,
+		map[string]interface{}{"id": id}, nil)
+}
+
+func jsonBody(v interface{}) (*bytes.Buffer, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewBuffer(data), nil
+}
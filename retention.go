@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// retentionMonths is the number of recent months of the churn chart.
+const retentionMonths = 6
+
+// RetentionMsg holds the stargazers found missing by the cached fetches.
+type RetentionMsg struct {
+	Unstarred []stars.Unstar
+	Err       error
+}
+
+// retention lists who unstarred the repository, found by comparing the
+// stargazers of each fetch with the previous one, and charts the monthly
+// churn.
+type retention struct {
+	loading bool
+	loaded  bool
+	// unstarred is the number of stargazers listed in the table.
+	unstarred int
+	err       error
+	table     table.Model
+}
+
+func newRetention(theme Theme) retention {
+	return retention{
+		table: table.New(
+			table.WithColumns(
+				[]table.Column{
+					{Title: "Stargazer", Width: 20},
+					{Title: "Unstarred between", Width: 25},
+				},
+			),
+			table.WithFocused(true),
+			table.WithStyles(theme.TableStyles()),
+		),
+	}
+}
+
+// SetSize sets the size of the unstarred stargazers table.
+func (t *retention) SetSize(width, height int) {
+	t.table.SetWidth(width)
+	// Leave room for the churn chart and the note.
+	t.table.SetHeight(height - retentionMonths - 4)
+}
+
+// Load reads the unstarred stargazers of the cached history unless they were
+// already read.
+func (t *retention) Load(r *Repo) tea.Cmd {
+	if t.loading || t.loaded {
+		return nil
+	}
+	t.loading = true
+	name := r.name
+	return func() tea.Msg {
+		entry, err := LoadCache(name)
+		if err != nil || entry == nil {
+			return RetentionMsg{Err: err}
+		}
+		return RetentionMsg{Unstarred: entry.Unstarred}
+	}
+}
+
+// SetUnstarred fills the table with the unstarred stargazers, most recent
+// first.
+func (t *retention) SetUnstarred(msg RetentionMsg) {
+	t.loading = false
+	t.loaded = true
+	t.err = msg.Err
+	t.unstarred = len(msg.Unstarred)
+	rows := make([]table.Row, 0, len(msg.Unstarred))
+	for i := len(msg.Unstarred) - 1; i >= 0; i-- {
+		u := msg.Unstarred[i]
+		rows = append(rows, table.Row{u.Login, fmt.Sprintf("%s and %s", dayOf(u.After), dayOf(u.Before))})
+	}
+	t.table.SetRows(rows)
+}
+
+// monthlyCounts returns the last n months up to now and the sum of the
+// daily counts during each of them, like the stargazers lost.
+func monthlyCounts(daily map[string]int, now time.Time, n int) ([]string, []int) {
+	months := make([]string, n)
+	counts := make([]int, n)
+	index := make(map[string]int, n)
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for i := range months {
+		months[i] = start.AddDate(0, i-n+1, 0).Format("2006-01")
+		index[months[i]] = i
+	}
+	for day, count := range daily {
+		if i, ok := index[day[:7]]; ok {
+			counts[i] += count
+		}
+	}
+	return months, counts
+}
+
+func (t *retention) View(r *Repo) string {
+	switch {
+	case t.loading:
+		return fmt.Sprintf("\n %s loading unstars...\n", r.spinner.View())
+	case t.err != nil:
+		return fmt.Sprintf("\n Error: %s", t.err)
+	case len(r.unstars) == 0 && t.unstarred == 0:
+		return "\n No unstars found yet, they're found by comparing the stargazers of each fetch with the previous one.\n"
+	}
+	months, counts := monthlyCounts(r.unstars, time.Now().In(timeZone), retentionMonths)
+	v := "\n"
+	for _, line := range barChart(r.theme, months, counts, r.width) {
+		v += line + "\n"
+	}
+	note := fmt.Sprintf(" %d stargazers found missing between fetches, churn of the last %d months", t.unstarred, retentionMonths)
+	return v + "\n" + t.table.View() + "\n" + r.theme.AccentStyle().Render(note)
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// toastDuration is how long a toast stays on screen.
+const toastDuration = 3 * time.Second
+
+// screenshotMsg reports the files written by a screenshot.
+type screenshotMsg struct {
+	paths []string
+	err   error
+}
+
+// toastTimeoutMsg hides the toast with the same id.
+type toastTimeoutMsg int
+
+// toast is a short message shown over the last line of the view.
+type toast struct {
+	text string
+	id   int
+}
+
+// Show shows text and schedules hiding it.
+func (t *toast) Show(text string) tea.Cmd {
+	t.text = text
+	t.id++
+	id := t.id
+	return tea.Tick(toastDuration, func(time.Time) tea.Msg {
+		return toastTimeoutMsg(id)
+	})
+}
+
+// Hide hides the toast unless a newer one was shown since.
+func (t *toast) Hide(id toastTimeoutMsg) {
+	if int(id) == t.id {
+		t.text = ""
+	}
+}
+
+// View replaces the last line of v with the toast.
+func (t *toast) View(v string, theme Theme) string {
+	if t.text == "" {
+		return v
+	}
+	if i := strings.LastIndex(v, "\n"); i >= 0 {
+		v = v[:i+1]
+	} else {
+		v = ""
+	}
+	return v + theme.AccentStyle().Render(" "+t.text)
+}
+
+// screenshot writes frame to timestamped ANSI text and HTML files in the
+// cache directory, and renders it to a PNG file too if withPNG is set.
+func screenshot(name string, frame string, withPNG bool) tea.Cmd {
+	return func() tea.Msg {
+		dir, err := cacheDir()
+		if err != nil {
+			return screenshotMsg{err: err}
+		}
+		dir = filepath.Join(dir, "screenshots")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return screenshotMsg{err: err}
+		}
+		base := filepath.Join(dir, fmt.Sprintf("%s-%s",
+			strings.ReplaceAll(name, "/", "_"), time.Now().Format("20060102-150405")))
+		paths := []string{base + ".ans", base + ".html"}
+		if err := os.WriteFile(paths[0], []byte(frame), 0o644); err != nil {
+			return screenshotMsg{err: err}
+		}
+		if err := os.WriteFile(paths[1], []byte(RenderHTML(name, frame)), 0o644); err != nil {
+			return screenshotMsg{err: err}
+		}
+		if withPNG {
+			path := base + ".png"
+			f, err := os.Create(path)
+			if err != nil {
+				return screenshotMsg{err: err}
+			}
+			err = png.Encode(f, RenderANSI(frame))
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return screenshotMsg{err: err}
+			}
+			paths = append(paths, path)
+		}
+		return screenshotMsg{paths: paths}
+	}
+}
+
+var sgrRegexp = regexp.MustCompile(`\x1b\[([0-9;]*)([A-Za-z])`)
+
+// ansi16 are the RGB values of the 16 basic ANSI colors.
+var ansi16 = [16]color.RGBA{
+	{0x00, 0x00, 0x00, 0xff}, {0xcd, 0x00, 0x00, 0xff}, {0x00, 0xcd, 0x00, 0xff}, {0xcd, 0xcd, 0x00, 0xff},
+	{0x00, 0x00, 0xee, 0xff}, {0xcd, 0x00, 0xcd, 0xff}, {0x00, 0xcd, 0xcd, 0xff}, {0xe5, 0xe5, 0xe5, 0xff},
+	{0x7f, 0x7f, 0x7f, 0xff}, {0xff, 0x00, 0x00, 0xff}, {0x00, 0xff, 0x00, 0xff}, {0xff, 0xff, 0x00, 0xff},
+	{0x5c, 0x5c, 0xff, 0xff}, {0xff, 0x00, 0xff, 0xff}, {0x00, 0xff, 0xff, 0xff}, {0xff, 0xff, 0xff, 0xff},
+}
+
+var (
+	screenshotBackground = color.RGBA{0x1e, 0x1e, 0x1e, 0xff}
+	screenshotForeground = color.RGBA{0xe5, 0xe5, 0xe5, 0xff}
+)
+
+// ansi256 returns the RGB value of an ANSI 256 color.
+func ansi256(n int) color.RGBA {
+	switch {
+	case n < 16:
+		return ansi16[n]
+	case n < 232:
+		levels := [6]uint8{0, 95, 135, 175, 215, 255}
+		n -= 16
+		return color.RGBA{levels[n/36], levels[n/6%6], levels[n%6], 0xff}
+	default:
+		gray := uint8(8 + 10*(n-232))
+		return color.RGBA{gray, gray, gray, 0xff}
+	}
+}
+
+// sgrColor applies the foreground color changes of SGR parameters.
+func sgrColor(params string, fg color.RGBA) color.RGBA {
+	codes := strings.Split(params, ";")
+	for i := 0; i < len(codes); i++ {
+		n, _ := strconv.Atoi(codes[i])
+		switch {
+		case n == 0 || n == 39:
+			fg = screenshotForeground
+		case n >= 30 && n <= 37:
+			fg = ansi16[n-30]
+		case n >= 90 && n <= 97:
+			fg = ansi16[n-90+8]
+		case n == 38 && i+2 < len(codes) && codes[i+1] == "5":
+			c, _ := strconv.Atoi(codes[i+2])
+			fg = ansi256(c)
+			i += 2
+		case n == 38 && i+4 < len(codes) && codes[i+1] == "2":
+			r, _ := strconv.Atoi(codes[i+2])
+			g, _ := strconv.Atoi(codes[i+3])
+			b, _ := strconv.Atoi(codes[i+4])
+			fg = color.RGBA{uint8(r), uint8(g), uint8(b), 0xff}
+			i += 4
+		case n == 48 && i+1 < len(codes) && codes[i+1] == "5":
+			// Skip background colors.
+			i += 2
+		case n == 48 && i+1 < len(codes) && codes[i+1] == "2":
+			i += 4
+		}
+	}
+	return fg
+}
+
+// boxArms are the arms of box drawing characters: left, right, up, down.
+var boxArms = map[rune][4]bool{
+	'─': {true, true, false, false},
+	'│': {false, false, true, true},
+	'╭': {false, true, false, true},
+	'╮': {true, false, false, true},
+	'╰': {false, true, true, false},
+	'╯': {true, false, true, false},
+	'┌': {false, true, false, true},
+	'┐': {true, false, false, true},
+	'└': {false, true, true, false},
+	'┘': {true, false, true, false},
+	'┤': {true, false, true, true},
+	'├': {false, true, true, true},
+	'┬': {true, true, false, true},
+	'┴': {true, true, true, false},
+	'┼': {true, true, true, true},
+	'╴': {true, false, false, false},
+	'╶': {false, true, false, false},
+}
+
+// RenderANSI renders ANSI text to an image with a fixed-size bitmap font.
+// Box drawing and block characters are drawn, other characters outside of
+// ASCII are replaced with their ASCII equivalent.
+func RenderANSI(s string) image.Image {
+	face := basicfont.Face7x13
+	cw, ch := face.Advance, face.Height
+	lines := strings.Split(s, "\n")
+	cols := 0
+	for _, line := range lines {
+		if n := utf8.RuneCountInString(sgrRegexp.ReplaceAllString(line, "")); n > cols {
+			cols = n
+		}
+	}
+	img := image.NewRGBA(image.Rect(0, 0, cols*cw, len(lines)*ch))
+	draw.Draw(img, img.Bounds(), image.NewUniform(screenshotBackground), image.Point{}, draw.Src)
+	fg := screenshotForeground
+	for row, line := range lines {
+		col := 0
+		for len(line) > 0 {
+			if loc := sgrRegexp.FindStringSubmatchIndex(line); loc != nil && loc[0] == 0 {
+				if line[loc[4]:loc[5]] == "m" {
+					fg = sgrColor(line[loc[2]:loc[3]], fg)
+				}
+				line = line[loc[1]:]
+				continue
+			}
+			r, size := utf8.DecodeRuneInString(line)
+			line = line[size:]
+			x, y := col*cw, row*ch
+			col++
+			src := image.NewUniform(fg)
+			if arms, ok := boxArms[r]; ok {
+				cx, cy := x+cw/2, y+ch/2
+				if arms[0] {
+					draw.Draw(img, image.Rect(x, cy, cx+1, cy+1), src, image.Point{}, draw.Src)
+				}
+				if arms[1] {
+					draw.Draw(img, image.Rect(cx, cy, x+cw, cy+1), src, image.Point{}, draw.Src)
+				}
+				if arms[2] {
+					draw.Draw(img, image.Rect(cx, y, cx+1, cy+1), src, image.Point{}, draw.Src)
+				}
+				if arms[3] {
+					draw.Draw(img, image.Rect(cx, cy, cx+1, y+ch), src, image.Point{}, draw.Src)
+				}
+				continue
+			}
+			if r >= '▁' && r <= '█' {
+				h := ch * int(r-'▁'+1) / 8
+				draw.Draw(img, image.Rect(x, y+ch-h, x+cw, y+ch), src, image.Point{}, draw.Src)
+				continue
+			}
+			text := string(r)
+			if r > '~' {
+				// Replacements such as ... for … are cut to one cell.
+				text = string([]rune(asciiReplacer.Replace(text))[0])
+			}
+			d := font.Drawer{
+				Dst:  img,
+				Src:  src,
+				Face: face,
+				Dot:  fixed.P(x, y+face.Ascent),
+			}
+			d.DrawString(text)
+		}
+	}
+	return img
+}
+
+// RenderHTML renders ANSI text to a standalone HTML page with the same
+// colors as RenderANSI.
+func RenderHTML(title, s string) string {
+	hex := func(c color.RGBA) string {
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n</head>\n", html.EscapeString(title))
+	fmt.Fprintf(&b, "<body style=\"background: %s\">\n<pre style=\"color: %s; font-family: monospace; line-height: 1.2\">",
+		hex(screenshotBackground), hex(screenshotForeground))
+	fg := screenshotForeground
+	for len(s) > 0 {
+		loc := sgrRegexp.FindStringSubmatchIndex(s)
+		text := s
+		if loc != nil {
+			text = s[:loc[0]]
+		}
+		if text != "" {
+			if fg == screenshotForeground {
+				b.WriteString(html.EscapeString(text))
+			} else {
+				fmt.Fprintf(&b, "<span style=\"color: %s\">%s</span>", hex(fg), html.EscapeString(text))
+			}
+		}
+		if loc == nil {
+			break
+		}
+		if s[loc[4]:loc[5]] == "m" {
+			fg = sgrColor(s[loc[2]:loc[3]], fg)
+		}
+		s = s[loc[1]:]
+	}
+	b.WriteString("</pre>\n</body>\n</html>\n")
+	return b.String()
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/aymanbagabas/gh-stars/pkg/starsui"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Script actions.
+const (
+	scriptView   = "view"
+	scriptRange  = "range"
+	scriptSmooth = "smooth"
+	scriptKey    = "key"
+	scriptExport = "export"
+	scriptQuit   = "quit"
+)
+
+// scriptExports are the file extensions export steps can write: the graph
+// as an SVG chart, the screen as ANSI or plain text, HTML or a PNG image,
+// and the history in the CSV format of gh stars export.
+var scriptExports = []string{".svg", ".ans", ".txt", ".html", ".png", ".csv"}
+
+// ScriptStep is a step of a --script, an action with a value like
+// view=graph, or quit.
+type ScriptStep struct {
+	Action string
+	Value  string
+}
+
+func (s ScriptStep) String() string {
+	if s.Value == "" {
+		return s.Action
+	}
+	return s.Action + "=" + s.Value
+}
+
+// ParseScript parses the steps of a script separated by semicolons:
+// view=NAME, range=RANGE (or interval=RANGE), smooth=SMOOTHING, keys=KEYS
+// separated by commas, export=FILE, and quit.
+func ParseScript(script string) ([]ScriptStep, error) {
+	steps := make([]ScriptStep, 0)
+	for _, field := range strings.Split(script, ";") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		action, value, _ := strings.Cut(field, "=")
+		action, value = strings.ToLower(strings.TrimSpace(action)), strings.TrimSpace(value)
+		var err error
+		switch action {
+		case scriptView:
+			err = fmt.Errorf("unknown view %q, expected one of %s", value, strings.Join(viewNames[:], ", "))
+			for _, name := range viewNames {
+				if name == value {
+					err = nil
+				}
+			}
+		case scriptRange, "interval":
+			action = scriptRange
+			_, _, err = ParseRange(value, time.Now())
+		case scriptSmooth:
+			_, err = starsui.ParseSmoothing(value)
+		case "keys":
+			names := strings.Split(value, ",")
+			if _, err := ParseKeys(names); err != nil {
+				return nil, fmt.Errorf("script step %s: %w", field, err)
+			}
+			// Keys are pressed one step at a time, so each is handled
+			// before the next.
+			for _, name := range names {
+				steps = append(steps, ScriptStep{Action: scriptKey, Value: name})
+			}
+			continue
+		case scriptExport:
+			if !contains(scriptExports, strings.ToLower(filepath.Ext(value))) {
+				err = fmt.Errorf("can't export %q, expected a file ending with %s", value, strings.Join(scriptExports, ", "))
+			}
+		case scriptQuit:
+			if value != "" {
+				err = fmt.Errorf("quit takes no value")
+			}
+		default:
+			err = fmt.Errorf("unknown action %q, expected view, range, smooth, keys, export, or quit", action)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("script step %s: %w", field, err)
+		}
+		steps = append(steps, ScriptStep{Action: action, Value: value})
+	}
+	return steps, nil
+}
+
+// scriptMsg runs the first of the remaining steps of the script.
+type scriptMsg []ScriptStep
+
+// runScript runs the script once the stargazers are loaded.
+func (r *Repo) runScript() tea.Cmd {
+	steps := r.script
+	r.script = nil
+	return nextStep(steps)
+}
+
+func nextStep(steps []ScriptStep) tea.Cmd {
+	if len(steps) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		return scriptMsg(steps)
+	}
+}
+
+// runStep runs the first step of steps, and the next one once its command
+// is done. A failing step stops the script with the error.
+func (r *Repo) runStep(steps scriptMsg) tea.Cmd {
+	step := steps[0]
+	cmd, err := r.scriptAction(step)
+	if err != nil {
+		return func() tea.Msg {
+			return ErrorMsg(fmt.Errorf("script step %s: %w", step, err))
+		}
+	}
+	return tea.Sequence(cmd, nextStep(steps[1:]))
+}
+
+// scriptAction runs a step of the script, returning the command to wait for
+// before the next one.
+func (r *Repo) scriptAction(step ScriptStep) (tea.Cmd, error) {
+	switch step.Action {
+	case scriptView:
+		for v, name := range viewNames {
+			if name == step.Value {
+				r.view = view(v)
+			}
+		}
+		r.day.Stop()
+		// The next step sees the data of the view.
+		return r.loadView(), nil
+	case scriptRange:
+		var err error
+		r.graph.From, r.graph.To, err = ParseRange(step.Value, time.Now().In(timeZone))
+		return nil, err
+	case scriptSmooth:
+		var err error
+		r.graph.Smoothing, err = starsui.ParseSmoothing(step.Value)
+		return nil, err
+	case scriptKey:
+		keys, err := ParseKeys([]string{step.Value})
+		if err != nil {
+			return nil, err
+		}
+		return func() tea.Msg { return keys[0] }, nil
+	case scriptExport:
+		return nil, r.exportFile(step.Value)
+	case scriptQuit:
+		return tea.Quit, nil
+	}
+	return nil, fmt.Errorf("unknown action %q", step.Action)
+}
+
+// exportFile writes the graph, the screen or the history to path depending
+// on its extension.
+func (r *Repo) exportFile(path string) error {
+	var data []byte
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".svg":
+		g := r.graph
+		g.Caption = r.graphCaption()
+		g.SetSize(800, 400)
+		data = []byte(g.RenderWith(starsui.SVGRenderer{}))
+	case ".ans":
+		data = []byte(r.frame())
+	case ".txt":
+		data = []byte(sgrRegexp.ReplaceAllString(r.frame(), ""))
+	case ".html":
+		data = []byte(RenderHTML(r.name, r.frame()))
+	case ".png":
+		var b bytes.Buffer
+		if err := png.Encode(&b, RenderANSI(r.frame())); err != nil {
+			return err
+		}
+		data = b.Bytes()
+	case ".csv":
+		var b bytes.Buffer
+		entry := &stars.History{Name: r.name, Stars: r.stars, Stargazers: r.stargazers}
+		if err := ExportStarHistoryCSV(&b, entry); err != nil {
+			return err
+		}
+		data = b.Bytes()
+	default:
+		return fmt.Errorf("can't export %q", path)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
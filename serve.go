@@ -0,0 +1,261 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/aymanbagabas/gh-stars/pkg/starsui"
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/spf13/pflag"
+	"golang.org/x/sync/singleflight"
+)
+
+// repoNamePattern matches the owner/repo names the server looks up, so
+// requests can't name files outside of the cache.
+var repoNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
+// server serves the stargazers history of repositories over HTTP from the
+// cache, refreshing it periodically.
+type server struct {
+	client  api.RESTClient
+	refresh time.Duration
+	group   singleflight.Group
+	// allowed are the repositories served besides the cached ones, keyed
+	// by lowercase name.
+	allowed map[string]bool
+	mu      sync.Mutex
+	// repos are the repositories served so far, refreshed periodically.
+	repos map[string]struct{}
+}
+
+// HistoryPoint is the number of stargazers gained on a day and the total at
+// the end of that day.
+type HistoryPoint struct {
+	Date  string `json:"date"`
+	Stars int    `json:"stars"`
+	Total int    `json:"total"`
+}
+
+// History is the JSON representation of the stargazers history of a
+// repository.
+type History struct {
+	Name      string         `json:"name"`
+	Stars     int            `json:"stars"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	History   []HistoryPoint `json:"history"`
+}
+
+// BadgeMsg is a shields.io endpoint badge.
+type BadgeMsg struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+func runServe(args []string) {
+	flags := pflag.NewFlagSet("serve", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gh stars serve [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Serve the stargazers history of repositories over HTTP:\n\n")
+		fmt.Fprintf(os.Stderr, "  /<owner>/<repo>/history.json  daily and total stargazers\n")
+		fmt.Fprintf(os.Stderr, "  /<owner>/<repo>/chart.svg     stargazers over time chart\n")
+		fmt.Fprintf(os.Stderr, "  /<owner>/<repo>/badge.json    shields.io endpoint badge\n\n")
+		flags.PrintDefaults()
+	}
+	host := flags.String("host", "127.0.0.1", "address to listen on, 0.0.0.0 to accept connections from other machines")
+	port := flags.IntP("port", "p", 8080, "port to listen on")
+	repos := flags.StringSlice("repos", nil, "repositories to serve besides the cached ones")
+	refresh := flags.Duration("refresh", time.Hour, "how often to refresh the cached data")
+	addTokenFlag(flags)
+	addMaxRequestsFlag(flags)
+	flags.Parse(args)
+	if *refresh <= 0 {
+		log.Fatalf("invalid --refresh %s, expected a positive duration like 1h", *refresh)
+	}
+	client, err := NewClient()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	s := &server{
+		client:  client,
+		refresh: *refresh,
+		allowed: make(map[string]bool, len(*repos)),
+		repos:   make(map[string]struct{}),
+	}
+	for _, name := range *repos {
+		if !repoNamePattern.MatchString(name) {
+			log.Fatalf("invalid repository %q, expected owner/repo", name)
+		}
+		s.allowed[strings.ToLower(name)] = true
+	}
+	go s.refreshLoop()
+	addr := fmt.Sprintf("%s:%d", *host, *port)
+	log.Printf("listening on %s", addr)
+	log.Fatalln(http.ListenAndServe(addr, s))
+}
+
+// errNotServed is returned for the repositories that are neither allowed
+// nor cached. The token of the server isn't spent on arbitrary names.
+var errNotServed = errors.New("repository not served")
+
+// history returns the cached history of a repository, fetching it if it's
+// missing or older than the refresh interval. Stale data is served if the
+// fetch fails. Only the allowed and the cached repositories are served, and
+// they are refreshed periodically once served.
+func (s *server) history(name string) (*stars.History, error) {
+	if !repoNamePattern.MatchString(name) {
+		return nil, errNotServed
+	}
+	entry, err := LoadCache(name)
+	if err != nil || entry == nil {
+		entry = nil
+		if !s.allowed[strings.ToLower(name)] {
+			return nil, errNotServed
+		}
+	}
+	if entry != nil && time.Since(entry.UpdatedAt) < s.refresh {
+		s.track(name)
+		return entry, nil
+	}
+	fresh, err := s.fetch(name)
+	if err != nil {
+		if entry != nil {
+			log.Printf("refreshing %s, serving stale data: %v", name, err)
+			s.track(name)
+			return entry, nil
+		}
+		return nil, err
+	}
+	s.track(name)
+	return fresh, nil
+}
+
+// track refreshes a served repository periodically.
+func (s *server) track(name string) {
+	s.mu.Lock()
+	s.repos[strings.ToLower(name)] = struct{}{}
+	s.mu.Unlock()
+}
+
+// fetch fetches a repository, deduplicating concurrent requests.
+func (s *server) fetch(name string) (*stars.History, error) {
+	v, err, _ := s.group.Do(strings.ToLower(name), func() (interface{}, error) {
+		return FetchHistory(s.client, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*stars.History), nil
+}
+
+func (s *server) refreshLoop() {
+	for range time.Tick(s.refresh) {
+		s.mu.Lock()
+		names := make([]string, 0, len(s.repos))
+		for name := range s.repos {
+			names = append(names, name)
+		}
+		s.mu.Unlock()
+		for _, name := range names {
+			if _, err := s.fetch(name); err != nil {
+				log.Printf("refreshing %s: %v", name, err)
+			}
+		}
+	}
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if len(parts) != 3 {
+		http.NotFound(w, req)
+		return
+	}
+	name := parts[0] + "/" + parts[1]
+	switch parts[2] {
+	case "history.json", "chart.svg", "badge.json":
+	default:
+		http.NotFound(w, req)
+		return
+	}
+	entry, err := s.history(name)
+	if errors.Is(err, errNotServed) {
+		http.NotFound(w, req)
+		return
+	}
+	if err != nil {
+		log.Printf("fetching %s: %v", name, err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	switch parts[2] {
+	case "history.json":
+		writeJSON(w, NewHistoryJSON(entry))
+	case "chart.svg":
+		series := starsui.NewDailySeries(entry.Name, entry.Stargazers).Cumulative()
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Header().Set("Cache-Control", "max-age=3600")
+		fmt.Fprint(w, starsui.SVGRenderer{}.Render([]starsui.Series{series}, starsui.RenderOptions{
+			Width:   800,
+			Height:  400,
+			Caption: fmt.Sprintf("%s %d stargazers over time", entry.Name, entry.Stars),
+		}))
+	case "badge.json":
+		writeJSON(w, BadgeMsg{
+			SchemaVersion: 1,
+			Label:         "stars",
+			Message:       formatCount(entry.Stars),
+			Color:         "yellow",
+		})
+	}
+}
+
+// NewHistoryJSON returns the JSON representation of a cache entry.
+func NewHistoryJSON(entry *stars.History) History {
+	days := make([]string, 0, len(entry.Stargazers))
+	for day := range entry.Stargazers {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	h := History{
+		Name:      entry.Name,
+		Stars:     entry.Stars,
+		UpdatedAt: entry.UpdatedAt,
+		History:   make([]HistoryPoint, len(days)),
+	}
+	var total int
+	for i, day := range days {
+		total += entry.Stargazers[day]
+		h.History[i] = HistoryPoint{Date: day, Stars: entry.Stargazers[day], Total: total}
+	}
+	return h
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("writing response: %v", err)
+	}
+}
+
+// formatCount formats a count the way GitHub does, e.g. 1.2k.
+func formatCount(n int) string {
+	switch {
+	case n >= 1000000:
+		return fmt.Sprintf("%.1fM", float64(n)/1000000)
+	case n >= 1000:
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aymanbagabas/gh-stars/pkg/starsui"
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+// viewNames are the names of the views in sessions, indexed by view.
+var viewNames = [...]string{
+	viewGraph:        "graph",
+	viewTable:        "table",
+	viewOrgs:         "orgs",
+	viewContributors: "contributors",
+	viewStats:        "stats",
+	viewCycles:       "cycles",
+	viewQuality:      "quality",
+	viewNotable:      "notable",
+	viewAges:         "ages",
+	viewPatterns:     "patterns",
+	viewAlso:         "also",
+	viewRetention:    "retention",
+	viewIssues:       "issues",
+	viewAdoption:     "adoption",
+	viewCampaigns:    "campaigns",
+}
+
+// Session is a saved setup of the repository view, saved with :w NAME and
+// restored with --session NAME.
+type Session struct {
+	Repo       string `yaml:"repo"`
+	View       string `yaml:"view"`
+	Theme      string `yaml:"theme,omitempty"`
+	Smoothing  string `yaml:"smoothing"`
+	LogScale   bool   `yaml:"log_scale"`
+	Buckets    bool   `yaml:"buckets"`
+	Growth     bool   `yaml:"growth"`
+	Cumulative bool   `yaml:"cumulative"`
+	// From and To are the zoomed window of the graph, empty when it isn't
+	// zoomed.
+	From string `yaml:"from,omitempty"`
+	To   string `yaml:"to,omitempty"`
+}
+
+// sessionPath returns the file of a named session, in the sessions
+// directory next to the config file.
+func sessionPath(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid session name %q", name)
+	}
+	path, err := configPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "sessions", name+".yml"), nil
+}
+
+// SaveSession writes a named session, replacing any session of the same
+// name.
+func SaveSession(name string, s Session) error {
+	path, err := sessionPath(name)
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// LoadSession reads a named session.
+func LoadSession(name string) (Session, error) {
+	var s Session
+	path, err := sessionPath(name)
+	if err != nil {
+		return s, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, fmt.Errorf("no session named %q, save one with :w %s", name, name)
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("reading session %q: %w", name, err)
+	}
+	return s, nil
+}
+
+// session returns the current setup of the repository view.
+func (r *Repo) session() Session {
+	return Session{
+		Repo:       r.name,
+		View:       viewNames[r.view],
+		Theme:      r.themeName,
+		Smoothing:  r.graph.Smoothing.String(),
+		LogScale:   r.graph.LogScale,
+		Buckets:    r.graph.Buckets,
+		Growth:     r.graph.Growth,
+		Cumulative: r.graph.Cumulative,
+		From:       r.graph.From,
+		To:         r.graph.To,
+	}
+}
+
+// restore applies a saved session. The data of its view is loaded once the
+// stargazers are.
+func (r *Repo) restore(s Session) error {
+	if s.Smoothing != "" {
+		smoothing, err := starsui.ParseSmoothing(s.Smoothing)
+		if err != nil {
+			return err
+		}
+		r.graph.Smoothing = smoothing
+	}
+	if s.View != "" {
+		found := false
+		for v, name := range viewNames {
+			if name == s.View {
+				r.view, found = view(v), true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("unknown view %q in the session", s.View)
+		}
+	}
+	r.graph.LogScale = s.LogScale
+	r.graph.Buckets = s.Buckets
+	r.graph.Growth = s.Growth
+	r.graph.Cumulative = s.Cumulative
+	r.graph.From, r.graph.To = s.From, s.To
+	return nil
+}
+
+// commandPrompt reads a command typed after :, like :w NAME to save the
+// session, or a table filter typed after /.
+type commandPrompt struct {
+	active bool
+	input  textinput.Model
+	// run runs the typed command on enter.
+	run func(string) tea.Cmd
+}
+
+func newCommandPrompt(theme Theme) commandPrompt {
+	input := textinput.New()
+	input.PromptStyle = theme.AccentStyle()
+	// The cursor doesn't blink so the prompt needs no ticks.
+	input.Cursor.SetMode(cursor.CursorStatic)
+	return commandPrompt{input: input}
+}
+
+// Active reports whether a command is being typed.
+func (p *commandPrompt) Active() bool {
+	return p.active
+}
+
+// Start shows the prompt, starting with value, and runs the command typed
+// with run.
+func (p *commandPrompt) Start(prompt, value string, run func(string) tea.Cmd) {
+	p.active = true
+	p.run = run
+	p.input.Prompt = prompt
+	p.input.SetValue(value)
+	p.input.CursorEnd()
+	p.input.Focus()
+}
+
+// Update types into the prompt, running the command on enter.
+func (p *commandPrompt) Update(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEsc:
+		p.active = false
+		return nil
+	case tea.KeyEnter:
+		p.active = false
+		return p.run(p.input.Value())
+	}
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return cmd
+}
+
+// View replaces the last line of v with the prompt while it's active.
+func (p *commandPrompt) View(v string) string {
+	if !p.active {
+		return v
+	}
+	if i := strings.LastIndex(v, "\n"); i >= 0 {
+		v = v[:i+1]
+	} else {
+		v = ""
+	}
+	return v + " " + p.input.View()
+}
+
+// runCommand runs a command typed in the prompt.
+func (r *Repo) runCommand(command string) tea.Cmd {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil
+	}
+	switch fields[0] {
+	case "w", "write":
+		if len(fields) != 2 {
+			return r.toast.Show("Usage: :w NAME")
+		}
+		if err := SaveSession(fields[1], r.session()); err != nil {
+			return r.toast.Show(fmt.Sprintf("Error saving session: %s", err))
+		}
+		return r.toast.Show(fmt.Sprintf("Saved session %s, restore it with --session %s", fields[1], fields[1]))
+	}
+	return r.toast.Show(fmt.Sprintf("Unknown command %q", fields[0]))
+}
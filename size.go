@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// minWidth and minHeight are the smallest terminal size the views are
+	// laid out in.
+	minWidth  = 40
+	minHeight = 10
+	// compactHeight is the terminal height below which graph captions and
+	// the full help are hidden.
+	compactHeight = 16
+)
+
+// tooSmall returns the screen shown instead of a view when the terminal is
+// smaller than minWidth x minHeight, and whether it is. The size is unknown
+// until the first resize.
+func tooSmall(width, height int) (string, bool) {
+	if width == 0 && height == 0 || width >= minWidth && height >= minHeight {
+		return "", false
+	}
+	msg := lipgloss.NewStyle().
+		Width(width).
+		Align(lipgloss.Center).
+		Render(fmt.Sprintf("terminal too small (need %dx%d)", minWidth, minHeight))
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, msg), true
+}
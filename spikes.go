@@ -0,0 +1,280 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// spikeFactor is how many times the trailing average a day must reach
+	// to be a spike.
+	spikeFactor = 3
+	// spikeMinStars ignores spikes of small repositories where a couple of
+	// stars would triple the average.
+	spikeMinStars = 5
+	// spikeWindow is the number of days of the trailing average.
+	spikeWindow = 14
+	// spikePaneHeight is the number of lines of the spikes details pane.
+	spikePaneHeight = 6
+
+	hnSearchURL     = "https://hn.algolia.com/api/v1/search?query=%s&restrictSearchableAttributes=url&numericFilters=created_at_i>%d,created_at_i<%d"
+	redditSearchURL = "https://www.reddit.com/search.json?q=%s&sort=new&limit=100"
+)
+
+// Spike is a day with an unusual number of stargazers.
+type Spike struct {
+	Date    string
+	Count   int
+	Average float64
+}
+
+// SourceHint is a post that likely caused a spike.
+type SourceHint struct {
+	Source string
+	Title  string
+	URL    string
+	Points int
+}
+
+// SourceHintsMsg holds the source hints of a spike.
+type SourceHintsMsg struct {
+	Date  string
+	Hints []SourceHint
+	Err   error
+}
+
+// DetectSpikes returns the days with more than spikeFactor times the
+// average of the previous spikeWindow days, in chronological order.
+func DetectSpikes(daily map[string]int) []Spike {
+	first := earliestKey(daily)
+	if first == "" {
+		return nil
+	}
+	start, err := time.Parse("2006-01-02", first)
+	if err != nil {
+		return nil
+	}
+	end := time.Now()
+	spikes := make([]Spike, 0)
+	window := make([]int, 0, spikeWindow)
+	var sum int
+	for t := start; !t.After(end); t = t.AddDate(0, 0, 1) {
+		day := t.Format("2006-01-02")
+		count := daily[day]
+		var avg float64
+		if len(window) > 0 {
+			avg = float64(sum) / float64(len(window))
+		}
+		if count >= spikeMinStars && float64(count) > spikeFactor*avg {
+			spikes = append(spikes, Spike{Date: day, Count: count, Average: avg})
+		}
+		window = append(window, count)
+		sum += count
+		if len(window) > spikeWindow {
+			sum -= window[0]
+			window = window[1:]
+		}
+	}
+	return spikes
+}
+
+// spikes flags spikes on the graph and shows their likely sources.
+type spikes struct {
+	active  bool
+	list    []Spike
+	index   int
+	hints   map[string]SourceHintsMsg
+	loading map[string]bool
+}
+
+// Active returns whether spikes are shown.
+func (s *spikes) Active() bool {
+	return s.active
+}
+
+// Toggle shows or hides the spikes and loads the hints of the selected
+// spike.
+func (s *spikes) Toggle(r *Repo) tea.Cmd {
+	s.active = !s.active
+	if !s.active {
+		return nil
+	}
+	s.list = DetectSpikes(r.stargazers)
+	// Start with the most recent spike.
+	s.index = len(s.list) - 1
+	return s.load(r)
+}
+
+// Stop hides the spikes.
+func (s *spikes) Stop() {
+	s.active = false
+}
+
+// Move selects another spike and loads its hints.
+func (s *spikes) Move(delta int, r *Repo) tea.Cmd {
+	if !s.active || len(s.list) == 0 {
+		return nil
+	}
+	s.index += delta
+	if s.index < 0 {
+		s.index = 0
+	}
+	if s.index > len(s.list)-1 {
+		s.index = len(s.list) - 1
+	}
+	return s.load(r)
+}
+
+// SetHints stores the source hints of a spike.
+func (s *spikes) SetHints(msg SourceHintsMsg) {
+	delete(s.loading, msg.Date)
+	s.hints[msg.Date] = msg
+}
+
+func (s *spikes) load(r *Repo) tea.Cmd {
+	if s.index < 0 || r.offline {
+		return nil
+	}
+	if s.hints == nil {
+		s.hints = make(map[string]SourceHintsMsg)
+		s.loading = make(map[string]bool)
+	}
+	date := s.list[s.index].Date
+	if _, ok := s.hints[date]; ok || s.loading[date] {
+		return nil
+	}
+	s.loading[date] = true
+	name := r.name
+	return func() tea.Msg {
+		hints, err := FetchSourceHints(name, date)
+		return SourceHintsMsg{Date: date, Hints: hints, Err: err}
+	}
+}
+
+// Markers returns a line with a marker under each spike of the graph.
+func (s *spikes) Markers(r *Repo, keys []string) string {
+	dates := make([]string, len(s.list))
+	for i, spike := range s.list {
+		dates[i] = spike.Date
+	}
+	return markerLine(r, keys, dates, "▲")
+}
+
+// View renders the details pane of the selected spike.
+func (s *spikes) View(r *Repo) string {
+	if len(s.list) == 0 {
+		return " No spikes found."
+	}
+	spike := s.list[s.index]
+	lines := []string{
+		fmt.Sprintf(" Spike %d/%d: %s, %d stars (%.1f/day on average before), ↑/↓ to select",
+			s.index+1, len(s.list), spike.Date, spike.Count, spike.Average),
+	}
+	msg, ok := s.hints[spike.Date]
+	switch {
+	case r.offline:
+		lines = append(lines, " Source hints are not available offline.")
+	case s.loading[spike.Date]:
+		lines = append(lines, fmt.Sprintf(" %s looking for sources...", r.spinner.View()))
+	case ok && msg.Err != nil:
+		lines = append(lines, fmt.Sprintf(" Error: %s", msg.Err))
+	case ok && len(msg.Hints) == 0:
+		lines = append(lines, " No likely source found on Hacker News or Reddit.")
+	case ok:
+		for i, h := range msg.Hints {
+			if i == spikePaneHeight-1 {
+				break
+			}
+			lines = append(lines, fmt.Sprintf(" [%s] %s (%d points) %s", h.Source, h.Title, h.Points, h.URL))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+var hintsClient = &http.Client{Timeout: 10 * time.Second}
+
+// FetchSourceHints searches Hacker News and Reddit for posts linking to the
+// repository around date.
+func FetchSourceHints(name string, date string) ([]SourceHint, error) {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return nil, err
+	}
+	// Posts usually precede the spike by a day or so.
+	from, to := day.AddDate(0, 0, -2), day.AddDate(0, 0, 1)
+	query := url.QueryEscape("github.com/" + name)
+	hints := make([]SourceHint, 0)
+
+	var hn struct {
+		Hits []struct {
+			Title    string `json:"title"`
+			ObjectID string `json:"objectID"`
+			Points   int    `json:"points"`
+		} `json:"hits"`
+	}
+	if err := getJSON(fmt.Sprintf(hnSearchURL, query, from.Unix(), to.Unix()), &hn); err != nil {
+		return nil, fmt.Errorf("searching Hacker News: %w", err)
+	}
+	for _, hit := range hn.Hits {
+		hints = append(hints, SourceHint{
+			Source: "HN",
+			Title:  hit.Title,
+			URL:    "https://news.ycombinator.com/item?id=" + hit.ObjectID,
+			Points: hit.Points,
+		})
+	}
+
+	var reddit struct {
+		Data struct {
+			Children []struct {
+				Data struct {
+					Title      string  `json:"title"`
+					Permalink  string  `json:"permalink"`
+					Score      int     `json:"score"`
+					CreatedUTC float64 `json:"created_utc"`
+				} `json:"data"`
+			} `json:"children"`
+		} `json:"data"`
+	}
+	if err := getJSON(fmt.Sprintf(redditSearchURL, query), &reddit); err != nil {
+		return nil, fmt.Errorf("searching Reddit: %w", err)
+	}
+	for _, child := range reddit.Data.Children {
+		post := child.Data
+		created := time.Unix(int64(post.CreatedUTC), 0)
+		if created.Before(from) || created.After(to) {
+			continue
+		}
+		hints = append(hints, SourceHint{
+			Source: "Reddit",
+			Title:  post.Title,
+			URL:    "https://www.reddit.com" + post.Permalink,
+			Points: post.Score,
+		})
+	}
+	return hints, nil
+}
+
+func getJSON(u string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	// Reddit rejects requests without a user agent.
+	req.Header.Set("User-Agent", "gh-stars")
+	resp, err := hintsClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
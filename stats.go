@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/go-gh/pkg/api"
+)
+
+const commitsPath = "repos/%s/commits"
+
+// YearStats are the stargazers, releases, and commits of a year.
+type YearStats struct {
+	Year     string
+	Stars    int
+	Releases int
+	Commits  int
+}
+
+// StatsMsg holds the yearly statistics of a repository.
+type StatsMsg struct {
+	Years []YearStats
+	Err   error
+}
+
+// CountCommits returns the number of commits between since and until. It
+// requests a single commit per page and reads the number of pages from the
+// Link header.
+func CountCommits(client api.RESTClient, name string, since, until time.Time) (int, error) {
+	path := fmt.Sprintf(commitsPath+"?since=%s&until=%s",
+		name, since.Format(time.RFC3339), until.Format(time.RFC3339))
+	// With one commit per page, the number of pages is the number of
+	// commits.
+	p := stars.NewPaginator(client, path, 1)
+	var commits []struct{}
+	if err := p.Page(1, &commits); err != nil {
+		// Empty repositories return 409 Conflict.
+		var httpErr api.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusConflict {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if len(commits) == 0 {
+		return 0, nil
+	}
+	return p.Last(), nil
+}
+
+// stats shows the stars per release and per 100 commits of each year.
+type stats struct {
+	loading bool
+	years   []YearStats
+	err     error
+}
+
+// Load fetches the releases and the yearly commit counts unless they were
+// already fetched.
+func (s *stats) Load(r *Repo) tea.Cmd {
+	if s.loading || s.years != nil || s.err != nil || len(r.stargazers) == 0 {
+		return nil
+	}
+	if r.offline {
+		s.err = errOffline
+		return nil
+	}
+	s.loading = true
+	client, name := r.client, r.name
+	yearly := make(map[string]int)
+	for day, count := range r.stargazers {
+		yearly[day[:4]] += count
+	}
+	return func() tea.Msg {
+		releases, err := FetchReleases(client, name)
+		if err != nil {
+			return StatsMsg{Err: err}
+		}
+		first, err := strconv.Atoi(earliestKey(yearly))
+		if err != nil {
+			return StatsMsg{Err: err}
+		}
+		years := make([]YearStats, 0)
+		for y := first; y <= time.Now().Year(); y++ {
+			since := time.Date(y, 1, 1, 0, 0, 0, 0, time.UTC)
+			commits, err := CountCommits(client, name, since, since.AddDate(1, 0, 0))
+			if err != nil {
+				return StatsMsg{Err: err}
+			}
+			year := strconv.Itoa(y)
+			ys := YearStats{Year: year, Stars: yearly[year], Commits: commits}
+			for _, rel := range releases {
+				if strings.HasPrefix(rel.Date(), year) {
+					ys.Releases++
+				}
+			}
+			years = append(years, ys)
+		}
+		sort.Slice(years, func(i, j int) bool { return years[i].Year < years[j].Year })
+		return StatsMsg{Years: years}
+	}
+}
+
+// SetStats stores the fetched statistics.
+func (s *stats) SetStats(msg StatsMsg) {
+	s.loading = false
+	s.years = msg.Years
+	s.err = msg.Err
+}
+
+func (s *stats) View(r *Repo) string {
+	switch {
+	case s.loading:
+		return fmt.Sprintf("\n %s loading releases and commits...\n", r.spinner.View())
+	case s.err != nil:
+		return fmt.Sprintf("\n Error: %s", s.err)
+	case len(r.stargazers) == 0:
+		return "\n No stargazers found.\n"
+	}
+	ratio := func(stars, n int, per float64) string {
+		if n == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.1f", float64(stars)*per/float64(n))
+	}
+	var b strings.Builder
+	row := func(cols ...string) {
+		fmt.Fprintf(&b, " %-6s %8s %9s %14s %8s %18s\n", cols[0], cols[1], cols[2], cols[3], cols[4], cols[5])
+	}
+	b.WriteString("\n")
+	row("Year", "Stars", "Releases", "Stars/release", "Commits", "Stars/100 commits")
+	var total YearStats
+	for _, y := range s.years {
+		row(y.Year, strconv.Itoa(y.Stars), strconv.Itoa(y.Releases), ratio(y.Stars, y.Releases, 1),
+			strconv.Itoa(y.Commits), ratio(y.Stars, y.Commits, 100))
+		total.Stars += y.Stars
+		total.Releases += y.Releases
+		total.Commits += y.Commits
+	}
+	row("Total", strconv.Itoa(total.Stars), strconv.Itoa(total.Releases), ratio(total.Stars, total.Releases, 1),
+		strconv.Itoa(total.Commits), ratio(total.Stars, total.Commits, 100))
+	return b.String()
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aymanbagabas/gh-stars/pkg/starsui"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/guptarohit/asciigraph"
+	"github.com/muesli/termenv"
+)
+
+// ansiColor is an asciigraph color that adapts to the terminal background.
+type ansiColor struct {
+	Light asciigraph.AnsiColor
+	Dark  asciigraph.AnsiColor
+}
+
+func (c ansiColor) color() asciigraph.AnsiColor {
+	if lipgloss.HasDarkBackground() {
+		return c.Dark
+	}
+	return c.Light
+}
+
+// Theme defines the colors used to render the TUI.
+type Theme struct {
+	Accent lipgloss.TerminalColor
+	// Warning and Error are the colors of the banners above the views, for
+	// degraded data and for alarming changes of the stars.
+	Warning lipgloss.TerminalColor
+	Error   lipgloss.TerminalColor
+	Series  ansiColor
+	// Secondary is the color of a second series plotted on the same graph.
+	Secondary ansiColor
+	Axis      ansiColor
+	Label     ansiColor
+	Caption   ansiColor
+	plain     bool
+}
+
+// extraSeriesColors are the colors of the series after the first two.
+var extraSeriesColors = []asciigraph.AnsiColor{
+	asciigraph.Green,
+	asciigraph.Magenta,
+	asciigraph.Cyan,
+	asciigraph.Red,
+}
+
+// ThemeColors overrides the colors of a theme. Accent, Warning, and Error
+// take any lipgloss color, the others take an asciigraph color name or an
+// ANSI 256 color number.
+type ThemeColors struct {
+	Accent    string `yaml:"accent,omitempty"`
+	Warning   string `yaml:"warning,omitempty"`
+	Error     string `yaml:"error,omitempty"`
+	Series    string `yaml:"series,omitempty"`
+	Secondary string `yaml:"secondary,omitempty"`
+	Axis      string `yaml:"axis,omitempty"`
+	Label     string `yaml:"label,omitempty"`
+	Caption   string `yaml:"caption,omitempty"`
+}
+
+var themes = map[string]Theme{
+	"auto": {
+		Accent:    lipgloss.AdaptiveColor{Light: "162", Dark: "205"},
+		Warning:   lipgloss.Color("3"),
+		Error:     lipgloss.Color("1"),
+		Series:    ansiColor{Light: asciigraph.Navy, Dark: asciigraph.Blue},
+		Secondary: ansiColor{Light: asciigraph.DarkOrange, Dark: asciigraph.Orange},
+		Axis:      ansiColor{Light: asciigraph.Default, Dark: asciigraph.Default},
+		Label:     ansiColor{Light: asciigraph.Default, Dark: asciigraph.Default},
+		Caption:   ansiColor{Light: asciigraph.Default, Dark: asciigraph.Default},
+	},
+	"dark": {
+		Accent:    lipgloss.Color("205"),
+		Warning:   lipgloss.Color("3"),
+		Error:     lipgloss.Color("1"),
+		Series:    ansiColor{Light: asciigraph.Blue, Dark: asciigraph.Blue},
+		Secondary: ansiColor{Light: asciigraph.Orange, Dark: asciigraph.Orange},
+		Axis:      ansiColor{Light: asciigraph.Gray, Dark: asciigraph.Gray},
+		Label:     ansiColor{Light: asciigraph.Silver, Dark: asciigraph.Silver},
+		Caption:   ansiColor{Light: asciigraph.White, Dark: asciigraph.White},
+	},
+	"light": {
+		Accent:    lipgloss.Color("162"),
+		Warning:   lipgloss.Color("3"),
+		Error:     lipgloss.Color("1"),
+		Series:    ansiColor{Light: asciigraph.Navy, Dark: asciigraph.Navy},
+		Secondary: ansiColor{Light: asciigraph.DarkOrange, Dark: asciigraph.DarkOrange},
+		Axis:      ansiColor{Light: asciigraph.Gray, Dark: asciigraph.Gray},
+		Label:     ansiColor{Light: asciigraph.Black, Dark: asciigraph.Black},
+		Caption:   ansiColor{Light: asciigraph.Black, Dark: asciigraph.Black},
+	},
+	"high-contrast": {
+		Accent:    lipgloss.AdaptiveColor{Light: "0", Dark: "15"},
+		Warning:   lipgloss.Color("3"),
+		Error:     lipgloss.Color("1"),
+		Series:    ansiColor{Light: asciigraph.Red, Dark: asciigraph.Yellow},
+		Secondary: ansiColor{Light: asciigraph.Blue, Dark: asciigraph.Cyan},
+		Axis:      ansiColor{Light: asciigraph.Black, Dark: asciigraph.White},
+		Label:     ansiColor{Light: asciigraph.Black, Dark: asciigraph.White},
+		Caption:   ansiColor{Light: asciigraph.Black, Dark: asciigraph.White},
+	},
+}
+
+// ThemeNames returns the names of the built-in themes.
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewTheme returns the built-in theme with the given name with colors
+// overridden by the non-empty fields of colors.
+func NewTheme(name string, colors ThemeColors) (Theme, error) {
+	if name == "" {
+		name = "auto"
+	}
+	t, ok := themes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q, available themes: %s", name, strings.Join(ThemeNames(), ", "))
+	}
+	if colors.Accent != "" {
+		t.Accent = lipgloss.Color(colors.Accent)
+	}
+	if colors.Warning != "" {
+		t.Warning = lipgloss.Color(colors.Warning)
+	}
+	if colors.Error != "" {
+		t.Error = lipgloss.Color(colors.Error)
+	}
+	for _, c := range []struct {
+		value string
+		color *ansiColor
+	}{
+		{colors.Series, &t.Series},
+		{colors.Secondary, &t.Secondary},
+		{colors.Axis, &t.Axis},
+		{colors.Label, &t.Label},
+		{colors.Caption, &t.Caption},
+	} {
+		if c.value == "" {
+			continue
+		}
+		ac, err := parseAnsiColor(c.value)
+		if err != nil {
+			return Theme{}, err
+		}
+		*c.color = ansiColor{Light: ac, Dark: ac}
+	}
+	return t, nil
+}
+
+func parseAnsiColor(s string) (asciigraph.AnsiColor, error) {
+	if c, ok := asciigraph.ColorNames[strings.ToLower(s)]; ok {
+		return c, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n > 255 {
+		return 0, fmt.Errorf("invalid color %q", s)
+	}
+	return asciigraph.AnsiColor(n), nil
+}
+
+// PlainTheme returns a theme without any colors and disables colors in
+// lipgloss styles.
+func PlainTheme() Theme {
+	lipgloss.SetColorProfile(termenv.Ascii)
+	return Theme{Accent: lipgloss.NoColor{}, Warning: lipgloss.NoColor{}, Error: lipgloss.NoColor{}, plain: true}
+}
+
+// asciiReplacer replaces the Unicode characters used by the graph, the help,
+// and the table with ASCII ones.
+var asciiReplacer = strings.NewReplacer(
+	"┤", "|",
+	"├", "|",
+	"┼", "+",
+	"─", "-",
+	"│", "|",
+	"╭", ".",
+	"╮", ".",
+	"╰", "'",
+	"╯", "'",
+	"╴", "-",
+	"╶", "-",
+	"↑", "^",
+	"↓", "v",
+	"←", "<",
+	"→", ">",
+	"•", "*",
+	"▲", "^",
+	"◆", "*",
+	"▼", "v",
+	"■", "#",
+	"□", "o",
+	"▁", "_",
+	"▂", ".",
+	"▃", ":",
+	"▄", "-",
+	"▅", "=",
+	"▆", "+",
+	"▇", "*",
+	"█", "#",
+	"░", ".",
+	"…", "...",
+)
+
+// AccentStyle returns a style using the accent color of the theme.
+func (t Theme) AccentStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(t.Accent)
+}
+
+// WarningStyle returns a style using the warning color of the theme.
+func (t Theme) WarningStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(t.Warning)
+}
+
+// ErrorStyle returns a style using the error color of the theme.
+func (t Theme) ErrorStyle() lipgloss.Style {
+	return lipgloss.NewStyle().Foreground(t.Error)
+}
+
+// Colors returns the colors of a graph. The first series uses the series
+// color, the second one uses the secondary color, and the others use
+// extraSeriesColors.
+func (t Theme) Colors() starsui.Colors {
+	series := []asciigraph.AnsiColor{t.Series.color(), t.Secondary.color()}
+	if !t.plain {
+		series = append(series, extraSeriesColors...)
+	}
+	return starsui.Colors{
+		Series:  series,
+		Axis:    t.Axis.color(),
+		Label:   t.Label.color(),
+		Caption: t.Caption.color(),
+	}
+}
+
+// Legend renders labels in the colors of the graph series they describe.
+func (t Theme) Legend(labels []string) string {
+	colors := t.Colors().Series
+	legend := make([]string, len(labels))
+	for i, label := range labels {
+		color := lipgloss.Color(strconv.Itoa(int(colors[i%len(colors)])))
+		legend[i] = lipgloss.NewStyle().Foreground(color).Render("■ " + label)
+	}
+	return strings.Join(legend, "  ")
+}
+
+// ToggleLegend renders labels like Legend, numbered from 1 to toggle them,
+// with the hidden ones dimmed.
+func (t Theme) ToggleLegend(labels []string, hidden []bool) string {
+	colors := t.Colors().Series
+	legend := make([]string, len(labels))
+	for i, label := range labels {
+		label = fmt.Sprintf("%d %s", i+1, label)
+		if i < len(hidden) && hidden[i] {
+			legend[i] = lipgloss.NewStyle().Faint(true).Render("□ " + label)
+			continue
+		}
+		color := lipgloss.Color(strconv.Itoa(int(colors[i%len(colors)])))
+		legend[i] = lipgloss.NewStyle().Foreground(color).Render("■ " + label)
+	}
+	return strings.Join(legend, "  ")
+}
+
+// VisibleColors returns the graph colors of the series that aren't hidden,
+// so series keep their color when others are hidden.
+func (t Theme) VisibleColors(hidden []bool) starsui.Colors {
+	colors := t.Colors()
+	series := make([]asciigraph.AnsiColor, 0, len(colors.Series))
+	for i := range hidden {
+		if !hidden[i] {
+			series = append(series, colors.Series[i%len(colors.Series)])
+		}
+	}
+	if len(series) > 0 {
+		colors.Series = series
+	}
+	return colors
+}
+
+// TableStyles returns the table styles of the theme.
+func (t Theme) TableStyles() table.Styles {
+	s := table.DefaultStyles()
+	s.Selected = s.Selected.Copy().Foreground(t.Accent)
+	return s
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+)
+
+// timeZone is the time zone in which stargazers are counted per day. GitHub
+// counts them in UTC.
+var timeZone = time.UTC
+
+// ParseTimeZone parses UTC, local, or an IANA time zone name.
+func ParseTimeZone(name string) (*time.Location, error) {
+	switch strings.ToLower(name) {
+	case "", "utc":
+		return time.UTC, nil
+	case "local":
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q, expected UTC, local, or an IANA name like Europe/Paris", name)
+	}
+	return loc, nil
+}
+
+// dailyBucketer counts stargazers per day in timeZone.
+func dailyBucketer() stars.Bucketer {
+	return stars.Daily{Location: timeZone}
+}
+
+// dayOf returns the day of t in timeZone formatted as 2006-01-02.
+func dayOf(t time.Time) string {
+	return dailyBucketer().Bucket(t)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+)
+
+const (
+	// unstarAlertThreshold is the number of stargazers lost in a day above
+	// which an alert is raised.
+	unstarAlertThreshold = 10
+	// unstarAlertDays is the number of recent days alerts are raised for.
+	unstarAlertDays = 7
+)
+
+// countUnstars records in entry the stargazers of the previous fetch that
+// are missing from the current one, logins. They are counted on the day of
+// the fetch as the API doesn't tell when a star is removed, and kept with the
+// times of both fetches.
+func countUnstars(entry *stars.History, logins []string, now time.Time) {
+	if len(entry.Logins) == 0 {
+		return
+	}
+	current := make(map[string]bool, len(logins))
+	for _, login := range logins {
+		current[strings.ToLower(login)] = true
+	}
+	var lost int
+	for _, login := range entry.Logins {
+		if !current[strings.ToLower(login)] {
+			lost++
+			entry.Unstarred = append(entry.Unstarred, stars.Unstar{Login: login, After: entry.UpdatedAt, Before: now})
+		}
+	}
+	if lost == 0 {
+		return
+	}
+	if entry.Unstars == nil {
+		entry.Unstars = make(map[string]int)
+	}
+	entry.Unstars[now.Format("2006-01-02")] += lost
+}
+
+// unstarAlerts returns the recent days when more than unstarAlertThreshold
+// stargazers were lost, oldest first.
+func unstarAlerts(unstars map[string]int, now time.Time) []string {
+	since := now.AddDate(0, 0, -unstarAlertDays).Format("2006-01-02")
+	days := make([]string, 0)
+	for day, lost := range unstars {
+		if day > since && lost > unstarAlertThreshold {
+			days = append(days, day)
+		}
+	}
+	sort.Strings(days)
+	return days
+}
+
+func (r *Repo) unstarBanner() string {
+	days := unstarAlerts(r.unstars, time.Now())
+	parts := make([]string, len(days))
+	for i, day := range days {
+		parts[i] = fmt.Sprintf("%d on %s", r.unstars[day], day)
+	}
+	return r.theme.ErrorStyle().Render(" unstar wave: lost " + strings.Join(parts, ", "))
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+)
+
+const userReposPath = "users/%s/repos?type=owner"
+
+// FetchUserRepos fetches the public repositories owned by a user sorted by
+// stars.
+func FetchUserRepos(login string) ([]RepoMsg, error) {
+	client, err := NewClient()
+	if err != nil {
+		return nil, err
+	}
+	p := stars.NewPaginator(client, fmt.Sprintf(userReposPath, login), stars.PerPage)
+	repos := make([]RepoMsg, 0)
+	for {
+		result := make([]RepoMsg, 0)
+		ok, err := p.Next(&result)
+		if err != nil {
+			return nil, fmt.Errorf("listing the repositories of %s: %w", login, err)
+		}
+		if !ok {
+			break
+		}
+		repos = append(repos, result...)
+	}
+	sort.SliceStable(repos, func(i, j int) bool {
+		return repos[i].StargazersCount > repos[j].StargazersCount
+	})
+	return repos, nil
+}
+
+// NewUser returns a dashboard of the public repositories of a user sorted
+// by stars, followed by their total.
+func NewUser(login string, opts Options) (*Dashboard, error) {
+	if opts.Offline {
+		return nil, fmt.Errorf("listing the repositories of %s: %w", login, errOffline)
+	}
+	repos, err := FetchUserRepos(login)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(repos))
+	for i, repo := range repos {
+		names[i] = repo.FullName
+	}
+	d, err := NewDashboard(names, opts)
+	if err != nil {
+		return nil, err
+	}
+	d.totals = true
+	return d, nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/starsui"
+)
+
+// Window is a named period of time.
+type Window struct {
+	Name string
+	From time.Time
+	To   time.Time
+}
+
+// Days returns the number of days of the window.
+func (w Window) Days() int {
+	return int(w.To.Sub(w.From).Hours()/24) + 1
+}
+
+// ParseWindow parses a quarter such as 2024Q1 or a date range such as
+// 2024-01-01..2024-03-31, optionally prefixed with a name and an equal sign.
+func ParseWindow(s string) (Window, error) {
+	var w Window
+	spec := s
+	if i := strings.Index(s, "="); i >= 0 {
+		w.Name, spec = s[:i], s[i+1:]
+	}
+	if w.Name == "" {
+		w.Name = spec
+	}
+	if from, to, ok := strings.Cut(spec, ".."); ok {
+		var err error
+		if w.From, err = time.Parse("2006-01-02", from); err != nil {
+			return w, fmt.Errorf("invalid window %q: %w", s, err)
+		}
+		if w.To, err = time.Parse("2006-01-02", to); err != nil {
+			return w, fmt.Errorf("invalid window %q: %w", s, err)
+		}
+	} else if year, quarter, ok := strings.Cut(strings.ToUpper(spec), "Q"); ok {
+		y, err := strconv.Atoi(year)
+		if err != nil {
+			return w, fmt.Errorf("invalid window %q: %w", s, err)
+		}
+		q, err := strconv.Atoi(quarter)
+		if err != nil || q < 1 || q > 4 {
+			return w, fmt.Errorf("invalid window %q: quarter must be between 1 and 4", s)
+		}
+		w.From = time.Date(y, time.Month(3*q-2), 1, 0, 0, 0, 0, time.UTC)
+		w.To = w.From.AddDate(0, 3, -1)
+	} else {
+		return w, fmt.Errorf("invalid window %q, expected a quarter like 2024Q1 or a range like 2024-01-01..2024-03-31", s)
+	}
+	if w.To.Before(w.From) {
+		return w, fmt.Errorf("invalid window %q: ends before it starts", s)
+	}
+	return w, nil
+}
+
+// ParseWindows parses a list of windows.
+func ParseWindows(specs []string) ([]Window, error) {
+	windows := make([]Window, len(specs))
+	for i, spec := range specs {
+		w, err := ParseWindow(spec)
+		if err != nil {
+			return nil, err
+		}
+		windows[i] = w
+	}
+	return windows, nil
+}
+
+// rangePresets are the days of the recent windows of the graph selected by
+// name with --range and with the 0-4 keys, zero for all time.
+var rangePresets = map[string]int{
+	"all":     0,
+	"week":    7,
+	"month":   30,
+	"quarter": 91,
+	"year":    365,
+}
+
+// ParseRange parses a range preset, a quarter, or a date range into the
+// first and last days of the plotted window, formatted as 2006-01-02 and
+// empty for all time. Presets end today.
+func ParseRange(s string, today time.Time) (from, to string, err error) {
+	if days, ok := rangePresets[s]; ok {
+		if days == 0 {
+			return "", "", nil
+		}
+		return today.AddDate(0, 0, -days+1).Format("2006-01-02"), today.Format("2006-01-02"), nil
+	}
+	w, err := ParseWindow(s)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid range %q, expected all, week, month, quarter, year, a quarter like 2024Q1, or a range like 2024-01-01..2024-03-31", s)
+	}
+	return w.From.Format("2006-01-02"), w.To.Format("2006-01-02"), nil
+}
+
+// compare overlays the cumulative stars of several windows aligned by day
+// within the window.
+type windowComparison struct {
+	active  bool
+	windows []Window
+}
+
+// Active returns whether the windows are compared.
+func (c *windowComparison) Active() bool {
+	return c.active
+}
+
+// Toggle shows or hides the comparison. There is nothing to compare without
+// windows.
+func (c *windowComparison) Toggle() {
+	c.active = !c.active && len(c.windows) > 0
+}
+
+// Stop hides the comparison.
+func (c *windowComparison) Stop() {
+	c.active = false
+}
+
+func (c *windowComparison) View(r *Repo) string {
+	days := 0
+	for _, w := range c.windows {
+		if d := w.Days(); d > days {
+			days = d
+		}
+	}
+	labels := make([]string, days)
+	for i := range labels {
+		labels[i] = fmt.Sprintf("day %d", i+1)
+	}
+	now := time.Now()
+	series := make([]starsui.Series, len(c.windows))
+	totals := make([]string, len(c.windows))
+	for i, w := range c.windows {
+		values := make([]float64, days)
+		var total float64
+		for d := range values {
+			day := w.From.AddDate(0, 0, d)
+			// Days past the end of the window or in the future aren't
+			// plotted.
+			if d >= w.Days() || day.After(now) {
+				values[d] = math.NaN()
+				continue
+			}
+			total += float64(r.stargazers[day.Format("2006-01-02")])
+			values[d] = total
+		}
+		series[i] = starsui.Series{Name: w.Name, Labels: labels, Values: values}
+		totals[i] = fmt.Sprintf("%s +%.0f", w.Name, total)
+	}
+	lower := 0.0
+	graph := starsui.AsciigraphRenderer{}.Render(series, starsui.RenderOptions{
+		Width:      r.graphWidth(),
+		Height:     r.height - 2,
+		Caption:    fmt.Sprintf("%s stars by day of window", r.name),
+		Colors:     r.theme.Colors(),
+		LowerBound: &lower,
+	})
+	return graph + "\n " + r.theme.Legend(totals)
+}
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/cli/go-gh/pkg/api"
+)
+
+const (
+	searchReposPath = "search/repositories?q=%s&per_page=%d"
+	// wizardResults is the number of suggested repositories.
+	wizardResults = 8
+	// searchDebounce is how long typing must pause before searching.
+	searchDebounce = 300 * time.Millisecond
+)
+
+type wizardStep int
+
+const (
+	stepRepos wizardStep = iota
+	stepWindow
+	stepCustomWindow
+	stepNormalize
+	stepOutput
+	stepOutputFile
+)
+
+// Compare output targets.
+const (
+	outputTUI  = "tui"
+	outputWeb  = "web"
+	outputFile = "file"
+)
+
+// wizardOption is a choice of a wizard step.
+type wizardOption struct {
+	label string
+	value string
+}
+
+// windowOptions returns the windows offered by the wizard, relative to now.
+func windowOptions(now time.Time) []wizardOption {
+	last := func(days int) string {
+		return now.AddDate(0, 0, 1-days).Format("2006-01-02") + ".." + now.Format("2006-01-02")
+	}
+	return []wizardOption{
+		{"All time", ""},
+		{"Last 30 days", last(30)},
+		{"Last 90 days", last(90)},
+		{"Last year", last(365)},
+		{"Custom quarter or range...", "custom"},
+	}
+}
+
+var normalizeOptions = []wizardOption{
+	{"Total stars", normalizeNone},
+	{"Stars gained in the window", normalizeGained},
+	{"Percent of the stars at the end of the window", normalizePercent},
+}
+
+var outputOptions = []wizardOption{
+	{"Show the graph", outputTUI},
+	{"Open the star-history.com chart in the browser", outputWeb},
+	{"Write a star-history.com compatible CSV file...", outputFile},
+}
+
+// searchMsg holds the repositories matching a query.
+type searchMsg struct {
+	id    int
+	repos []string
+	err   error
+}
+
+type searchDebounceMsg struct {
+	id int
+}
+
+// CompareWizard walks through picking the repositories and the options of a
+// comparison. Once done, its fields are the chosen options.
+type CompareWizard struct {
+	Repos     []string
+	Window    string
+	Normalize string
+	Output    string
+	File      string
+	// Done is set when all the steps are completed.
+	Done bool
+
+	step     wizardStep
+	input    textinput.Model
+	client   api.RESTClient
+	offline  bool
+	known    []string
+	results  []string
+	searchID int
+	cursor   int
+	err      error
+	opts     Options
+}
+
+// NewCompareWizard returns a wizard suggesting known repositories, the
+// cached ones and those of the dashboard list, and search results unless
+// offline.
+func NewCompareWizard(client api.RESTClient, known []string, opts Options) *CompareWizard {
+	input := textinput.New()
+	input.Placeholder = "owner/repo"
+	input.Focus()
+	w := &CompareWizard{
+		input:   input,
+		client:  client,
+		offline: opts.Offline,
+		known:   known,
+		opts:    opts,
+	}
+	w.results = w.matches("")
+	return w
+}
+
+// knownRepos returns the cached repositories and the dashboard list.
+func knownRepos(cfg *Config) []string {
+	known := append([]string{}, cfg.Dashboard...)
+	if cache, err := historyCache(); err == nil {
+		if names, err := cache.Names(); err == nil {
+			for _, name := range names {
+				if !containsFold(known, name) {
+					known = append(known, name)
+				}
+			}
+		}
+	}
+	sort.Strings(known)
+	return known
+}
+
+func (w *CompareWizard) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// matches returns the known repositories containing query, none for an
+// empty query.
+func (w *CompareWizard) matches(query string) []string {
+	results := make([]string, 0, wizardResults)
+	if query == "" {
+		return results
+	}
+	for _, name := range w.known {
+		if len(results) == wizardResults {
+			break
+		}
+		if strings.Contains(strings.ToLower(name), strings.ToLower(query)) && !containsFold(w.Repos, name) {
+			results = append(results, name)
+		}
+	}
+	return results
+}
+
+// search searches GitHub for repositories matching query.
+func (w *CompareWizard) search(id int, query string) tea.Cmd {
+	return func() tea.Msg {
+		var result struct {
+			Items []struct {
+				FullName string `json:"full_name"`
+			} `json:"items"`
+		}
+		err := w.client.Get(fmt.Sprintf(searchReposPath, url.QueryEscape(query), wizardResults), &result)
+		repos := make([]string, len(result.Items))
+		for i, item := range result.Items {
+			repos[i] = item.FullName
+		}
+		return searchMsg{id: id, repos: repos, err: err}
+	}
+}
+
+// options returns the choices of the current step.
+func (w *CompareWizard) options() []wizardOption {
+	switch w.step {
+	case stepRepos:
+		options := make([]wizardOption, len(w.results))
+		for i, name := range w.results {
+			options[i] = wizardOption{name, name}
+		}
+		return options
+	case stepWindow:
+		return windowOptions(time.Now())
+	case stepNormalize:
+		return normalizeOptions
+	case stepOutput:
+		return outputOptions
+	}
+	return nil
+}
+
+// setStep moves to a step, resetting the cursor and the input.
+func (w *CompareWizard) setStep(step wizardStep) {
+	w.step = step
+	w.cursor = 0
+	w.err = nil
+	w.input.Reset()
+	switch step {
+	case stepRepos:
+		w.input.Placeholder = "owner/repo"
+		w.results = w.matches("")
+	case stepCustomWindow:
+		w.input.Placeholder = "2024Q1 or 2024-01-01..2024-03-31"
+	case stepOutputFile:
+		w.input.Placeholder = "stars.csv"
+	}
+}
+
+func (w *CompareWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return w, tea.Quit
+		case "esc":
+			switch w.step {
+			case stepRepos:
+				return w, tea.Quit
+			case stepCustomWindow:
+				w.setStep(stepWindow)
+			case stepOutputFile:
+				w.setStep(stepOutput)
+			case stepNormalize:
+				w.setStep(stepWindow)
+			default:
+				w.setStep(w.step - 1)
+			}
+			return w, nil
+		case "up", "ctrl+p":
+			if w.cursor > 0 {
+				w.cursor--
+			}
+			return w, nil
+		case "down", "ctrl+n":
+			if w.cursor < len(w.options())-1 {
+				w.cursor++
+			}
+			return w, nil
+		case "enter":
+			return w, w.choose()
+		case "backspace":
+			if w.step == stepRepos && w.input.Value() == "" && len(w.Repos) > 0 {
+				w.Repos = w.Repos[:len(w.Repos)-1]
+				w.results = w.matches("")
+				return w, nil
+			}
+		}
+		if w.step != stepRepos && w.step != stepCustomWindow && w.step != stepOutputFile {
+			return w, nil
+		}
+		query := w.input.Value()
+		var cmd tea.Cmd
+		w.input, cmd = w.input.Update(msg)
+		if w.step != stepRepos || w.input.Value() == query {
+			return w, cmd
+		}
+		w.cursor = 0
+		w.results = w.matches(w.input.Value())
+		if w.offline || strings.TrimSpace(w.input.Value()) == "" {
+			return w, cmd
+		}
+		w.searchID++
+		id := w.searchID
+		return w, tea.Batch(cmd, tea.Tick(searchDebounce, func(time.Time) tea.Msg {
+			return searchDebounceMsg{id: id}
+		}))
+	case searchDebounceMsg:
+		if msg.id == w.searchID && w.step == stepRepos {
+			return w, w.search(msg.id, w.input.Value())
+		}
+	case searchMsg:
+		if msg.id != w.searchID || w.step != stepRepos {
+			return w, nil
+		}
+		w.err = msg.err
+		for _, name := range msg.repos {
+			if len(w.results) < 2*wizardResults && !containsFold(w.results, name) && !containsFold(w.Repos, name) {
+				w.results = append(w.results, name)
+			}
+		}
+	default:
+		var cmd tea.Cmd
+		w.input, cmd = w.input.Update(msg)
+		return w, cmd
+	}
+	return w, nil
+}
+
+// choose completes the current step with the selected option or the typed
+// value.
+func (w *CompareWizard) choose() tea.Cmd {
+	value := strings.TrimSpace(w.input.Value())
+	switch w.step {
+	case stepRepos:
+		switch {
+		case value == "" && len(w.Repos) >= 2:
+			w.setStep(stepWindow)
+			return nil
+		case value == "":
+			w.err = fmt.Errorf("pick at least two repositories")
+			return nil
+		case len(w.results) > 0:
+			w.Repos = append(w.Repos, w.results[w.cursor])
+		case strings.Count(value, "/") == 1:
+			w.Repos = append(w.Repos, value)
+		default:
+			w.err = fmt.Errorf("no repository matches %q", value)
+			return nil
+		}
+		w.input.Reset()
+		w.cursor = 0
+		w.err = nil
+		w.results = w.matches("")
+	case stepWindow:
+		w.Window = w.options()[w.cursor].value
+		if w.Window == "custom" {
+			w.setStep(stepCustomWindow)
+			return nil
+		}
+		w.setStep(stepNormalize)
+	case stepCustomWindow:
+		if _, err := ParseWindow(value); err != nil {
+			w.err = err
+			return nil
+		}
+		w.Window = value
+		w.setStep(stepNormalize)
+	case stepNormalize:
+		w.Normalize = w.options()[w.cursor].value
+		w.setStep(stepOutput)
+	case stepOutput:
+		w.Output = w.options()[w.cursor].value
+		if w.Output == outputFile {
+			w.setStep(stepOutputFile)
+			return nil
+		}
+		w.Done = true
+		return tea.Quit
+	case stepOutputFile:
+		if value == "" {
+			value = w.input.Placeholder
+		}
+		w.File = value
+		w.Done = true
+		return tea.Quit
+	}
+	return nil
+}
+
+func (w *CompareWizard) View() string {
+	v := w.render()
+	if w.opts.ASCII {
+		v = asciiReplacer.Replace(v)
+	}
+	return v
+}
+
+func (w *CompareWizard) render() string {
+	accent := w.opts.Theme.AccentStyle()
+	lines := []string{""}
+	var help string
+	switch w.step {
+	case stepRepos:
+		lines = append(lines, " Repositories to compare: "+accent.Render(strings.Join(w.Repos, ", ")), "")
+		lines = append(lines, " "+w.input.View(), "")
+		help = "type to search • ↑/↓ select • enter add • backspace remove the last one • esc quit"
+		if len(w.Repos) >= 2 {
+			help += " • enter with an empty search to continue"
+		}
+	case stepWindow:
+		lines = append(lines, " Period to compare", "")
+		help = "↑/↓ select • enter continue • esc back"
+	case stepCustomWindow:
+		lines = append(lines, " Quarter or date range to compare", "", " "+w.input.View())
+		help = "enter continue • esc back"
+	case stepNormalize:
+		lines = append(lines, " Stars to plot", "")
+		help = "↑/↓ select • enter continue • esc back"
+	case stepOutput:
+		lines = append(lines, " Output", "")
+		help = "↑/↓ select • enter compare • esc back"
+	case stepOutputFile:
+		lines = append(lines, " CSV file to write", "", " "+w.input.View())
+		help = "enter write • esc back"
+	}
+	for i, option := range w.options() {
+		if i == w.cursor {
+			lines = append(lines, accent.Render(" > "+option.label))
+			continue
+		}
+		lines = append(lines, "   "+option.label)
+	}
+	if w.err != nil {
+		lines = append(lines, "", fmt.Sprintf(" Error: %s", w.err))
+	}
+	lines = append(lines, "", " "+help)
+	return strings.Join(lines, "\n")
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/gh-stars/pkg/stars"
+	"github.com/cli/go-gh/pkg/api"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// Workspace is a set of report jobs read from a workspace file, such as
+// stars.yaml, to version-control a reporting pipeline.
+type Workspace struct {
+	Jobs []Job `yaml:"jobs"`
+}
+
+// Job renders the stargazers of repositories in a format and delivers them
+// to its destinations, stdout if none is set.
+type Job struct {
+	Name  string   `yaml:"name"`
+	Repos []string `yaml:"repos"`
+	// Format is one of jobFormats, report by default.
+	Format string `yaml:"format"`
+	// Windows are the periods of the diff format, as quarters or ranges.
+	Windows []string `yaml:"windows"`
+	// Output is the file to write, {repo} is replaced by the repository.
+	Output string `yaml:"output"`
+	// Issue is the repository whose pinned issue the report is posted to,
+	// {repo} is replaced by the reported repository.
+	Issue string `yaml:"issue"`
+	// Gist uploads the output to a gist, public if Public is set.
+	Gist   bool `yaml:"gist"`
+	Public bool `yaml:"public"`
+}
+
+// jobFormats are the formats of job outputs.
+var jobFormats = []string{"report", "csv", "json", "ipynb", "diff"}
+
+// LoadWorkspace reads and checks a workspace file.
+func LoadWorkspace(path string) (*Workspace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ws Workspace
+	if err := yaml.Unmarshal(data, &ws); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(ws.Jobs) == 0 {
+		return nil, fmt.Errorf("%s: no jobs", path)
+	}
+	for i := range ws.Jobs {
+		job := &ws.Jobs[i]
+		if job.Name == "" {
+			job.Name = fmt.Sprintf("job %d", i+1)
+		}
+		if job.Format == "" {
+			job.Format = "report"
+		}
+		if err := job.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", path, job.Name, err)
+		}
+	}
+	return &ws, nil
+}
+
+func (j *Job) validate() error {
+	if len(j.Repos) == 0 {
+		return fmt.Errorf("no repositories")
+	}
+	if !contains(jobFormats, j.Format) {
+		return fmt.Errorf("unknown format %q, expected %s", j.Format, strings.Join(jobFormats, ", "))
+	}
+	if _, err := ParseWindows(j.Windows); err != nil {
+		return err
+	}
+	if j.Format == "diff" && len(j.Windows) == 0 {
+		return fmt.Errorf("the diff format needs windows")
+	}
+	if j.Issue != "" && j.Format != "report" {
+		return fmt.Errorf("only reports can be posted to an issue")
+	}
+	return nil
+}
+
+// render renders the stargazers of entry in the format of the job.
+func (j *Job) render(w io.Writer, entry *stars.History) error {
+	switch j.Format {
+	case "csv":
+		return ExportStarHistoryCSV(w, entry)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entry)
+	case "ipynb":
+		return ExportNotebook(w, entry)
+	case "diff":
+		windows, err := ParseWindows(j.Windows)
+		if err != nil {
+			return err
+		}
+		for _, window := range windows {
+			if err := WriteDiff(w, NewDiff(entry, window.From, window.To.AddDate(0, 0, 1), 5)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		_, err := io.WriteString(w, Report(entry, time.Now()))
+		return err
+	}
+}
+
+// Run runs the job for each repository, logging the destinations written.
+func (j *Job) Run(client api.RESTClient) error {
+	for _, repo := range j.Repos {
+		entry, err := LoadHistory(client, repo, 0, false)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := j.render(&buf, entry); err != nil {
+			return err
+		}
+		delivered := false
+		if j.Output != "" {
+			path := strings.ReplaceAll(j.Output, "{repo}", entry.Name)
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "%s: wrote %s\n", j.Name, path)
+			delivered = true
+		}
+		if j.Issue != "" {
+			issue, err := postReport(client, strings.ReplaceAll(j.Issue, "{repo}", entry.Name), buf.String())
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "%s: posted %s\n", j.Name, issue.HTMLURL)
+			delivered = true
+		}
+		if j.Gist {
+			gist, err := PublishGist(client, j.filename(entry.Name),
+				fmt.Sprintf("%s stargazers %s", entry.Name, j.Format), buf.String(), j.Public)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "%s: uploaded %s\n", j.Name, gist.HTMLURL)
+			delivered = true
+		}
+		if !delivered {
+			if _, err := os.Stdout.Write(buf.Bytes()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// filename returns the gist file name of the output of a repository.
+func (j *Job) filename(repo string) string {
+	ext := map[string]string{"report": "md", "diff": "txt"}[j.Format]
+	if ext == "" {
+		ext = j.Format
+	}
+	return fmt.Sprintf("%s.%s", strings.ReplaceAll(repo, "/", "-"), ext)
+}
+
+func runWorkspace(args []string) {
+	flags := pflag.NewFlagSet("run", pflag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: gh stars run [workspace file] [flags]\n\n")
+		fmt.Fprintf(os.Stderr, "Run the report jobs of a workspace file, stars.yaml by default.\n\n")
+		flags.PrintDefaults()
+	}
+	only := flags.StringSlice("job", nil, "names of the jobs to run, all by default")
+	addTokenFlag(flags)
+	addMaxRequestsFlag(flags)
+	flags.Parse(args)
+	path := flags.Arg(0)
+	if path == "" {
+		path = "stars.yaml"
+	}
+	ws, err := LoadWorkspace(path)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	client, err := NewClient()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	ran, failed := 0, 0
+	for i := range ws.Jobs {
+		job := &ws.Jobs[i]
+		if len(*only) > 0 && !contains(*only, job.Name) {
+			continue
+		}
+		ran++
+		if err := job.Run(client); err != nil {
+			log.Printf("%s: %v", job.Name, err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		log.Fatalf("%d of %d jobs failed", failed, ran)
+	}
+}